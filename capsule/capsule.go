@@ -55,6 +55,15 @@ func Query() query.Query {
 	return Global.Query()
 }
 
+// Transaction Execute a Closure within a transaction using the global manager.
+func Transaction(callback func(tx query.Query) error) error {
+	if Global == nil {
+		err := errors.New("the global capsule not set")
+		panic(err)
+	}
+	return Global.Transaction(callback)
+}
+
 // ************************************************************
 // THE FOLLOWING LINES WILL BE DEPRECATED
 // ************************************************************
@@ -95,6 +95,16 @@ func (manager *Manager) Query() query.Query {
 		})
 }
 
+// Transaction Execute a Closure within a transaction on the primary connection.
+func (manager *Manager) Transaction(callback func(tx query.Query) error) error {
+	return manager.Query().Transaction(callback)
+}
+
+// BeginTransaction Start a new database transaction on the primary connection, use the Commit and Rollback methods of the returned query to finish it.
+func (manager *Manager) BeginTransaction() (query.Query, error) {
+	return manager.Query().BeginTransaction()
+}
+
 // Close the connections
 func (manager *Manager) Close() error {
 
@@ -11,6 +11,7 @@ var Grammars = map[string]Grammar{}
 
 // BindingKeys the binding key orders
 var BindingKeys = []string{
+	"with",
 	"select", "from", "join", "where",
 	"groupBy", "having",
 	"order",
@@ -51,15 +52,19 @@ func NewConstraint(schemaName string, tableName string, columnName string) *Cons
 // NewTable make a grammar table
 func NewTable(name string, schemaName string, dbName string) *Table {
 	return &Table{
-		DBName:     dbName,
-		SchemaName: schemaName,
-		TableName:  name,
-		Primary:    nil,
-		Columns:    []*Column{},
-		ColumnMap:  map[string]*Column{},
-		Indexes:    []*Index{},
-		IndexMap:   map[string]*Index{},
-		Commands:   []*Command{},
+		DBName:        dbName,
+		SchemaName:    schemaName,
+		TableName:     name,
+		Primary:       nil,
+		Columns:       []*Column{},
+		ColumnMap:     map[string]*Column{},
+		Indexes:       []*Index{},
+		IndexMap:      map[string]*Index{},
+		Foreigns:      []*Foreign{},
+		ForeignMap:    map[string]*Foreign{},
+		Constraints:   []*Constraint{},
+		ConstraintMap: map[string]*Constraint{},
+		Commands:      []*Command{},
 	}
 }
 
@@ -96,6 +101,7 @@ func NewQuery() *Query {
 		UnionOffset:        -1,
 		BindingOffset:      0,
 		Bindings: map[string][]interface{}{
+			"with":       {},
 			"select":     {},
 			"from":       {},
 			"join":       {},
@@ -133,6 +139,34 @@ func IsExpression(value interface{}) bool {
 	}
 }
 
+// IsJSONSelector Determine if the given value is a JSON selector. eg: options->language
+func IsJSONSelector(value interface{}) bool {
+	switch value.(type) {
+	case string:
+		return strings.Contains(value.(string), "->")
+	default:
+		return false
+	}
+}
+
+// ParseJSONSelector Split the given JSON selector into the field and the path segments. eg: options->dining->>meal
+// The unquote is true if the last operator is ->>, the selector returns the unquoted text instead of the JSON value.
+func ParseJSONSelector(value string) (string, []string, bool) {
+	parts := strings.Split(value, "->")
+	field := strings.Trim(parts[0], " ")
+	path := []string{}
+	unquote := false
+	for _, segment := range parts[1:] {
+		unquote = strings.HasPrefix(segment, ">")
+		segment = strings.TrimPrefix(segment, ">")
+		segment = strings.Trim(segment, " \"'")
+		if segment != "" {
+			path = append(path, segment)
+		}
+	}
+	return field, path, unquote
+}
+
 // NewPrimary create a new primary intstance
 func (table *Table) NewPrimary(name string, columns ...*Column) *Primary {
 	return &Primary{
@@ -217,6 +251,67 @@ func (table *Table) GetIndex(name string) *Index {
 	return table.IndexMap[name]
 }
 
+// NewForeign create a new foreign key intstance
+func (table *Table) NewForeign(name string, columns ...string) *Foreign {
+	return &Foreign{
+		DBName:            table.DBName,
+		TableName:         table.TableName,
+		Table:             table,
+		Name:              name,
+		Columns:           columns,
+		ReferencedColumns: []string{},
+	}
+}
+
+// PushForeign push a foreign key instance to the table foreign keys
+func (table *Table) PushForeign(foreign *Foreign) *Table {
+	table.ForeignMap[foreign.Name] = foreign
+	table.Foreigns = append(table.Foreigns, foreign)
+	return table
+}
+
+// HasForeign checking if the given name foreign key exists
+func (table *Table) HasForeign(name string) bool {
+	_, has := table.ForeignMap[name]
+	return has
+}
+
+// GetForeign get the given name foreign key instance
+func (table *Table) GetForeign(name string) *Foreign {
+	return table.ForeignMap[name]
+}
+
+// NewTableConstraint create a new table constraint intstance, the type should be UNIQUE or CHECK
+func (table *Table) NewTableConstraint(name string, typ string) *Constraint {
+	return &Constraint{
+		SchemaName: table.SchemaName,
+		TableName:  table.TableName,
+		Table:      table,
+		Name:       name,
+		Type:       typ,
+		Args:       []string{},
+		Columns:    []string{},
+	}
+}
+
+// PushConstraint push a constraint instance to the table constraints
+func (table *Table) PushConstraint(constraint *Constraint) *Table {
+	table.ConstraintMap[constraint.Name] = constraint
+	table.Constraints = append(table.Constraints, constraint)
+	return table
+}
+
+// HasConstraint checking if the given name constraint exists
+func (table *Table) HasConstraint(name string) bool {
+	_, has := table.ConstraintMap[name]
+	return has
+}
+
+// GetConstraint get the given name constraint instance
+func (table *Table) GetConstraint(name string) *Constraint {
+	return table.ConstraintMap[name]
+}
+
 // AddCommand Add a new command to the table.
 //
 // The commands must be:
@@ -227,6 +322,10 @@ func (table *Table) GetIndex(name string) *Index {
 //    CreateIndex(index *Index) for creating a index
 //    DropIndex( name string) for  dropping a index
 //    RenameIndex(old string,new string)  for renaming a index
+//    CreateForeign(foreign *Foreign) for creating a foreign key
+//    DropForeign(name string) for dropping a foreign key
+//    CreateConstraint(constraint *Constraint) for creating a unique or check constraint
+//    DropConstraint(name string, typ string) for dropping a unique or check constraint
 func (table *Table) AddCommand(name string, success func(), fail func(), params ...interface{}) {
 	table.Commands = append(table.Commands, &Command{
 		Name:    name,
@@ -255,6 +354,13 @@ func (index *Index) AddColumn(column *Column) {
 	index.Columns = append(index.Columns, column)
 }
 
+// AddColumn add a column and the referenced column to the foreign key
+func (foreign *Foreign) AddColumn(name string, referencedName string) *Foreign {
+	foreign.Columns = append(foreign.Columns, name)
+	foreign.ReferencedColumns = append(foreign.ReferencedColumns, referencedName)
+	return foreign
+}
+
 // Fullname get the name name with prefix
 func (name Name) Fullname() string {
 	return fmt.Sprintf("%s%s", name.Prefix, name.Name)
@@ -275,6 +381,31 @@ func (expression Expression) GetValue() string {
 	return fmt.Sprintf("%v", expression.Value)
 }
 
+// HasWindowFunctions Determine if the query selects the window functions or defines the named windows.
+func (query *Query) HasWindowFunctions() bool {
+	if len(query.Windows) > 0 {
+		return true
+	}
+	for _, column := range query.Columns {
+		if _, ok := column.(WindowFunction); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRandomSeed Determine if the query orders the results in random order with a seed.
+func (query *Query) HasRandomSeed() bool {
+	orders := append([]Order{}, query.Orders...)
+	orders = append(orders, query.UnionOrders...)
+	for _, order := range orders {
+		if order.Type == "random" && order.Value != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // Clone clone the query instance
 func (query *Query) Clone() *Query {
 
@@ -286,6 +417,7 @@ func (query *Query) Clone() *Query {
 		Aggregate:          query.CopyAggregate(),       // An aggregate function and column to be run.
 		Wheres:             query.CopyWheres(),          // The where constraints for the query.
 		Joins:              query.CopyJoins(),           // The table joins for the query.
+		CTEs:               query.CopyCTEs(),            // The common table expressions of the query.
 		Unions:             query.CopyUnions(),          // The query union statements.
 		UnionLimit:         query.UnionLimit,            // The maximum number of union records to return.
 		UnionOffset:        query.UnionOffset,           // The number of union records to skip.
@@ -295,6 +427,8 @@ func (query *Query) Clone() *Query {
 		Offset:             query.Offset,                // The number of records to skip.
 		Groups:             query.CopyGroups(),          // The groupings for the query.
 		Havings:            query.CopyHavings(),         // The having constraints for the query.
+		Windows:            query.CopyWindows(),         // The named window definitions for the query.
+		Returning:          query.CopyReturning(),       // The columns that should be returned by the insert, update and delete statements.
 		Bindings:           query.CopyBindings(),        // The current query value bindings.
 		Distinct:           query.Distinct,              // Indicates if the query returns distinct results. Occasionally contains the columns that should be distinct. default is false
 		DistinctColumns:    query.CopyDistinctColumns(), // Indicates if the query returns distinct results. Occasionally contains the columns that should be distinct.
@@ -386,6 +520,33 @@ func (query *Query) CopyUnions() []Union {
 	return new
 }
 
+// CopyCTEs copy CTEs
+func (query *Query) CopyCTEs() []CTE {
+	new := []CTE{}
+	for _, cte := range query.CTEs {
+		new = append(new, cte)
+	}
+	return new
+}
+
+// CopyWindows copy Windows
+func (query *Query) CopyWindows() []Window {
+	new := []Window{}
+	for _, window := range query.Windows {
+		new = append(new, window)
+	}
+	return new
+}
+
+// CopyReturning copy Returning
+func (query *Query) CopyReturning() []interface{} {
+	new := []interface{}{}
+	for _, column := range query.Returning {
+		new = append(new, column)
+	}
+	return new
+}
+
 // CopyUnionOrders copy UnionOrders
 func (query *Query) CopyUnionOrders() []Order {
 	new := []Order{}
@@ -473,3 +634,31 @@ func (query *Query) GetBindings(key ...string) []interface{} {
 	}
 	return bindings
 }
+
+// FullScans Get the nodes of the plan which scan the whole table
+func (plan *Plan) FullScans() []*PlanNode {
+	nodes := []*PlanNode{}
+	plan.Walk(func(node *PlanNode) {
+		if node.FullScan {
+			nodes = append(nodes, node)
+		}
+	})
+	return nodes
+}
+
+// HasFullScan Determine if the plan scans any table without using index
+func (plan *Plan) HasFullScan() bool {
+	return len(plan.FullScans()) > 0
+}
+
+// Walk Traverse the nodes of the plan depth-first
+func (plan *Plan) Walk(callback func(node *PlanNode)) {
+	var walk func(nodes []*PlanNode)
+	walk = func(nodes []*PlanNode) {
+		for _, node := range nodes {
+			callback(node)
+			walk(node.Children)
+		}
+	}
+	walk(plan.Nodes)
+}
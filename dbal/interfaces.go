@@ -1,6 +1,8 @@
 package dbal
 
 import (
+	"context"
+
 	"github.com/jmoiron/sqlx"
 )
 
@@ -13,11 +15,14 @@ type Grammar interface {
 	WrapTable(value interface{}) string
 
 	OnConnected() error
+	WithContext(ctx context.Context) Grammar
+	WithTransaction(tx *sqlx.Tx) Grammar
 
 	GetVersion() (*Version, error)
 	GetDatabase() string
 	GetSchema() string
 	GetOperators() []string
+	GetMaxPlaceholders() int
 
 	// Grammar for migrating
 	GetTables() ([]string, error)
@@ -38,13 +43,34 @@ type Grammar interface {
 	CompileInsertUsing(query *Query, columns []interface{}, sql string) string
 	CompileUpsert(query *Query, columns []interface{}, values [][]interface{}, uniqueBy []interface{}, updateValues interface{}) (string, []interface{})
 	CompileUpdate(query *Query, values map[string]interface{}) (string, []interface{})
+	CompileUpdateBatch(query *Query, key string, columns []interface{}, values [][]interface{}) (string, []interface{})
 	CompileDelete(query *Query) (string, []interface{})
 	CompileTruncate(query *Query) ([]string, [][]interface{})
 	CompileSelect(query *Query) string
 	CompileSelectOffset(query *Query, offset *int) string
+	CompileWheres(query *Query, wheres []Where, offset *int) string
+	CompileJSONUpdateColumn(column string, selectors []string, values map[string]interface{}, offset *int) (string, []interface{})
 	CompileExists(query *Query) string
+	CompileReturning(query *Query, columns []interface{}) (string, error)
+	CompileRandom(seed string) string
+	CompileExplain(sql string, options map[string]interface{}) string
+	CompileFullTextOrder(query *Query, fulltext FullText, value interface{}, offset int) (string, []interface{})
+	CompileSavepoint(name string) string
+	CompileSavepointRollBack(name string) string
+	CompileSavepointRelease(name string) string
+	Interpolate(sql string, bindings []interface{}) string
+	CheckQuery(query *Query) error
+
+	ProcessInsertGetID(db Executor, sql string, bindings []interface{}, sequence string) (int64, error)
+	ProcessExplain(rows []map[string]interface{}, options map[string]interface{}) (*Plan, error)
+}
 
-	ProcessInsertGetID(sql string, bindings []interface{}, sequence string) (int64, error)
+// Executor the statement executor interface, both of the *sqlx.DB and *sqlx.Tx implement it.
+type Executor interface {
+	sqlx.ExtContext
+	sqlx.PreparerContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 }
 
 // Quoter the database quoting query text intrface
@@ -53,6 +79,7 @@ type Quoter interface {
 	ID(value string) string
 	VAL(value interface{}) string // operates on both string and []byte and int or other types.
 	Wrap(value interface{}) string
+	WrapJSONFieldAndPath(value string) (string, string)
 	WrapTable(value interface{}) string
 	WrapUnion(sql string) string
 	IsExpression(value interface{}) bool
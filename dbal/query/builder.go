@@ -1,6 +1,7 @@
 package query
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
@@ -109,6 +110,7 @@ func useBuilder(conn *Connection) *Builder {
 		Database: grammar.GetDatabase(),
 		Schema:   grammar.GetSchema(),
 		Query:    dbal.NewQuery(),
+		Context:  context.Background(),
 	}
 }
 
@@ -1,6 +1,11 @@
 package query
 
-import "github.com/jmoiron/sqlx"
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/yaoapp/xun/dbal"
+)
 
 // DB Get the sqlx.DB pointer instance
 func (builder *Builder) DB(usewrite ...bool) *sqlx.DB {
@@ -10,6 +15,33 @@ func (builder *Builder) DB(usewrite ...bool) *sqlx.DB {
 	return builder.Conn.Read
 }
 
+// executor Get the statement executor, returns the transaction if the builder is in a transaction.
+func (builder *Builder) executor(usewrite ...bool) dbal.Executor {
+	if builder.Tx != nil {
+		return builder.Tx.Tx
+	}
+	return builder.DB(usewrite...)
+}
+
+// WithContext create a new query builder instance, the statements of it will be executed using the given context.
+func (builder *Builder) WithContext(ctx context.Context) Query {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	new := builder.clone()
+	new.Context = ctx
+	new.Grammar = builder.Grammar.WithContext(ctx)
+	return new
+}
+
+// GetContext Get the context of the query.
+func (builder *Builder) GetContext() context.Context {
+	if builder.Context == nil {
+		return context.Background()
+	}
+	return builder.Context
+}
+
 // UseWrite Use the write connection for query.
 func (builder *Builder) UseWrite() Query {
 	builder.Query.UseWriteConnection = true
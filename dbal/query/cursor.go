@@ -0,0 +1,190 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/utils"
+)
+
+// Cursor Execute the query as a "select" statement and get a lazy iterator of the results.
+// The rows are read from the database one at a time, the cursor must be closed when it is not fully iterated.
+func (builder *Builder) Cursor() (*Cursor, error) {
+	err := builder.Grammar.CheckQuery(builder.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := builder.ToSQL()
+	bindings := builder.GetBindings()
+	defer log.With(log.F{"bindings": bindings}).Debug(sql)
+
+	stmt, err := builder.executor().PrepareContext(builder.GetContext(), sql)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(builder.GetContext(), bindings...)
+	if err != nil {
+		stmt.Close()
+		return nil, err
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		stmt.Close()
+		return nil, err
+	}
+
+	return &Cursor{builder: builder, stmt: stmt, rows: rows, columns: columns}, nil
+}
+
+// MustCursor Execute the query as a "select" statement and get a lazy iterator of the results.
+func (builder *Builder) MustCursor() *Cursor {
+	cursor, err := builder.Cursor()
+	utils.PanicIF(err)
+	return cursor
+}
+
+// Next Prepare the next row for reading with the Row or Scan method.
+// It returns false when there are no more rows or an error occurred, and the cursor will be closed.
+func (cursor *Cursor) Next() bool {
+	if cursor.closed {
+		return false
+	}
+
+	if cursor.rows.Next() {
+		return true
+	}
+
+	cursor.err = cursor.rows.Err()
+	cursor.Close()
+	return false
+}
+
+// Row Get the current row as a xun.R
+func (cursor *Cursor) Row() (xun.R, error) {
+	if cursor.closed {
+		return nil, fmt.Errorf("the cursor is closed")
+	}
+
+	values := cursor.builder.makeMapValues(len(cursor.columns))
+	if err := cursor.rows.Scan(values...); err != nil {
+		cursor.err = err
+		return nil, err
+	}
+
+	row := xun.R{}
+	for i, column := range cursor.columns {
+		row[column] = cursor.builder.getValue(values[i])
+	}
+	return row, nil
+}
+
+// MustRow Get the current row as a xun.R
+func (cursor *Cursor) MustRow() xun.R {
+	row, err := cursor.Row()
+	utils.PanicIF(err)
+	return row
+}
+
+// Scan Scan the current row into the given pointer. The pointer could be a struct pointer or a scalar value pointer.
+func (cursor *Cursor) Scan(v interface{}) error {
+	if cursor.closed {
+		return fmt.Errorf("the cursor is closed")
+	}
+
+	structType, vStruct, err := cursor.builder.getStructType(v)
+	if err != nil {
+		return err
+	}
+
+	if !vStruct {
+		err = cursor.rows.Scan(v)
+		if err != nil {
+			cursor.err = err
+		}
+		return err
+	}
+
+	fieldMap, err := cursor.builder.getFieldMap(structType)
+	if err != nil {
+		return err
+	}
+
+	dest := reflect.New(structType)
+	values, err := cursor.builder.makeStructValues(dest, fieldMap, cursor.columns)
+	if err != nil {
+		return err
+	}
+
+	if err := cursor.rows.Scan(values...); err != nil {
+		cursor.err = err
+		return err
+	}
+
+	reflect.ValueOf(v).Elem().Set(reflect.Indirect(dest))
+	return nil
+}
+
+// MustScan Scan the current row into the given pointer.
+func (cursor *Cursor) MustScan(v interface{}) {
+	err := cursor.Scan(v)
+	utils.PanicIF(err)
+}
+
+// Each Execute a callback over each row of the cursor, the cursor will be closed when the iteration is finished.
+// If the binding var is given, the rows will be scanned into the struct and the callback receives the struct value.
+func (cursor *Cursor) Each(callback func(item interface{}, index int) error, v ...interface{}) error {
+	defer cursor.Close()
+	index := 0
+	for cursor.Next() {
+		var item interface{}
+		if len(v) > 0 && v[0] != nil {
+			if err := cursor.Scan(v[0]); err != nil {
+				return err
+			}
+			item = reflect.Indirect(reflect.ValueOf(v[0])).Interface()
+		} else {
+			row, err := cursor.Row()
+			if err != nil {
+				return err
+			}
+			item = row
+		}
+
+		if err := callback(item, index); err != nil {
+			return err
+		}
+		index++
+	}
+	return cursor.Err()
+}
+
+// Columns Get the column names of the results.
+func (cursor *Cursor) Columns() []string {
+	return cursor.columns
+}
+
+// Err Get the error, if any, that was encountered during iteration.
+func (cursor *Cursor) Err() error {
+	return cursor.err
+}
+
+// Close Close the cursor and release the database connection, it could be called more than once.
+func (cursor *Cursor) Close() error {
+	if cursor.closed {
+		return nil
+	}
+	cursor.closed = true
+
+	err := cursor.rows.Close()
+	errStmt := cursor.stmt.Close()
+	if err != nil {
+		return err
+	}
+	return errStmt
+}
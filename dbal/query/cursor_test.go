@@ -0,0 +1,161 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestCursorNextRow(t *testing.T) {
+	NewTableForCursorTest()
+	qb := getTestBuilder()
+	cursor := qb.Table("table_test_cursor").
+		Where("vote", ">", 5).
+		OrderBy("id").
+		MustCursor()
+	defer cursor.Close()
+
+	assert.Equal(t, []string{"id", "email", "vote"}, cursor.Columns(), "the columns should be equal")
+
+	emails := []string{}
+	for cursor.Next() {
+		row := cursor.MustRow()
+		emails = append(emails, row.Get("email").(string))
+	}
+	assert.Nil(t, cursor.Err(), "the cursor should not have errors")
+	assert.Equal(t, []string{"john@yao.run", "ken@yao.run", "ben@yao.run"}, emails, "the emails should be equal")
+	assert.False(t, cursor.Next(), "the cursor should be closed")
+}
+
+func TestCursorScan(t *testing.T) {
+	type Item struct {
+		ID    int64
+		Email string
+		Score int `json:"vote"`
+	}
+
+	NewTableForCursorTest()
+	qb := getTestBuilder()
+	cursor := qb.Table("table_test_cursor").OrderBy("id").MustCursor()
+	defer cursor.Close()
+
+	items := []Item{}
+	for cursor.Next() {
+		item := Item{}
+		cursor.MustScan(&item)
+		items = append(items, item)
+	}
+	assert.Equal(t, 4, len(items), "the cursor should yield 4 items")
+	assert.Equal(t, Item{ID: 3, Email: "ken@yao.run", Score: 125}, items[2], "the 3rd item should be ken")
+
+	var total int64
+	cursor = qb.Table("table_test_cursor").Select("id").OrderBy("id").Limit(1).MustCursor()
+	for cursor.Next() {
+		cursor.MustScan(&total)
+	}
+	assert.Equal(t, int64(1), total, "the scalar value should be scanned")
+}
+
+func TestCursorEach(t *testing.T) {
+	type Item struct {
+		ID    int64
+		Email string
+		Vote  int
+	}
+
+	NewTableForCursorTest()
+	qb := getTestBuilder()
+	emails := []string{}
+	err := qb.Table("table_test_cursor").OrderByDesc("id").MustCursor().Each(func(item interface{}, index int) error {
+		emails = append(emails, item.(xun.R).Get("email").(string))
+		return nil
+	})
+	assert.Nil(t, err, "the each method should not return errors")
+	assert.Equal(t, []string{"ben@yao.run", "ken@yao.run", "lee@yao.run", "john@yao.run"}, emails, "the emails should be equal")
+
+	votes := []int{}
+	err = qb.Table("table_test_cursor").OrderBy("id").MustCursor().Each(func(item interface{}, index int) error {
+		votes = append(votes, item.(Item).Vote)
+		if index == 1 {
+			return fmt.Errorf("stop")
+		}
+		return nil
+	}, &Item{})
+	assert.Equal(t, "stop", err.Error(), "the error of the callback should be returned")
+	assert.Equal(t, []int{10, 5}, votes, "the votes should be equal")
+}
+
+func TestCursorClose(t *testing.T) {
+	NewTableForCursorTest()
+	qb := getTestBuilder()
+	cursor := qb.Table("table_test_cursor").OrderBy("id").MustCursor()
+	assert.True(t, cursor.Next(), "the cursor should have rows")
+	assert.Nil(t, cursor.Close(), "the cursor should be closed")
+	assert.Nil(t, cursor.Close(), "the cursor could be closed more than once")
+	assert.False(t, cursor.Next(), "the cursor should be closed")
+
+	_, err := cursor.Row()
+	assert.Equal(t, "the cursor is closed", err.Error(), "the error should be returned")
+	assert.Panics(t, func() { cursor.MustScan(&struct{}{}) })
+
+	// the connection should be released
+	assert.Equal(t, int64(4), qb.Table("table_test_cursor").MustCount(), "The rows count should be 4")
+}
+
+func TestCursorError(t *testing.T) {
+	NewTableForCursorTest()
+	qb := getTestBuilder()
+	_, err := qb.Table("table_test_cursor_not_exists").Cursor()
+	assert.NotNil(t, err, "the cursor should return an error")
+
+	cursor := qb.Table("table_test_cursor").Select("id").MustCursor()
+	defer cursor.Close()
+	cursor.Next()
+	err = cursor.Scan(&struct{ Email string }{})
+	assert.NotNil(t, err, "the scan should return an error")
+}
+
+func TestCursorInTransaction(t *testing.T) {
+	NewTableForCursorTest()
+	qb := getTestBuilder()
+	err := qb.Transaction(func(tx Query) error {
+		tx.Table("table_test_cursor").MustInsert(xun.R{"email": "max@yao.run", "vote": 1})
+		hits := 0
+		err := tx.Table("table_test_cursor").MustCursor().Each(func(item interface{}, index int) error {
+			hits++
+			return nil
+		})
+		assert.Equal(t, 5, hits, "the cursor should read the rows of the transaction")
+		return err
+	})
+	assert.Nil(t, err, "the transaction should be committed")
+}
+
+// clean the test data
+func TestCursorClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_cursor")
+}
+
+func NewTableForCursorTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_cursor")
+	builder.MustCreateTable("table_test_cursor", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.Integer("vote")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_cursor").Insert([]xun.R{
+		{"email": "john@yao.run", "vote": 10},
+		{"email": "lee@yao.run", "vote": 5},
+		{"email": "ken@yao.run", "vote": 125},
+		{"email": "ben@yao.run", "vote": 6},
+	})
+}
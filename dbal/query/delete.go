@@ -2,15 +2,21 @@ package query
 
 import (
 	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/utils"
 )
 
 // Delete Delete records from the database.
 func (builder *Builder) Delete() (int64, error) {
+	err := builder.Grammar.CheckQuery(builder.Query)
+	if err != nil {
+		return 0, err
+	}
+
 	sql, bindings := builder.Grammar.CompileDelete(builder.Query)
 	defer log.With(log.F{"bindings": bindings}).Debug(sql)
 
-	res, err := builder.UseWrite().DB().Exec(sql, bindings...)
+	res, err := builder.executor(true).ExecContext(builder.GetContext(), sql, bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -25,12 +31,30 @@ func (builder *Builder) MustDelete() int64 {
 	return affected
 }
 
+// DeleteReturning Delete records from the database and get the rows returned by the "returning" clause. (PostgreSQL, SQLite 3.35.0+)
+func (builder *Builder) DeleteReturning() ([]xun.R, error) {
+	err := builder.Grammar.CheckQuery(builder.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, bindings := builder.Grammar.CompileDelete(builder.Query)
+	return builder.execReturning(sql, bindings)
+}
+
+// MustDeleteReturning Delete records from the database and get the rows returned by the "returning" clause.
+func (builder *Builder) MustDeleteReturning() []xun.R {
+	rows, err := builder.DeleteReturning()
+	utils.PanicIF(err)
+	return rows
+}
+
 // Truncate Run a truncate statement on the table.
 func (builder *Builder) Truncate() error {
 	sqls, bindings := builder.Grammar.CompileTruncate(builder.Query)
 	for i, sql := range sqls {
 		defer log.With(log.F{"bindings": bindings}).Debug(sql)
-		_, err := builder.UseWrite().DB().Exec(sql, bindings[i]...)
+		_, err := builder.executor(true).ExecContext(builder.GetContext(), sql, bindings[i]...)
 		if err != nil {
 			return err
 		}
@@ -45,6 +45,46 @@ func TestDeleteMustDeleteWithJoin(t *testing.T) {
 	assert.Equal(t, int64(2), affected, "The affected rows should be 2")
 }
 
+func TestDeleteMustDeleteWithInnerJoin(t *testing.T) {
+	NewTableForDeleteTest()
+	NewTableForDeleteLogTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_delete").
+		Join("table_test_delete_log", "table_test_delete_log.user_id", "=", "table_test_delete.id").
+		Where("table_test_delete_log.action", "banned")
+
+	// checking sql
+	sql, _ := qb.Builder().Grammar.CompileDelete(qb.Builder().Query.Clone())
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `delete from "table_test_delete" using "table_test_delete_log" where ("table_test_delete_log"."user_id" = "table_test_delete"."id") and ("table_test_delete_log"."action" = $1)`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "delete from `table_test_delete` where `rowid` in (select `table_test_delete`.`rowid` from `table_test_delete` inner join `table_test_delete_log` on `table_test_delete_log`.`user_id` = `table_test_delete`.`id` where `table_test_delete_log`.`action` = ?)", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "delete `table_test_delete` from `table_test_delete` inner join `table_test_delete_log` on `table_test_delete_log`.`user_id` = `table_test_delete`.`id` where `table_test_delete_log`.`action` = ?", sql, "the query sql not equal")
+	}
+
+	affected := qb.MustDelete()
+	assert.Equal(t, int64(2), affected, "The affected rows should be 2")
+	assert.Equal(t, int64(2), qb.Table("table_test_delete").MustCount(), "The rows count should be 2")
+}
+
+func TestDeleteMustDeleteWithLeftJoin(t *testing.T) {
+	NewTableForDeleteTest()
+	NewTableForDeleteLogTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_delete").
+		LeftJoin("table_test_delete_log", "table_test_delete_log.user_id", "=", "table_test_delete.id").
+		WhereNull("table_test_delete_log.id").
+		MustDelete()
+
+	assert.Equal(t, int64(1), affected, "The affected rows should be 1")
+	rows := qb.Table("table_test_delete").OrderBy("id").MustGet()
+	assert.Equal(t, 3, len(rows), "The return value should be have 3 rows")
+	if len(rows) == 3 {
+		assert.Equal(t, "ben@yao.run", rows[2]["email"].(string), "The email of the 3rd row should be ben@yao.run")
+	}
+}
+
 func TestDeleteMustTruncate(t *testing.T) {
 	NewTableForDeleteTest()
 	qb := getTestBuilder()
@@ -70,6 +110,7 @@ func TestDeleteMustTruncateError(t *testing.T) {
 func TestDeleteClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
 	builder.DropTableIfExists("table_test_delete")
+	builder.DropTableIfExists("table_test_delete_log")
 }
 
 func NewTableForDeleteTest() {
@@ -96,3 +137,22 @@ func NewTableForDeleteTest() {
 		{"email": "ben@yao.run", "name": "Ben", "vote": 6, "score": 48.12, "score_grade": 99.27, "status": "DONE", "created_at": "2021-03-25 18:15:29"},
 	})
 }
+
+func NewTableForDeleteLogTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_delete_log")
+	builder.MustCreateTable("table_test_delete_log", func(table schema.Blueprint) {
+		table.ID("id")
+		table.BigInteger("user_id")
+		table.String("action")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_delete_log").Insert([]xun.R{
+		{"user_id": 1, "action": "login"},
+		{"user_id": 2, "action": "banned"},
+		{"user_id": 4, "action": "login"},
+		{"user_id": 4, "action": "banned"},
+	})
+}
@@ -0,0 +1,51 @@
+package query
+
+import (
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// Explain Get the normalized query plan of the select statement.
+// MySQL: explain format=json (5.6.5+), Postgres: explain (format json), SQLite: explain query plan
+// The analyze of Postgres is opt-in: {"analyze": true} runs explain (analyze, buffers, format json), {"analyze": true, "buffers": false} skips the buffers.
+// The select statement is executed only when the analyze option is true.
+// Explain().HasFullScan() true if any table is scanned without using index.
+func (builder *Builder) Explain(options ...map[string]interface{}) (*dbal.Plan, error) {
+	option := map[string]interface{}{}
+	if len(options) > 0 && options[0] != nil {
+		option = options[0]
+	}
+
+	err := builder.Grammar.CheckQuery(builder.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	sql := builder.Grammar.CompileExplain(builder.ToSQL(), option)
+	bindings := builder.GetBindings()
+	defer log.With(log.F{"bindings": bindings}).Debug(sql)
+
+	rows, err := builder.executor().QueryContext(builder.GetContext(), sql, bindings...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := builder.mapScan(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []map[string]interface{}{}
+	for _, row := range res {
+		results = append(results, row.ToMap())
+	}
+	return builder.Grammar.ProcessExplain(results, option)
+}
+
+// MustExplain Get the normalized query plan of the select statement.
+func (builder *Builder) MustExplain(options ...map[string]interface{}) *dbal.Plan {
+	plan, err := builder.Explain(options...)
+	utils.PanicIF(err)
+	return plan
+}
@@ -0,0 +1,130 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/grammar/mysql"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestExplainFullScan(t *testing.T) {
+	NewTableForExplainTest()
+	qb := getTestBuilder()
+	plan := qb.Table("table_test_explain").
+		Where("vote", ">", 5).
+		MustExplain()
+
+	assert.True(t, len(plan.Nodes) > 0, "the plan should have nodes")
+	assert.True(t, plan.HasFullScan(), "the plan should have full scans")
+
+	nodes := plan.FullScans()
+	if len(nodes) == 0 {
+		return
+	}
+
+	assert.Equal(t, "table_test_explain", nodes[0].Table, "the table of the full scan node should be table_test_explain")
+	assert.Equal(t, "", nodes[0].Index, "the full scan node should not use index")
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, "Seq Scan", nodes[0].Operation, "the operation should be Seq Scan")
+		assert.True(t, plan.Cost > 0, "the cost of the plan should be returned")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "SCAN", nodes[0].Operation, "the operation should be SCAN")
+	} else {
+		assert.Equal(t, "ALL", nodes[0].Operation, "the operation should be ALL")
+	}
+}
+
+func TestExplainIndex(t *testing.T) {
+	NewTableForExplainTest()
+	qb := getTestBuilder()
+	plan := qb.Table("table_test_explain").
+		Where("email", "john@yao.run").
+		MustExplain(map[string]interface{}{"analyze": false})
+
+	assert.True(t, len(plan.Nodes) > 0, "the plan should have nodes")
+	if unit.DriverIs("postgres") {
+		// the planner may prefer the sequential scan for the small tables
+		assert.Equal(t, "table_test_explain", plan.Nodes[0].Table, "the table of the node should be table_test_explain")
+		return
+	}
+
+	assert.False(t, plan.HasFullScan(), "the plan should not have full scans")
+	assert.Equal(t, "table_test_explain", plan.Nodes[0].Table, "the table of the node should be table_test_explain")
+	if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "SEARCH", plan.Nodes[0].Operation, "the operation should be SEARCH")
+		assert.Contains(t, plan.Nodes[0].Index, "email", "the node should use the email index")
+	}
+}
+
+func TestExplainJoin(t *testing.T) {
+	NewTableForExplainTest()
+	qb := getTestBuilder()
+	plan := qb.Table("table_test_explain as t1").
+		Join("table_test_explain as t2", "t2.id", "=", "t1.id").
+		Where("t1.vote", ">", 5).
+		MustExplain()
+
+	tables := []string{}
+	plan.Walk(func(node *dbal.PlanNode) {
+		if node.Table != "" {
+			tables = append(tables, node.Table)
+		}
+	})
+	assert.Equal(t, 2, len(tables), "the plan should have 2 table nodes")
+	assert.True(t, plan.HasFullScan(), "the plan should have full scans")
+}
+
+func TestExplainError(t *testing.T) {
+	NewTableForExplainTest()
+	qb := getTestBuilder()
+	assert.Panics(t, func() {
+		qb.Table("table_test_explain_not_exists").MustExplain()
+	})
+}
+
+func TestExplainCompileOptions(t *testing.T) {
+	query := "select * from \"users\""
+
+	// the statement should not be executed unless the analyze option is given
+	grammar := dbal.Grammars["postgres"]
+	assert.Equal(t, `explain (format json) select * from "users"`, grammar.CompileExplain(query, map[string]interface{}{}), "the explain sql not equal")
+	assert.Equal(t, `explain (analyze, buffers, format json) select * from "users"`, grammar.CompileExplain(query, map[string]interface{}{"analyze": true}), "the explain sql not equal")
+	assert.Equal(t, `explain (analyze, format json) select * from "users"`, grammar.CompileExplain(query, map[string]interface{}{"analyze": true, "buffers": false}), "the explain sql not equal")
+
+	my := dbal.Grammars["mysql"].(mysql.MySQL)
+	my.Version = &dbal.Version{Version: semver.MustParse("5.6.5"), Driver: "mysql"}
+	assert.Equal(t, "explain format=json select * from `users`", my.CompileExplain("select * from `users`", nil), "the explain sql not equal")
+	my.Version = &dbal.Version{Version: semver.MustParse("5.6.4"), Driver: "mysql"}
+	assert.Equal(t, "explain select * from `users`", my.CompileExplain("select * from `users`", nil), "the explain sql not equal")
+}
+
+// clean the test data
+func TestExplainClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_explain")
+}
+
+func NewTableForExplainTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_explain")
+	builder.MustCreateTable("table_test_explain", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.String("name")
+		table.Integer("vote")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_explain").Insert([]xun.R{
+		{"email": "john@yao.run", "name": "John", "vote": 10},
+		{"email": "lee@yao.run", "name": "Lee", "vote": 5},
+		{"email": "ken@yao.run", "name": "Ken", "vote": 125},
+		{"email": "ben@yao.run", "name": "Ben", "vote": 6},
+	})
+}
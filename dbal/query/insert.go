@@ -4,22 +4,19 @@ import (
 	"fmt"
 
 	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/utils"
 )
 
 // Insert Insert new records into the database.
+// A payload over the placeholder limit is inserted by several statements, either all or none of them are applied.
 func (builder *Builder) Insert(v interface{}, columns ...interface{}) error {
 	columns, values := builder.prepareInsertValues(v, columns...)
-	sql, bindings := builder.Grammar.CompileInsert(builder.Query, columns, values)
-	defer log.With(log.F{"bindings": bindings}).Debug(sql)
-
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
-
-	_, err = stmt.Exec(bindings...)
+	chunks := builder.chunkValues(values, len(columns), 0)
+	_, err := builder.execChunks(chunks, func(qb *Builder, values [][]interface{}) (int64, error) {
+		sql, bindings := qb.Grammar.CompileInsert(qb.Query, columns, values)
+		return qb.execAffected(sql, bindings)
+	})
 	return err
 }
 
@@ -29,23 +26,28 @@ func (builder *Builder) MustInsert(v interface{}, columns ...interface{}) {
 	utils.PanicIF(err)
 }
 
-// InsertOrIgnore Insert new records into the database while ignoring errors.
-func (builder *Builder) InsertOrIgnore(v interface{}, columns ...interface{}) (int64, error) {
+// InsertReturning Insert new records into the database and get the rows returned by the "returning" clause. (PostgreSQL, SQLite 3.35.0+)
+func (builder *Builder) InsertReturning(v interface{}, columns ...interface{}) ([]xun.R, error) {
 	columns, values := builder.prepareInsertValues(v, columns...)
-	sql, bindings := builder.Grammar.CompileInsertOrIgnore(builder.Query, columns, values)
-	defer log.With(log.F{"bindings": bindings}).Debug(sql)
+	sql, bindings := builder.Grammar.CompileInsert(builder.Query, columns, values)
+	return builder.execReturning(sql, bindings)
+}
 
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
-	if err != nil {
-		return 0, err
-	}
-	defer stmt.Close()
+// MustInsertReturning Insert new records into the database and get the rows returned by the "returning" clause.
+func (builder *Builder) MustInsertReturning(v interface{}, columns ...interface{}) []xun.R {
+	rows, err := builder.InsertReturning(v, columns...)
+	utils.PanicIF(err)
+	return rows
+}
 
-	res, err := stmt.Exec(bindings...)
-	if err != nil {
-		return 0, err
-	}
-	return res.RowsAffected()
+// InsertOrIgnore Insert new records into the database while ignoring errors, return the number of the inserted records of all batches.
+func (builder *Builder) InsertOrIgnore(v interface{}, columns ...interface{}) (int64, error) {
+	columns, values := builder.prepareInsertValues(v, columns...)
+	chunks := builder.chunkValues(values, len(columns), 0)
+	return builder.execChunks(chunks, func(qb *Builder, values [][]interface{}) (int64, error) {
+		sql, bindings := qb.Grammar.CompileInsertOrIgnore(qb.Query, columns, values)
+		return qb.execAffected(sql, bindings)
+	})
 }
 
 // MustInsertOrIgnore Insert new records into the database while ignoring errors.
@@ -69,7 +71,7 @@ func (builder *Builder) InsertGetID(v interface{}, args ...interface{}) (int64,
 	columns, values := builder.prepareInsertValues(v, columns...)
 	sql, bindings := builder.Grammar.CompileInsertGetID(builder.Query, columns, values, seq)
 	defer log.With(log.F{"bindings": bindings}).Debug(sql)
-	return builder.Grammar.ProcessInsertGetID(sql, bindings, seq)
+	return builder.Grammar.ProcessInsertGetID(builder.executor(true), sql, bindings, seq)
 }
 
 // MustInsertGetID Insert a new record and get the value of the primary key.
@@ -87,13 +89,13 @@ func (builder *Builder) InsertUsing(qb interface{}, columns ...interface{}) (int
 	sql := builder.parseSub(sub)
 	sql = builder.Grammar.CompileInsertUsing(builder.Query, columns, sql)
 
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
+	stmt, err := builder.executor(true).PrepareContext(builder.GetContext(), sql)
 	if err != nil {
 		return 0, err
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(bindings...)
+	res, err := stmt.ExecContext(builder.GetContext(), bindings...)
 	if err != nil {
 		return 0, err
 	}
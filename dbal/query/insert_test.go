@@ -1,6 +1,7 @@
 package query
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -313,6 +314,53 @@ func TestInsertMustInsertUsingWithArray(t *testing.T) {
 
 }
 
+func TestInsertMustInsertChunk(t *testing.T) {
+	NewTableForInsertTest()
+	qb := getTestBuilder()
+
+	// 40000 rows with 2 columns take 80000 placeholders
+	values := []xun.R{}
+	for i := 0; i < 40000; i++ {
+		values = append(values, xun.R{"email": fmt.Sprintf("user%d@example.com", i), "vote": i})
+	}
+	qb.Table("table_test_insert").MustInsert(values)
+	assert.Equal(t, int64(40000), qb.Table("table_test_insert").MustCount(), "The rows count should be 40000")
+
+	row := qb.Table("table_test_insert").Where("email", "user39999@example.com").MustFirst()
+	assert.Equal(t, int64(39999), row.Get("vote").(int64), "The vote of the last row should be 39999")
+}
+
+func TestInsertMustInsertChunkRollback(t *testing.T) {
+	NewTableForInsertTest()
+	qb := getTestBuilder()
+
+	// the last row conflicts with the first one, all of the batches should be rolled back
+	values := []xun.R{}
+	for i := 0; i < 40000; i++ {
+		values = append(values, xun.R{"email": fmt.Sprintf("user%d@example.com", i), "vote": i})
+	}
+	values = append(values, xun.R{"email": "user0@example.com", "vote": 0})
+
+	err := qb.Table("table_test_insert").Insert(values)
+	assert.NotNil(t, err, "The return error should not be nil")
+	assert.Equal(t, int64(0), qb.Table("table_test_insert").MustCount(), "The rows count should be 0")
+}
+
+func TestInsertMustInsertOrIgnoreChunk(t *testing.T) {
+	NewTableForInsertTest()
+	qb := getTestBuilder()
+
+	values := []xun.R{}
+	for i := 0; i < 40000; i++ {
+		values = append(values, xun.R{"email": fmt.Sprintf("user%d@example.com", i), "vote": i})
+	}
+	qb.Table("table_test_insert").MustInsert(values[:10])
+
+	affected := qb.Table("table_test_insert").MustInsertOrIgnore(values)
+	assert.Equal(t, int64(39990), affected, "The affected rows should be 39990")
+	assert.Equal(t, int64(40000), qb.Table("table_test_insert").MustCount(), "The rows count should be 40000")
+}
+
 // clean the test data
 func TestInsertClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
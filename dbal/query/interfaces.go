@@ -1,8 +1,11 @@
 package query
 
 import (
+	"context"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
 )
 
 // Query The database Query interface
@@ -24,18 +27,37 @@ type Query interface {
 	MustFind(id interface{}, args ...interface{}) xun.R
 	Value(column string, v ...interface{}) (interface{}, error)
 	MustValue(column string, v ...interface{}) interface{}
+	Pluck(column string, args ...interface{}) (interface{}, error)
+	MustPluck(column string, args ...interface{}) interface{}
+	Implode(column string, glue string) (string, error)
+	MustImplode(column string, glue string) string
+	Sole(v ...interface{}) (xun.R, error)
+	MustSole(v ...interface{}) xun.R
+	KeyBy(column string, v ...interface{}) (map[interface{}]xun.R, error)
+	MustKeyBy(column string, v ...interface{}) map[interface{}]xun.R
 	Exists() (bool, error)
 	MustExists() bool
 	DoesntExist() (bool, error)
 	MustDoesntExist() bool
 	ToSQL() string
+	ToRawSQL() string
 	GetBindings() []interface{}
 
+	// defined in the cursor.go file
+	Cursor() (*Cursor, error)
+	MustCursor() *Cursor
+
 	// defined in the paginate.go file
 	Paginate(perpage int, page int, v ...interface{}) (xun.P, error)
 	MustPaginate(perpage int, page int, v ...interface{}) xun.P
+	CursorPaginate(perpage int, cursor string, v ...interface{}) (xun.P, error)
+	MustCursorPaginate(perpage int, cursor string, v ...interface{}) xun.P
 	Chunk(size int, callback func(items []interface{}, page int) error, v ...interface{}) error
 	MustChunk(size int, callback func(items []interface{}, page int) error, v ...interface{})
+	ChunkByID(size int, callback func(items []interface{}, page int) error, column string, alias string, v ...interface{}) error
+	MustChunkByID(size int, callback func(items []interface{}, page int) error, column string, alias string, v ...interface{})
+	EachByID(size int, callback func(item interface{}, index int) error, column string, alias string, v ...interface{}) error
+	MustEachByID(size int, callback func(item interface{}, index int) error, column string, alias string, v ...interface{})
 
 	// defined in the connection.go file
 	DB(usewrite ...bool) *sqlx.DB
@@ -43,6 +65,20 @@ type Query interface {
 	UseRead() Query
 	UseWrite() Query
 	IsWrite() bool
+	WithContext(ctx context.Context) Query
+	GetContext() context.Context
+
+	// defined in the transaction.go file
+	Transaction(callback func(tx Query) error) error
+	MustTransaction(callback func(tx Query) error)
+	BeginTransaction() (Query, error)
+	MustBeginTransaction() Query
+	Commit() error
+	MustCommit()
+	Rollback() error
+	MustRollback()
+	InTransaction() bool
+	WithTransaction(tx *sqlx.Tx) Query
 
 	// defined in the aggregate.go file
 	Count(columns ...interface{}) (int64, error)
@@ -64,11 +100,21 @@ type Query interface {
 	SelectSub(qb interface{}, alias string) Query
 	Distinct(args ...interface{}) Query
 
+	// defined in the window.go file
+	SelectWindow(fn interface{}, partitionBy interface{}, orderBy interface{}, alias string, frame ...string) Query
+	SelectOver(fn interface{}, window string, alias string) Query
+	Window(name string, partitionBy interface{}, orderBy interface{}, frame ...string) Query
+
 	// defined in the from.go file
 	From(name string) Query
 	FromRaw(sql string, bindings ...interface{}) Query
 	FromSub(qb interface{}, alias string) Query
 
+	// defined in the with.go file
+	With(name string, query interface{}, columns ...string) Query
+	WithRecursive(name string, columns []string, query interface{}) Query
+	WithMaterialized(name string, query interface{}, columns ...string) Query
+
 	// defined in the union.go file
 	Union(query interface{}, all ...bool) Query
 	UnionAll(query interface{}) Query
@@ -83,6 +129,8 @@ type Query interface {
 	RightJoinSub(qb interface{}, alias string, first interface{}, args ...interface{}) Query
 	CrossJoin(table string) Query
 	CrossJoinSub(qb interface{}, alias string) Query
+	JoinLateral(qb interface{}, alias string) Query
+	LeftJoinLateral(qb interface{}, alias string) Query
 	On(first interface{}, args ...interface{}) Query
 	OrOn(first interface{}, args ...interface{}) Query
 
@@ -119,6 +167,14 @@ type Query interface {
 	OrWhereMonth(column interface{}, args ...interface{}) Query
 	WhereDay(column interface{}, args ...interface{}) Query
 	OrWhereDay(column interface{}, args ...interface{}) Query
+	WhereJSONContains(column string, value interface{}) Query
+	OrWhereJSONContains(column string, value interface{}) Query
+	WhereJSONDoesntContain(column string, value interface{}) Query
+	OrWhereJSONDoesntContain(column string, value interface{}) Query
+	WhereJSONLength(column string, args ...interface{}) Query
+	OrWhereJSONLength(column string, args ...interface{}) Query
+	WhereFullText(columns interface{}, value string, options ...map[string]interface{}) Query
+	OrWhereFullText(columns interface{}, value string, options ...map[string]interface{}) Query
 	When(value bool, callback func(qb Query, value bool), defaults ...func(qb Query, value bool)) Query
 	Unless(value bool, callback func(qb Query, value bool), defaults ...func(qb Query, value bool)) Query
 
@@ -136,6 +192,11 @@ type Query interface {
 	OrderBy(column interface{}, args ...string) Query
 	OrderByDesc(column interface{}) Query
 	OrderByRaw(sql string, bindings ...interface{}) Query
+	OrderByFullText(columns interface{}, value string, options ...map[string]interface{}) Query
+	Latest(column ...interface{}) Query
+	Oldest(column ...interface{}) Query
+	InRandomOrder(seed ...int) Query
+	Reorder(args ...interface{}) Query
 
 	// defined in the limit.go file
 	Skip(value int) Query
@@ -144,8 +205,13 @@ type Query interface {
 	Limit(value int) Query
 
 	// defined in the lock.go file
-	SharedLock() Query
-	LockForUpdate() Query
+	SharedLock(options ...string) Query
+	LockForUpdate(options ...string) Query
+	ForNoKeyUpdate(options ...string) Query
+	ForUpdateOf(tables interface{}, options ...string) Query
+
+	// defined in the returning.go file
+	Returning(columns ...interface{}) Query
 
 	// defined in the insert.go file
 	Insert(v interface{}, columns ...interface{}) error
@@ -156,6 +222,8 @@ type Query interface {
 	MustInsertGetID(v interface{}, args ...interface{}) int64
 	InsertUsing(qb interface{}, columns ...interface{}) (int64, error)
 	MustInsertUsing(qb interface{}, columns ...interface{}) int64
+	InsertReturning(v interface{}, columns ...interface{}) ([]xun.R, error)
+	MustInsertReturning(v interface{}, columns ...interface{}) []xun.R
 
 	// defined in the update.go file
 	Upsert(values interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) (int64, error)
@@ -164,6 +232,12 @@ type Query interface {
 	MustUpdateOrInsert(attributes interface{}, values ...interface{}) bool
 	Update(v interface{}) (int64, error)
 	MustUpdate(v interface{}) int64
+	UpdateReturning(v interface{}) ([]xun.R, error)
+	MustUpdateReturning(v interface{}) []xun.R
+	UpsertReturning(values interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) ([]xun.R, error)
+	MustUpsertReturning(values interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) []xun.R
+	UpdateBatch(v interface{}, key string) (int64, error)
+	MustUpdateBatch(v interface{}, key string) int64
 	Increment(column interface{}, amount interface{}, extra ...interface{}) (int64, error)
 	MustIncrement(column interface{}, amount interface{}, extra ...interface{}) int64
 	Decrement(column interface{}, amount interface{}, extra ...interface{}) (int64, error)
@@ -172,9 +246,15 @@ type Query interface {
 	// defined in the delete.go file
 	Delete() (int64, error)
 	MustDelete() int64
+	DeleteReturning() ([]xun.R, error)
+	MustDeleteReturning() []xun.R
 	Truncate() error
 	MustTruncate()
 
+	// defined in the explain.go file
+	Explain(options ...map[string]interface{}) (*dbal.Plan, error)
+	MustExplain(options ...map[string]interface{}) *dbal.Plan
+
 	// defined in the debug.go file
 	DD()
 	Dump()
@@ -255,7 +335,7 @@ type Query interface {
 // 			  .where(`votes`, `>`, 50)
 // 		})
 // JSON Where Clauses:
-//		table("users").where(`preferences->dining->meal`, `salad`)
+//		table("users").where(`preferences->dining->>meal`, `salad`) // -> returns the JSON value, ->> returns the unquoted text
 // 		table("users").whereJsonContains(`options->languages`, `en`)
 // 		table("users").whereJsonContains(`options->languages`, [`en`, `de`])
 // 		table("users").whereJsonLength(`options->languages`, 0)
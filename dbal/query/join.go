@@ -23,6 +23,17 @@ func (builder *Builder) JoinSub(qb interface{}, alias string, first interface{},
 	return builder.joinSub(qb, alias, first, operator, second, "inner", "on", 0)
 }
 
+// JoinLateral Add a lateral join clause to the query, the subquery could reference the columns of the preceding tables. (PostgreSQL, MySQL 8.0.14+)
+// JoinLateral(func(qb Query){ qb.From("posts").WhereColumn("posts.user_id", "users.id").Limit(3) }, "latest")
+func (builder *Builder) JoinLateral(qb interface{}, alias string) Query {
+	return builder.joinLateral(qb, alias, "inner")
+}
+
+// LeftJoinLateral Add a lateral left join clause to the query. (PostgreSQL, MySQL 8.0.14+)
+func (builder *Builder) LeftJoinLateral(qb interface{}, alias string) Query {
+	return builder.joinLateral(qb, alias, "left")
+}
+
 // LeftJoin Add a left join to the query.
 func (builder *Builder) LeftJoin(table string, first interface{}, args ...interface{}) Query {
 	operator, second := builder.joinPrepare(args...)
@@ -57,19 +68,18 @@ func (builder *Builder) CrossJoinSub(qb interface{}, alias string) Query {
 	return builder.joinSub(qb, alias, nil, "", nil, "cross", "on", 0)
 }
 
-// On Add an "on" clause to the join.
+// On Add an "on" clause to the join. the where clauses (Where, WhereIn, WhereNull ...) could be used within the join closure as well.
+// Join("posts", func(join Query){ join.On("posts.user_id", "users.id").WhereIn("posts.status", []string{"PUBLISHED"}) })
 func (builder *Builder) On(first interface{}, args ...interface{}) Query {
 	operator, second := builder.joinPrepare(args...)
-	join := builder.joinOn(first, operator, second, "and", 0)
-	builder.Query.Joins = append(builder.Query.Joins, join)
+	builder.joinOn(first, operator, second, "and", 0)
 	return builder
 }
 
 // OrOn Add an "or on" clause to the join.
 func (builder *Builder) OrOn(first interface{}, args ...interface{}) Query {
 	operator, second := builder.joinPrepare(args...)
-	join := builder.joinOn(first, operator, second, "or", 0)
-	builder.Query.Joins = append(builder.Query.Joins, join)
+	builder.joinOn(first, operator, second, "or", 0)
 	return builder
 }
 
@@ -145,6 +155,13 @@ func (builder *Builder) joinSub(qb interface{}, alias string, first interface{},
 	return builder.join(sub, alias, first, operator, second, typ, method, joinOffset+offset)
 }
 
+// joinLateral Add a lateral join clause to the query.
+func (builder *Builder) joinLateral(qb interface{}, alias string, typ string) Query {
+	builder.joinSub(qb, alias, nil, "", nil, typ, "on", 0)
+	builder.Query.Joins[len(builder.Query.Joins)-1].Lateral = true
+	return builder
+}
+
 func (builder *Builder) joinOn(first interface{}, operator string, second interface{}, boolean string, offset int) dbal.Join {
 
 	if builder.isClosure(first) {
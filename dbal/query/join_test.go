@@ -3,9 +3,12 @@ package query
 import (
 	"testing"
 
+	"github.com/blang/semver/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/grammar/mysql"
 	"github.com/yaoapp/xun/unit"
 )
 
@@ -89,6 +92,184 @@ func TestJoinJoinOnNested(t *testing.T) {
 	}
 }
 
+func TestJoinJoinOnWhere(t *testing.T) {
+	NewTableFoJoinTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_join_t1 as t1").
+		Join("table_test_join_t2 as t2", func(join Query) {
+			join.On("t2.t1_id", "t1.id").
+				Where("t2.status", "PUBLISHED").
+				WhereIn("t2.o_id", []int{9, 6, 8}).
+				WhereNull("t2.deleted_at")
+		}).
+		Where("t1.vote", ">", 1).
+		Select("t1.id", "t2.o_id").
+		OrderBy("t1.id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "t1"."id", "t2"."o_id" from "table_test_join_t1" as "t1" inner join "table_test_join_t2" as "t2" on ("t2"."t1_id" = "t1"."id" and "t2"."status" = $1 and "t2"."o_id" in ($2,$3,$4) and "t2"."deleted_at" is null) where "t1"."vote" > $5 order by "t1"."id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `t1`.`id`, `t2`.`o_id` from `table_test_join_t1` as `t1` inner join `table_test_join_t2` as `t2` on (`t2`.`t1_id` = `t1`.`id` and `t2`.`status` = ? and `t2`.`o_id` in (?,?,?) and `t2`.`deleted_at` is null) where `t1`.`vote` > ? order by `t1`.`id` asc", sql, "the query sql not equal")
+	}
+
+	// checking bindings
+	bindings := qb.GetBindings()
+	assert.Equal(t, 5, len(bindings), "the bindings should have 5 items")
+	if len(bindings) == 5 {
+		assert.Equal(t, "PUBLISHED", bindings[0].(string), "the 1st binding should be PUBLISHED")
+		assert.Equal(t, 9, bindings[1].(int), "the 2nd binding should be 9")
+		assert.Equal(t, 1, bindings[4].(int), "the 5th binding should be 1")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, int64(9), rows[0]["o_id"].(int64), "the o_id of the 1st row should be 9")
+		assert.Equal(t, int64(6), rows[1]["o_id"].(int64), "the o_id of the 2nd row should be 6")
+	}
+}
+
+func TestJoinJoinSubOnWhere(t *testing.T) {
+	NewTableFoJoinTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_join_t1 as t1").
+		LeftJoinSub(func(qb Query) {
+			qb.From("table_test_join_t2").
+				Where("status", "PUBLISHED").
+				Select("t1_id as join_id", "title")
+		}, "t2", func(join Query) {
+			join.On("t2.join_id", "t1.id").Where("t2.join_id", "<", 4)
+		}).
+		Where("t1.vote", ">", 1).
+		Select("t1.id", "t2.title").
+		OrderBy("t1.id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "t1"."id", "t2"."title" from "table_test_join_t1" as "t1" left join (select "t1_id" as "join_id", "title" from "table_test_join_t2" where "status" = $1) as t2 on ("t2"."join_id" = "t1"."id" and "t2"."join_id" < $2) where "t1"."vote" > $3 order by "t1"."id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `t1`.`id`, `t2`.`title` from `table_test_join_t1` as `t1` left join (select `t1_id` as `join_id`, `title` from `table_test_join_t2` where `status` = ?) as t2 on (`t2`.`join_id` = `t1`.`id` and `t2`.`join_id` < ?) where `t1`.`vote` > ? order by `t1`.`id` asc", sql, "the query sql not equal")
+	}
+
+	// checking bindings
+	bindings := qb.GetBindings()
+	assert.Equal(t, 3, len(bindings), "the bindings should have 3 items")
+	if len(bindings) == 3 {
+		assert.Equal(t, "PUBLISHED", bindings[0].(string), "the 1st binding should be PUBLISHED")
+		assert.Equal(t, 4, bindings[1].(int), "the 2nd binding should be 4")
+		assert.Equal(t, 1, bindings[2].(int), "the 3rd binding should be 1")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 4, len(rows), "the return value should have 4 rows")
+	if len(rows) == 4 {
+		assert.Equal(t, "A Psychological Trick to Evoke An Interesting Conversation", rows[0]["title"].(string), "the title of the 1st row should be A Psychological Trick to Evoke An Interesting Conversation")
+		assert.Nil(t, rows[3]["title"], "the title of the 4th row should be nil")
+	}
+}
+
+func TestJoinJoinLateral(t *testing.T) {
+	NewTableFoJoinTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_join_t1 as t1").
+		JoinLateral(func(qb Query) {
+			qb.From("table_test_join_t2 as t2").
+				WhereColumn("t2.t1_id", "t1.id").
+				Where("t2.status", "PUBLISHED").
+				Select("t2.title").
+				Limit(1)
+		}, "latest").
+		Where("t1.vote", ">", 1).
+		Select("t1.id", "latest.title").
+		OrderBy("t1.id")
+
+	if unit.DriverIs("sqlite3") {
+		assert.NotPanics(t, func() {
+			qb.ToSQL()
+		}, "the lateral joins should not be panic")
+
+		_, err := qb.Get()
+		assert.Contains(t, err.Error(), "does not support lateral joins", "the error should be returned by Get")
+		_, err = qb.First()
+		assert.Contains(t, err.Error(), "does not support lateral joins", "the error should be returned by First")
+		_, err = qb.Exists()
+		assert.Contains(t, err.Error(), "does not support lateral joins", "the error should be returned by Exists")
+		_, err = qb.Update(xun.R{"vote": 1})
+		assert.Contains(t, err.Error(), "does not support lateral joins", "the error should be returned by Update")
+		return
+	}
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "t1"."id", "latest"."title" from "table_test_join_t1" as "t1" inner join lateral (select "t2"."title" from "table_test_join_t2" as "t2" where "t2"."t1_id" = "t1"."id" and "t2"."status" = $1 limit 1) as latest on true where "t1"."vote" > $2 order by "t1"."id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `t1`.`id`, `latest`.`title` from `table_test_join_t1` as `t1` inner join lateral (select `t2`.`title` from `table_test_join_t2` as `t2` where `t2`.`t1_id` = `t1`.`id` and `t2`.`status` = ? limit 1) as latest on true where `t1`.`vote` > ? order by `t1`.`id` asc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, int64(1), rows[0]["id"].(int64), "the id of the 1st row should be 1")
+		assert.Equal(t, int64(4), rows[1]["id"].(int64), "the id of the 2nd row should be 4")
+	}
+}
+
+func TestJoinLeftJoinLateral(t *testing.T) {
+	NewTableFoJoinTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_join_t1 as t1").
+		LeftJoinLateral(func(qb Query) {
+			qb.From("table_test_join_t2 as t2").
+				WhereColumn("t2.t1_id", "t1.id").
+				Where("t2.status", "PUBLISHED").
+				Select("t2.title").
+				Limit(1)
+		}, "latest").
+		Select("t1.id", "latest.title").
+		OrderBy("t1.id")
+
+	if unit.DriverIs("sqlite3") {
+		assert.NotPanics(t, func() {
+			qb.ToSQL()
+		}, "the lateral joins should not be panic")
+
+		_, err := qb.Get()
+		assert.Contains(t, err.Error(), "does not support lateral joins", "the error should be returned by Get")
+		return
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 4, len(rows), "the return value should have 4 rows")
+	if len(rows) == 4 {
+		assert.Nil(t, rows[1]["title"], "the title of the 2nd row should be nil")
+		assert.Equal(t, "The Future of Dashboards is Dashboardless", rows[3]["title"].(string), "the title of the 4th row should be The Future of Dashboards is Dashboardless")
+	}
+}
+
+func TestJoinJoinLateralMySQLVersion(t *testing.T) {
+	grammar := dbal.Grammars["mysql"].(mysql.MySQL)
+	qb := getTestBuilder().New()
+	qb.Table("table_test_join_t1 as t1").
+		JoinLateral(func(qb Query) {
+			qb.From("table_test_join_t2 as t2").WhereColumn("t2.t1_id", "t1.id").Limit(1)
+		}, "latest")
+
+	grammar.Version = &dbal.Version{Version: semver.MustParse("8.0.14"), Driver: "mysql"}
+	assert.Nil(t, grammar.CheckQuery(qb.Builder().Query), "the lateral joins should be supported by MySQL 8.0.14")
+
+	grammar.Version = &dbal.Version{Version: semver.MustParse("8.0.13"), Driver: "mysql"}
+	err := grammar.CheckQuery(qb.Builder().Query)
+	assert.Contains(t, err.Error(), "MySQL 8.0.14+ is required (current: 8.0.13)", "the error should be returned")
+}
+
 func TestJoinJoinSubOn(t *testing.T) {
 	NewTableFoJoinTest()
 	qb := getTestBuilder()
@@ -1,13 +1,50 @@
 package query
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// The options of the row locking clause (PostgreSQL, MySQL 8.0+)
+const (
+	SkipLocked = "skip locked" // Skip the rows that are locked by other transactions
+	NoWait     = "nowait"      // Fail immediately if any of the rows are locked by other transactions
+)
+
 // SharedLock Share lock the selected rows in the table.
-func (builder *Builder) SharedLock() Query {
-	return builder.Lock("share")
+// SharedLock()
+// SharedLock(query.NoWait)
+func (builder *Builder) SharedLock(options ...string) Query {
+	return builder.Lock(builder.makeLock("share", []string{}, options...))
 }
 
 // LockForUpdate Lock the selected rows in the table for updating.
-func (builder *Builder) LockForUpdate() Query {
-	return builder.Lock("update")
+// LockForUpdate()
+// LockForUpdate(query.SkipLocked)
+func (builder *Builder) LockForUpdate(options ...string) Query {
+	return builder.Lock(builder.makeLock("update", []string{}, options...))
+}
+
+// ForNoKeyUpdate Lock the selected rows in the table for updating, the lock does not block the "for key share" locks. (PostgreSQL only)
+// ForNoKeyUpdate()
+// ForNoKeyUpdate(query.SkipLocked)
+func (builder *Builder) ForNoKeyUpdate(options ...string) Query {
+	return builder.Lock(builder.makeLock("no key update", []string{}, options...))
+}
+
+// ForUpdateOf Lock the selected rows of the given tables for updating.
+// ForUpdateOf("jobs")
+// ForUpdateOf("jobs,users", query.SkipLocked)
+// ForUpdateOf([]string{"jobs", "users"}, query.NoWait)
+func (builder *Builder) ForUpdateOf(tables interface{}, options ...string) Query {
+	of := []string{}
+	for _, table := range builder.prepareColumns(tables) {
+		of = append(of, fmt.Sprintf("%v", table))
+	}
+	return builder.Lock(builder.makeLock("update", of, options...))
 }
 
 // Lock Lock the selected rows in the table.
@@ -18,3 +55,18 @@ func (builder *Builder) Lock(value interface{}) Query {
 	}
 	return builder
 }
+
+// makeLock make a new row locking clause
+func (builder *Builder) makeLock(typ string, of []string, options ...string) dbal.Lock {
+	lock := dbal.Lock{Type: typ, Of: of}
+	if len(options) > 1 {
+		panic(fmt.Errorf(`The row locking clause accepts only one option, "skip locked" or "nowait"`))
+	} else if len(options) == 1 {
+		option := strings.ToLower(options[0])
+		if !utils.StringHave([]string{SkipLocked, NoWait}, option) {
+			panic(fmt.Errorf(`The option of the row locking clause must be "skip locked" or "nowait"`))
+		}
+		lock.Option = option
+	}
+	return lock
+}
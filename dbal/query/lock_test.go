@@ -3,9 +3,12 @@ package query
 import (
 	"testing"
 
+	"github.com/blang/semver/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/grammar/mysql"
 	"github.com/yaoapp/xun/unit"
 )
 
@@ -68,6 +71,195 @@ func TestLockLockForUpdate(t *testing.T) {
 	assert.Equal(t, 4, len(rows), "the return value should be have 4 items")
 }
 
+func TestLockLockForUpdateSkipLocked(t *testing.T) {
+	NewTableForLockTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_lock").
+		Select("id", "vote").
+		Where("status", "WAITING").
+		OrderBy("id").
+		Limit(1).
+		LockForUpdate(SkipLocked)
+
+	assert.True(t, qb.IsWrite(), "the connection should be write")
+
+	// checking sql
+	if unit.DriverIs("sqlite3") {
+		assert.NotPanics(t, func() { qb.ToSQL() }, "the unsupported lock should not be panic")
+		_, err := qb.Get()
+		assert.EqualError(t, err, "This database engine does not support the row locking clause: for update skip locked", "the error should be returned")
+		_, err = qb.First()
+		assert.EqualError(t, err, "This database engine does not support the row locking clause: for update skip locked", "the error should be returned")
+		_, err = qb.Exists()
+		assert.EqualError(t, err, "This database engine does not support the row locking clause: for update skip locked", "the error should be returned")
+		return
+	}
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id", "vote" from "table_test_lock" where "status" = $1 order by "id" asc limit 1 for update skip locked`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id`, `vote` from `table_test_lock` where `status` = ? order by `id` asc limit 1 for update skip locked", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should be have 1 item")
+}
+
+func TestLockSharedLockNoWait(t *testing.T) {
+	NewTableForLockTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_lock").
+		Select("id", "vote").
+		OrderByDesc("id").
+		SharedLock(NoWait)
+
+	// checking sql
+	if unit.DriverIs("sqlite3") {
+		assert.NotPanics(t, func() { qb.ToSQL() }, "the unsupported lock should not be panic")
+		_, err := qb.Get()
+		assert.EqualError(t, err, "This database engine does not support the row locking clause: for share nowait", "the error should be returned")
+		return
+	}
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id", "vote" from "table_test_lock" order by "id" desc for share nowait`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id`, `vote` from `table_test_lock` order by `id` desc for share nowait", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 4, len(rows), "the return value should be have 4 items")
+}
+
+func TestLockForNoKeyUpdate(t *testing.T) {
+	NewTableForLockTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_lock").
+		Select("id", "vote").
+		OrderByDesc("id").
+		ForNoKeyUpdate()
+
+	// checking sql
+	if unit.DriverIs("sqlite3") {
+		assert.NotPanics(t, func() { qb.ToSQL() }, "the unsupported lock should not be panic")
+		_, err := qb.Get()
+		assert.EqualError(t, err, "This database engine does not support the row locking clause: for no key update", "the error should be returned")
+		return
+	} else if !unit.DriverIs("postgres") {
+		assert.NotPanics(t, func() { qb.ToSQL() }, "the unsupported lock should not be panic")
+		_, err := qb.Get()
+		assert.EqualError(t, err, "This database engine does not support the for no key update lock", "the error should be returned")
+		return
+	}
+
+	sql := qb.ToSQL()
+	assert.Equal(t, `select "id", "vote" from "table_test_lock" order by "id" desc for no key update`, sql, "the query sql not equal")
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 4, len(rows), "the return value should be have 4 items")
+}
+
+func TestLockForUpdateOf(t *testing.T) {
+	NewTableForLockTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_lock as t").
+		Join("table_test_lock as u", "u.id", "=", "t.id").
+		Select("t.id", "u.vote").
+		OrderByDesc("t.id").
+		ForUpdateOf("t", SkipLocked)
+
+	// checking sql
+	if unit.DriverIs("sqlite3") {
+		assert.NotPanics(t, func() { qb.ToSQL() }, "the unsupported lock should not be panic")
+		_, err := qb.Get()
+		assert.EqualError(t, err, "This database engine does not support the row locking clause: for update of `t` skip locked", "the error should be returned")
+		return
+	}
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "t"."id", "u"."vote" from "table_test_lock" as "t" inner join "table_test_lock" as "u" on "u"."id" = "t"."id" order by "t"."id" desc for update of "t" skip locked`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `t`.`id`, `u`.`vote` from `table_test_lock` as `t` inner join `table_test_lock` as `u` on `u`.`id` = `t`.`id` order by `t`.`id` desc for update of `t` skip locked", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 4, len(rows), "the return value should be have 4 items")
+}
+
+func TestLockOptionsFail(t *testing.T) {
+	qb := getTestBuilder()
+	assert.Panics(t, func() { qb.Table("table_test_lock").LockForUpdate("skip") }, "the invalid option should be panic")
+	assert.Panics(t, func() { qb.Table("table_test_lock").LockForUpdate(SkipLocked, NoWait) }, "the options should be only one")
+}
+
+func TestLockMySQLVersion(t *testing.T) {
+	grammar := dbal.Grammars["mysql"].(mysql.MySQL)
+	query := dbal.NewQuery()
+	query.Lock = dbal.Lock{Type: "update", Option: SkipLocked}
+
+	grammar.Version = &dbal.Version{Version: semver.MustParse("5.7.30"), Driver: "mysql"}
+	err := grammar.CheckQuery(query)
+	assert.EqualError(t, err, "This database engine does not support the row locking clause: for update skip locked, MySQL 8.0+ is required (current: 5.7.30)", "the error should be returned")
+
+	grammar.Version = &dbal.Version{Version: semver.MustParse("8.0.26"), Driver: "mysql"}
+	err = grammar.CheckQuery(query)
+	assert.Nil(t, err, "the return error should be nil")
+
+	query.Lock = dbal.Lock{Type: "update"}
+	grammar.Version = &dbal.Version{Version: semver.MustParse("5.7.30"), Driver: "mysql"}
+	err = grammar.CheckQuery(query)
+	assert.Nil(t, err, "the lock without options should be supported")
+}
+
+func TestLockForUpdateOfAlias(t *testing.T) {
+	NewTableForLockTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_lock as t").
+		Select("t.id").
+		OrderByDesc("t.id").
+		ForUpdateOf("table_test_lock as t")
+
+	// checking sql
+	if unit.DriverIs("sqlite3") {
+		_, err := qb.Get()
+		assert.EqualError(t, err, "This database engine does not support the row locking clause: for update of `t`", "the error should be returned")
+		return
+	}
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "t"."id" from "table_test_lock" as "t" order by "t"."id" desc for update of "t"`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `t`.`id` from `table_test_lock` as `t` order by `t`.`id` desc for update of `t`", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 4, len(rows), "the return value should be have 4 items")
+}
+
+func TestLockMySQLCompileUnsupported(t *testing.T) {
+	grammar := dbal.Grammars["mysql"].(mysql.MySQL)
+	grammar.Version = &dbal.Version{Version: semver.MustParse("5.7.30"), Driver: "mysql"}
+	query := dbal.NewQuery()
+	query.From = dbal.From{Type: "basic", Name: dbal.NewName("table_test_lock")}
+	query.Lock = dbal.Lock{Type: "update", Option: SkipLocked}
+
+	var sql string
+	assert.NotPanics(t, func() {
+		sql = grammar.CompileSelect(query)
+	}, "the unsupported lock should not be panic")
+	assert.Equal(t, "select * from `table_test_lock`", sql, "the unsupported lock clause should be skipped")
+	assert.Error(t, grammar.CheckQuery(query), "the error should be returned")
+}
+
 // clean the test data
 func TestLockClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
@@ -73,19 +73,68 @@ func (builder *Builder) OrderByRaw(sql string, bindings ...interface{}) Query {
 	return builder
 }
 
-// Latest Add an "order by" clause for a timestamp to the query. @todo
-func (builder *Builder) Latest() {
+// OrderByFullText Add an "order by" clause of the full text search relevance to the query, the most relevant rows come first.
+// The placeholders of the search value are numbered when the query is compiled.
+// OrderByFullText("title,body", "xun")
+// OrderByFullText([]string{"title", "body"}, "query builder", map[string]interface{}{"mode": "websearch"})
+func (builder *Builder) OrderByFullText(columns interface{}, value string, options ...map[string]interface{}) Query {
+	fulltext := builder.makeFullText(columns, options...)
+	_, bindings := builder.Grammar.CompileFullTextOrder(builder.Query, fulltext, value, 0)
+	builder.Query.Orders = append(builder.Query.Orders, dbal.Order{
+		Type:     "fulltext",
+		FullText: &fulltext,
+		Value:    value,
+	})
+	if len(bindings) > 0 {
+		builder.Query.AddBinding("order", bindings)
+	}
+	return builder
 }
 
-// Oldest Add an "order by" clause for a timestamp to the query. @todo
-func (builder *Builder) Oldest() {
+// Latest Add an "order by" clause for a timestamp to the query.
+// Latest() order by `created_at` desc
+// Latest("updated_at") order by `updated_at` desc
+func (builder *Builder) Latest(column ...interface{}) Query {
+	if len(column) > 0 {
+		return builder.OrderBy(column[0], "desc")
+	}
+	return builder.OrderBy("created_at", "desc")
 }
 
-// InRandomOrder Put the query's results in random order. @todo
-func (builder *Builder) InRandomOrder() {
+// Oldest Add an "order by" clause for a timestamp to the query.
+// Oldest() order by `created_at` asc
+// Oldest("updated_at") order by `updated_at` asc
+func (builder *Builder) Oldest(column ...interface{}) Query {
+	if len(column) > 0 {
+		return builder.OrderBy(column[0], "asc")
+	}
+	return builder.OrderBy("created_at", "asc")
+}
+
+// InRandomOrder Put the query's results in random order.
+// InRandomOrder()
+// InRandomOrder(20) the seed is only available for MySQL, PostgreSQL and SQLite return an error when the seed is given.
+func (builder *Builder) InRandomOrder(seed ...int) Query {
+	order := dbal.Order{Type: "random"}
+	value := ""
+	if len(seed) > 0 {
+		value = fmt.Sprintf("%d", seed[0])
+		order.Value = seed[0]
+	}
+	order.SQL = builder.Grammar.CompileRandom(value)
+
+	if len(builder.Query.Unions) > 0 {
+		builder.Query.UnionOrders = append(builder.Query.UnionOrders, order)
+	} else {
+		builder.Query.Orders = append(builder.Query.Orders, order)
+	}
+	return builder
 }
 
-// Reorder Remove all existing orders and optionally add a new order. @todo
+// Reorder Remove all existing orders and optionally add a new order.
+// Reorder()
+// Reorder("email")
+// Reorder("email", "desc")
 func (builder *Builder) Reorder(args ...interface{}) Query {
 	builder.Query.Orders = []dbal.Order{}
 	builder.Query.UnionOrders = []dbal.Order{}
@@ -96,8 +145,8 @@ func (builder *Builder) Reorder(args ...interface{}) Query {
 		return builder.OrderBy(args[0])
 	} else if len(args) == 2 {
 		direction := "asc"
-		if _, ok := args[0].(string); ok {
-			direction = args[0].(string)
+		if _, ok := args[1].(string); ok {
+			direction = args[1].(string)
 		}
 		return builder.OrderBy(args[0], direction)
 	}
@@ -110,6 +110,88 @@ func TestOrderOrderByRawUnion(t *testing.T) {
 	checkOrderOrderByUnion(t, qb)
 }
 
+func TestOrderLatestOldest(t *testing.T) {
+	NewTableForOrderTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_order").Select("id").Latest()
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id" from "table_test_order" order by "created_at" desc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id` from `table_test_order` order by `created_at` desc", sql, "the query sql not equal")
+	}
+	assert.Equal(t, int64(4), qb.MustFirst()["id"].(int64), "the id of the latest row should be 4")
+
+	qb.Table("table_test_order").Select("id").Oldest()
+	assert.Equal(t, int64(1), qb.MustFirst()["id"].(int64), "the id of the oldest row should be 1")
+
+	qb.Table("table_test_order").Select("id").Latest("score")
+	assert.Equal(t, int64(3), qb.MustFirst()["id"].(int64), "the id of the row with the highest score should be 3")
+
+	qb.Table("table_test_order").Select("id").Oldest("score")
+	assert.Equal(t, int64(4), qb.MustFirst()["id"].(int64), "the id of the row with the lowest score should be 4")
+}
+
+func TestOrderInRandomOrder(t *testing.T) {
+	NewTableForOrderTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_order").Select("id").InRandomOrder()
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id" from "table_test_order" order by RANDOM()`, sql, "the query sql not equal")
+		_, err := qb.New().Table("table_test_order").Select("id").InRandomOrder(10).Get()
+		assert.EqualError(t, err, "This database engine does not support the seed of the random order", "the error should be returned")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select `id` from `table_test_order` order by RANDOM()", sql, "the query sql not equal")
+		_, err := qb.New().Table("table_test_order").Select("id").InRandomOrder(10).Get()
+		assert.EqualError(t, err, "This database engine does not support the seed of the random order", "the error should be returned")
+	} else {
+		assert.Equal(t, "select `id` from `table_test_order` order by RAND()", sql, "the query sql not equal")
+		qb.Table("table_test_order").Select("id").InRandomOrder(10)
+		assert.Equal(t, "select `id` from `table_test_order` order by RAND(10)", qb.ToSQL(), "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 4, len(rows), "the return value should be have 4 rows")
+}
+
+func TestOrderReorder(t *testing.T) {
+	NewTableForOrderTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_order").
+		Where("email", "like", "%@yao.run").
+		Select("id", "name", "email", "vote", "score", "status").
+		OrderBy(func(qb Query) {
+			qb.Table("table_test_order as sub").Select("sub.vote").WhereColumn("sub.id", "table_test_order.id").Where("sub.vote", ">", 0)
+		}).
+		Reorder()
+
+	assert.Equal(t, 0, len(qb.Builder().Query.Orders), "the orders should be removed")
+	assert.Equal(t, 0, len(qb.Builder().Query.GetBindings("order")), "the order bindings should be removed")
+	assert.Equal(t, 1, len(qb.GetBindings()), "the bindings should have 1 item")
+
+	qb.OrderBy("name").Reorder("vote", "desc").OrderBy("score")
+	checkOrderOrderBy(t, qb)
+
+	qb.Table("table_test_order").
+		Where("email", "like", "%@yao.run").
+		Select("id", "name", "email", "vote", "score", "status").
+		OrderBy("name").
+		Reorder("id")
+
+	rows := qb.MustGet()
+	assert.Equal(t, 4, len(rows), "the return value should be have 4 rows")
+	if len(rows) == 4 {
+		assert.Equal(t, int64(1), rows[0]["id"].(int64), "the id of 1st row should be 1")
+		assert.Equal(t, int64(4), rows[3]["id"].(int64), "the id of 4th row should be 4")
+	}
+}
+
 // clean the test data
 func TestOrderClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
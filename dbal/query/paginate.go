@@ -1,8 +1,12 @@
 package query
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
@@ -20,43 +24,14 @@ func (builder *Builder) Chunk(size int, callback func(items []interface{}, page
 
 	for {
 
-		var results []interface{} = nil
-		var countResults int
-
 		// We'll execute the query for the given page and get the results. If there are
 		// no results we can just break and return from here. When there are results
 		// we will call the callback with the current chunk of these results here.
-		if len(v) > 0 {
-
-			reflectValuesPtr := reflect.ValueOf(v[0])
-			reflectValues := reflect.Indirect(reflectValuesPtr)
-			if reflectValues.Kind() != reflect.Slice {
-				return fmt.Errorf("The given binding var shoule be a slice pointer")
-			}
-
-			reflectValuesType := reflectValues.Type()
-			reflectValuesPtr.Elem().Set(reflect.New(reflectValuesType).Elem())
-
-			_, err := builder.forPage(page, size).Get(v...)
-			if err != nil {
-				return err
-			}
-
-			countResults = reflectValues.Len()
-			for i := 0; i < countResults; i++ {
-				results = append(results, reflectValues.Index(i).Interface())
-			}
-		} else {
-			rows, err := builder.forPage(page, size).Get()
-			if err != nil {
-				return err
-			}
-
-			countResults = len(rows)
-			for _, row := range rows {
-				results = append(results, row)
-			}
+		results, err := builder.getChunk(builder.forPage(page, size), v...)
+		if err != nil {
+			return err
 		}
+		countResults := len(results)
 
 		// log.Trace("Chunk: countResults: %d size: %d page: %d", countResults, size, page)
 		if err := callback(results, page); err != nil {
@@ -80,11 +55,154 @@ func (builder *Builder) MustChunk(size int, callback func(items []interface{}, p
 	utils.PanicIF(err)
 }
 
-// ChunkByID chunk the results of a query by comparing IDs.
-func (builder *Builder) ChunkByID() {}
+// ChunkByID Chunk the results of a query by comparing IDs. It pages with "where column > last id order by column" instead of offsets.
+// The column is "id" by default, and the alias is the name of the column in the results. eg: ChunkByID(100, callback, "users.id", "id")
+func (builder *Builder) ChunkByID(size int, callback func(items []interface{}, page int) error, column string, alias string, v ...interface{}) error {
 
-// MustChunkByID chunk the results of a query by comparing IDs.
-func (builder *Builder) MustChunkByID() {}
+	if column == "" {
+		column = "id"
+	}
+
+	if alias == "" {
+		alias = column
+		if strings.Contains(alias, ".") {
+			alias = alias[strings.LastIndex(alias, ".")+1:]
+		}
+	}
+
+	if size < 1 {
+		size = 50
+	}
+
+	var lastID interface{} = nil
+	page := 1
+	for {
+
+		// We'll execute the query for the given page and get the results. The query is
+		// cloned for each page, so the constraint of the previous last id will not be
+		// stacked on the builder. If there are no results we can just break and return.
+		clone := builder.clone()
+		results, err := builder.getChunk(clone.forPageAfterID(size, lastID, column), v...)
+		if err != nil {
+			return err
+		}
+
+		countResults := len(results)
+		if countResults == 0 {
+			break
+		}
+
+		if err := callback(results, page); err != nil {
+			return err
+		}
+
+		if countResults != size {
+			break
+		}
+
+		lastID, err = builder.getChunkLastID(results[countResults-1], alias)
+		if err != nil {
+			return err
+		}
+
+		page++
+	}
+
+	return nil
+}
+
+// MustChunkByID Chunk the results of a query by comparing IDs.
+func (builder *Builder) MustChunkByID(size int, callback func(items []interface{}, page int) error, column string, alias string, v ...interface{}) {
+	err := builder.ChunkByID(size, callback, column, alias, v...)
+	utils.PanicIF(err)
+}
+
+// EachByID Execute a callback over each item while chunking by ID.
+func (builder *Builder) EachByID(size int, callback func(item interface{}, index int) error, column string, alias string, v ...interface{}) error {
+	return builder.ChunkByID(size, func(items []interface{}, page int) error {
+		for i, item := range items {
+			if err := callback(item, (page-1)*size+i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, column, alias, v...)
+}
+
+// MustEachByID Execute a callback over each item while chunking by ID.
+func (builder *Builder) MustEachByID(size int, callback func(item interface{}, index int) error, column string, alias string, v ...interface{}) {
+	err := builder.EachByID(size, callback, column, alias, v...)
+	utils.PanicIF(err)
+}
+
+// getChunk Execute the query of a chunk and get the results. The results will be bound to the given slice pointer if it is given.
+func (builder *Builder) getChunk(qb Query, v ...interface{}) ([]interface{}, error) {
+	var results []interface{} = nil
+	if len(v) > 0 {
+
+		reflectValuesPtr := reflect.ValueOf(v[0])
+		reflectValues := reflect.Indirect(reflectValuesPtr)
+		if reflectValues.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("The given binding var shoule be a slice pointer")
+		}
+
+		reflectValuesType := reflectValues.Type()
+		reflectValuesPtr.Elem().Set(reflect.New(reflectValuesType).Elem())
+
+		_, err := qb.Get(v...)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < reflectValues.Len(); i++ {
+			results = append(results, reflectValues.Index(i).Interface())
+		}
+		return results, nil
+	}
+
+	rows, err := qb.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		results = append(results, row)
+	}
+	return results, nil
+}
+
+// getChunkLastID Get the id of the last item of a chunk.
+func (builder *Builder) getChunkLastID(item interface{}, alias string) (interface{}, error) {
+	value, has := builder.getItemValue(item, alias)
+	if !has {
+		return nil, fmt.Errorf("The chunk by id operation was aborted because the %s column is not present in the query result", alias)
+	}
+	return value, nil
+}
+
+// getItemValue Get the value of the given column from a result item. The item could be a xun.R or a struct.
+func (builder *Builder) getItemValue(item interface{}, column string) (interface{}, bool) {
+
+	if row, ok := item.(xun.R); ok {
+		if !row.Has(column) {
+			return nil, false
+		}
+		return row.Get(column), true
+	}
+
+	reflectValue := reflect.Indirect(reflect.ValueOf(item))
+	if reflectValue.Kind() == reflect.Struct {
+		fieldMap, err := builder.getFieldMap(reflectValue.Type())
+		if err != nil {
+			return nil, false
+		}
+		if field, has := fieldMap[column]; has {
+			return reflectValue.FieldByName(field.Name).Interface(), true
+		}
+	}
+
+	return nil, false
+}
 
 // Paginate paginate the given query into a simple paginator.
 func (builder *Builder) Paginate(pageSize int, page int, v ...interface{}) (xun.P, error) {
@@ -132,28 +250,218 @@ func (builder *Builder) MustPaginate(pageSize int, page int, v ...interface{}) x
 	return res
 }
 
+// CursorPaginate Paginate the given query into a cursor paginator. The items are paged with the order by columns of the query,
+// the cursor is the encoded values of the order by columns of the first or the last item. the count query will not be executed.
+// eg: CursorPaginate(15, "") the first page,  CursorPaginate(15, paginator.NextCursor) the next page.
+func (builder *Builder) CursorPaginate(pageSize int, cursor string, v ...interface{}) (xun.P, error) {
+
+	if pageSize < 1 {
+		pageSize = 15
+	}
+
+	columns, err := builder.getCursorColumns()
+	if err != nil {
+		return xun.MakeCursorP(pageSize, "", ""), err
+	}
+
+	payload, err := builder.decodeCursor(cursor)
+	if err != nil {
+		return xun.MakeCursorP(pageSize, "", ""), err
+	}
+
+	// If the cursor points to the previous items, we will reverse the orders of the query,
+	// and then reverse the results, so the items are always in the order of the query.
+	clone := builder.clone()
+	pointsToNext := payload == nil || payload.Next
+	if !pointsToNext {
+		for i := range clone.Query.Orders {
+			if clone.Query.Orders[i].Direction == "desc" {
+				clone.Query.Orders[i].Direction = "asc"
+			} else {
+				clone.Query.Orders[i].Direction = "desc"
+			}
+		}
+	}
+
+	if payload != nil {
+		clone.whereCursor(columns, payload)
+	}
+
+	items, err := builder.getChunk(clone.Limit(pageSize+1), v...)
+	if err != nil {
+		return xun.MakeCursorP(pageSize, "", ""), err
+	}
+
+	hasMore := len(items) > pageSize
+	if hasMore {
+		items = items[:pageSize]
+	}
+
+	if !pointsToNext {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	if len(items) == 0 {
+		return xun.MakeCursorP(pageSize, "", "", items...), nil
+	}
+
+	nextCursor := ""
+	if (payload == nil && hasMore) || (payload != nil && (!pointsToNext || hasMore)) {
+		nextCursor, err = builder.encodeCursor(columns, items[len(items)-1], true)
+		if err != nil {
+			return xun.MakeCursorP(pageSize, "", ""), err
+		}
+	}
+
+	prevCursor := ""
+	if payload != nil && (pointsToNext || hasMore) {
+		prevCursor, err = builder.encodeCursor(columns, items[0], false)
+		if err != nil {
+			return xun.MakeCursorP(pageSize, "", ""), err
+		}
+	}
+
+	return xun.MakeCursorP(pageSize, nextCursor, prevCursor, items...), nil
+}
+
+// MustCursorPaginate Paginate the given query into a cursor paginator.
+func (builder *Builder) MustCursorPaginate(pageSize int, cursor string, v ...interface{}) xun.P {
+	res, err := builder.CursorPaginate(pageSize, cursor, v...)
+	utils.PanicIF(err)
+	return res
+}
+
 // Set the limit and offset for a given page.
 func (builder *Builder) forPage(page int, pageSize int) Query {
 	return builder.Offset((page - 1) * pageSize).Limit(pageSize)
 }
 
-// @todo: forPageBeforeID  Constrain the query to the previous "page" of results before a given ID.
-// func (builder *Builder) forPageBeforeID(pageSize int, lastID int, column string) Query {
-// 	builder.Query.Orders = builder.removeExistingOrdersFor(column)
-// 	if lastID != 0 {
-// 		builder.Where(column, "<", lastID)
-// 	}
-// 	return builder.OrderBy(column, "desc").Limit(pageSize)
-// }
-
-// @todo: forPageAfterID  Constrain the query to the next "page" of results after a given ID.
-// func (builder *Builder) forPageAfterID(pageSize int, lastID int, column string) Query {
-// 	builder.Query.Orders = builder.removeExistingOrdersFor(column)
-// 	if lastID != 0 {
-// 		builder.Where(column, ">", lastID)
-// 	}
-// 	return builder.OrderBy(column, "asc").Limit(pageSize)
-// }
+// forPageAfterID Constrain the query to the next "page" of results after a given ID.
+func (builder *Builder) forPageAfterID(pageSize int, lastID interface{}, column string) Query {
+	builder.Query.Orders = builder.removeExistingOrdersFor(column)
+	if lastID != nil {
+		builder.Where(column, ">", lastID)
+	}
+	return builder.OrderBy(column, "asc").Limit(pageSize)
+}
+
+// whereCursor Constrain the query to the items after the cursor, in the order of the query.
+// (a, b) > (1, 2) => a > 1 or (a = 1 and b > 2), it works with the mixed order directions.
+func (builder *Builder) whereCursor(columns []string, payload *cursorPayload) Query {
+	return builder.Where(func(qb Query) {
+		for i := range columns {
+			qb.OrWhere(func(sub Query) {
+				for _, column := range columns[:i] {
+					sub.Where(column, "=", payload.Values[column])
+				}
+				operator := ">"
+				if builder.Query.Orders[i].Direction == "desc" {
+					operator = "<"
+				}
+				sub.Where(columns[i], operator, payload.Values[columns[i]])
+			})
+		}
+	})
+}
+
+// getCursorColumns Get the order by columns of the cursor paginator
+func (builder *Builder) getCursorColumns() ([]string, error) {
+	if len(builder.Query.Orders) == 0 {
+		return nil, fmt.Errorf("You must specify an orderBy clause when using the cursor paginator")
+	}
+
+	columns := []string{}
+	for _, order := range builder.Query.Orders {
+		column, ok := order.Column.(string)
+		if order.Type != "basic" || !ok {
+			return nil, fmt.Errorf("Only the columns could be used as the order by clauses of the cursor paginator")
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// encodeCursor Encode the values of the order by columns of the given item to the cursor
+func (builder *Builder) encodeCursor(columns []string, item interface{}, next bool) (string, error) {
+	payload := cursorPayload{Values: map[string]interface{}{}, Types: map[string]string{}, Next: next}
+	for _, column := range columns {
+		alias := column
+		if strings.Contains(alias, ".") {
+			alias = alias[strings.LastIndex(alias, ".")+1:]
+		}
+
+		value, has := builder.getItemValue(item, alias)
+		if !has {
+			return "", fmt.Errorf("The cursor paginator was aborted because the %s column is not present in the query result", alias)
+		}
+
+		// the time values are encoded with the nanoseconds, and decoded back to time.Time
+		if timeValue, ok := value.(time.Time); ok {
+			payload.Values[column] = timeValue.Format(time.RFC3339Nano)
+			payload.Types[column] = "time"
+			continue
+		}
+		payload.Values[column] = value
+	}
+
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// decodeCursor Decode the given cursor, return nil if the cursor is empty.
+func (builder *Builder) decodeCursor(cursor string) (*cursorPayload, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	bytes, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("The given cursor is invalid")
+	}
+
+	payload := &cursorPayload{}
+	decoder := json.NewDecoder(strings.NewReader(string(bytes)))
+	decoder.UseNumber()
+	if err := decoder.Decode(payload); err != nil {
+		return nil, fmt.Errorf("The given cursor is invalid")
+	}
+
+	for _, column := range builder.Query.Orders {
+		name := fmt.Sprintf("%v", column.Column)
+		value, has := payload.Values[name]
+		if !has {
+			return nil, fmt.Errorf("The given cursor is invalid")
+		}
+
+		if payload.Types[name] == "time" {
+			text, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("The given cursor is invalid")
+			}
+			timeValue, err := time.Parse(time.RFC3339Nano, text)
+			if err != nil {
+				return nil, fmt.Errorf("The given cursor is invalid")
+			}
+			payload.Values[name] = timeValue
+			continue
+		}
+
+		// json numbers are decoded as int64 or float64
+		if number, ok := value.(json.Number); ok {
+			if intValue, err := number.Int64(); err == nil {
+				payload.Values[name] = intValue
+			} else if floatValue, err := number.Float64(); err == nil {
+				payload.Values[name] = floatValue
+			}
+		}
+	}
+	return payload, nil
+}
 
 // getCountForPagination  Get the count of the total records for the paginator.
 func (builder *Builder) getCountForPagination(columns []interface{}) (int, error) {
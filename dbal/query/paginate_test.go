@@ -1,7 +1,9 @@
 package query
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/yaoapp/xun"
@@ -293,6 +295,262 @@ func TestPaginateChunkWithBindError(t *testing.T) {
 	})
 }
 
+func TestPaginateChunkByID(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Where("status", "<>", "WAITING").
+		Select("id", "name", "email", "vote", "score", "status").
+		OrderByDesc("id")
+
+	// the callback updates the filtered column, the offset based chunk skips rows in this case
+	pages := []int{}
+	IDs := []int64{}
+	qb.MustChunkByID(1, func(items []interface{}, page int) error {
+		pages = append(pages, page)
+		for _, item := range items {
+			id := item.(xun.R).Get("id").(int64)
+			IDs = append(IDs, id)
+			qb.New().Table("table_test_paginate").Where("id", id).MustUpdate(xun.R{"status": "WAITING"})
+		}
+		return nil
+	}, "id", "")
+
+	assert.Equal(t, []int{1, 2, 3}, pages, "The pages should be []int{1,2,3}")
+	assert.Equal(t, []int64{2, 3, 4}, IDs, "The chunk id of items ids should be []int64{2,3,4}")
+	assert.Equal(t, int64(4), getTestBuilder().Table("table_test_paginate").Where("status", "WAITING").MustCount(), "All of the rows should be updated")
+}
+
+func TestPaginateChunkByIDWithAlias(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate as t1").
+		Join("table_test_paginate_t2 as t2", "t2.t1_id", "=", "t1.id").
+		Select("t1.id as user_id", "t2.name as name")
+
+	IDs := []int64{}
+	names := []string{}
+	qb.MustChunkByID(3, func(items []interface{}, page int) error {
+		for _, item := range items {
+			IDs = append(IDs, item.(xun.R).Get("user_id").(int64))
+			names = append(names, item.(xun.R).Get("name").(string))
+		}
+		return nil
+	}, "t1.id", "user_id")
+
+	assert.Equal(t, []int64{1, 2, 3, 4}, IDs, "The chunk id of items ids should be []int64{1,2,3,4}")
+	assert.Equal(t, []string{"Emma", "Ava", "Amelia", "Elizabeth"}, names, "The chunk names should be equal")
+}
+
+func TestPaginateChunkByIDWithBind(t *testing.T) {
+
+	type Item struct {
+		ID            int64
+		Email         string
+		Score         float64
+		Vote          int
+		PaymentStatus string `json:"status"`
+	}
+
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Where("email", "like", "%@yao.run").
+		Select("id", "email", "vote", "score", "status")
+
+	IDs := []int64{}
+	qb.MustChunkByID(3, func(items []interface{}, page int) error {
+		for _, item := range items {
+			IDs = append(IDs, item.(Item).ID)
+		}
+		return nil
+	}, "", "", &[]Item{})
+	assert.Equal(t, []int64{1, 2, 3, 4}, IDs, "The chunk id of items ids should be []int64{1,2,3,4}")
+}
+
+func TestPaginateChunkByIDError(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").Select("email")
+
+	assert.PanicsWithError(t, "The chunk by id operation was aborted because the id column is not present in the query result", func() {
+		qb.MustChunkByID(2, func(items []interface{}, page int) error {
+			return nil
+		}, "id", "")
+	})
+
+	err := qb.ChunkByID(2, func(items []interface{}, page int) error {
+		return fmt.Errorf("something wrong")
+	}, "id", "")
+	assert.Equal(t, "something wrong", err.Error(), "the error of the callback should be returned")
+}
+
+func TestPaginateEachByID(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").Select("id", "name")
+
+	names := []string{}
+	indexes := []int{}
+	qb.MustEachByID(3, func(item interface{}, index int) error {
+		names = append(names, item.(xun.R).Get("name").(string))
+		indexes = append(indexes, index)
+		return nil
+	}, "id", "")
+	assert.Equal(t, []string{"John", "Lee", "Ken", "Ben"}, names, "The names should be equal")
+	assert.Equal(t, []int{0, 1, 2, 3}, indexes, "The indexes should be []int{0,1,2,3}")
+
+	names = []string{}
+	err := qb.EachByID(3, func(item interface{}, index int) error {
+		names = append(names, item.(xun.R).Get("name").(string))
+		if index == 1 {
+			return fmt.Errorf("stop")
+		}
+		return nil
+	}, "id", "")
+	assert.Equal(t, "stop", err.Error(), "the error of the callback should be returned")
+	assert.Equal(t, []string{"John", "Lee"}, names, "The names should be equal")
+}
+
+func TestPaginateCursorPaginate(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Select("id", "name", "vote").
+		OrderBy("id")
+
+	// the first page
+	paginator := qb.MustCursorPaginate(3, "")
+	assert.Equal(t, 3, len(paginator.Items), "The items count should be 3")
+	assert.Equal(t, int64(1), paginator.Items[0].(xun.R).Get("id").(int64), "The id of the 1st item should be 1")
+	assert.Equal(t, 0, paginator.Total, "The total should not be counted")
+	assert.NotEqual(t, "", paginator.NextCursor, "The next cursor should be returned")
+	assert.Equal(t, "", paginator.PrevCursor, "The previous cursor should be empty")
+
+	// the next page
+	paginator = qb.MustCursorPaginate(3, paginator.NextCursor)
+	assert.Equal(t, 1, len(paginator.Items), "The items count should be 1")
+	assert.Equal(t, int64(4), paginator.Items[0].(xun.R).Get("id").(int64), "The id of the 1st item should be 4")
+	assert.Equal(t, "", paginator.NextCursor, "The next cursor should be empty")
+	assert.NotEqual(t, "", paginator.PrevCursor, "The previous cursor should be returned")
+
+	// the previous page
+	paginator = qb.MustCursorPaginate(3, paginator.PrevCursor)
+	IDs := []int64{}
+	for _, item := range paginator.Items {
+		IDs = append(IDs, item.(xun.R).Get("id").(int64))
+	}
+	assert.Equal(t, []int64{1, 2, 3}, IDs, "The ids of the items should be []int64{1,2,3}")
+	assert.NotEqual(t, "", paginator.NextCursor, "The next cursor should be returned")
+	assert.Equal(t, "", paginator.PrevCursor, "The previous cursor should be empty")
+}
+
+func TestPaginateCursorPaginateMultipleColumns(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Select("id", "name", "status").
+		OrderBy("status").
+		OrderByDesc("id")
+
+	names := []string{}
+	cursor := ""
+	for {
+		paginator := qb.MustCursorPaginate(1, cursor)
+		for _, item := range paginator.Items {
+			names = append(names, item.(xun.R).Get("name").(string))
+		}
+		if paginator.NextCursor == "" {
+			break
+		}
+		cursor = paginator.NextCursor
+	}
+	// the enum columns are sorted by the index of the values in MySQL
+	if unit.DriverIs("mysql") {
+		assert.Equal(t, []string{"John", "Lee", "Ben", "Ken"}, names, "The names should be ordered by status and id desc")
+	} else {
+		assert.Equal(t, []string{"Ben", "Ken", "Lee", "John"}, names, "The names should be ordered by status and id desc")
+	}
+}
+
+func TestPaginateCursorPaginateTime(t *testing.T) {
+	NewTableForPaginateTest()
+	created := time.Date(2021, 3, 25, 0, 21, 16, 0, time.UTC)
+	for i, email := range []string{"john@yao.run", "lee@yao.run", "ken@yao.run", "ben@yao.run"} {
+		getTestBuilder().Table("table_test_paginate").
+			Where("email", email).
+			MustUpdate(xun.R{"created_at": created.Add(time.Duration(i/2) * time.Hour)})
+	}
+
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Select("id", "name", "created_at").
+		OrderByDesc("created_at").
+		OrderByDesc("id")
+
+	names := []string{}
+	cursor := ""
+	for i := 0; i < 5; i++ {
+		paginator := qb.MustCursorPaginate(1, cursor)
+		for _, item := range paginator.Items {
+			names = append(names, item.(xun.R).Get("name").(string))
+		}
+		if paginator.NextCursor == "" {
+			break
+		}
+		cursor = paginator.NextCursor
+	}
+	assert.Equal(t, []string{"Ben", "Ken", "Lee", "John"}, names, "The names should be ordered by created_at desc and id desc")
+
+	// the time values are decoded with the nanoseconds
+	value := time.Date(2021, 3, 25, 0, 21, 16, 123456789, time.UTC)
+	builder := getTestBuilder().Table("table_test_paginate").OrderBy("created_at").Builder()
+	cursor, err := builder.encodeCursor([]string{"created_at"}, xun.R{"created_at": value}, true)
+	assert.Nil(t, err, "the return error should be nil")
+	payload, err := builder.decodeCursor(cursor)
+	assert.Nil(t, err, "the return error should be nil")
+	assert.Equal(t, value, payload.Values["created_at"], "the time value should be decoded as time.Time")
+}
+
+func TestPaginateCursorPaginateWithBind(t *testing.T) {
+	type Item struct {
+		ID   int64
+		Name string
+	}
+
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Select("id", "name").
+		OrderByDesc("id")
+
+	paginator := qb.MustCursorPaginate(2, "", &[]Item{})
+	assert.Equal(t, 2, len(paginator.Items), "The items count should be 2")
+	assert.Equal(t, "Ben", paginator.Items[0].(Item).Name, "The name of the 1st item should be Ben")
+
+	paginator = qb.MustCursorPaginate(2, paginator.NextCursor, &[]Item{})
+	assert.Equal(t, 2, len(paginator.Items), "The items count should be 2")
+	assert.Equal(t, "Lee", paginator.Items[0].(Item).Name, "The name of the 1st item should be Lee")
+	assert.Equal(t, "", paginator.NextCursor, "The next cursor should be empty")
+}
+
+func TestPaginateCursorPaginateError(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").Select("id", "name")
+	_, err := qb.CursorPaginate(2, "")
+	assert.Equal(t, "You must specify an orderBy clause when using the cursor paginator", err.Error(), "the error should be returned")
+
+	qb.OrderBy("id")
+	_, err = qb.CursorPaginate(2, "invalid cursor")
+	assert.Equal(t, "The given cursor is invalid", err.Error(), "the error should be returned")
+
+	qb.Table("table_test_paginate").Select("name").OrderBy("id")
+	assert.PanicsWithError(t, "The cursor paginator was aborted because the id column is not present in the query result", func() {
+		qb.MustCursorPaginate(2, "")
+	})
+}
+
 // clean the test data
 func TestPaginateClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
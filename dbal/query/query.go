@@ -1,8 +1,10 @@
 package query
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/xun"
@@ -10,6 +12,12 @@ import (
 	"github.com/yaoapp/xun/utils"
 )
 
+// ErrRecordsNotFound the query of Sole has no matching records
+var ErrRecordsNotFound = errors.New("no records found")
+
+// ErrMultipleRecordsFound the query of Sole has more than one matching record
+var ErrMultipleRecordsFound = errors.New("multiple records found")
+
 // Table create a new statement and set from givn table
 func (builder *Builder) Table(name string) Query {
 	builder.Query = dbal.NewQuery()
@@ -19,8 +27,13 @@ func (builder *Builder) Table(name string) Query {
 
 // Get Execute the query as a "select" statement.
 func (builder *Builder) Get(v ...interface{}) ([]xun.R, error) {
-	db := builder.DB()
-	stmt, err := db.Prepare(builder.ToSQL())
+	err := builder.Grammar.CheckQuery(builder.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	db := builder.executor()
+	stmt, err := db.PrepareContext(builder.GetContext(), builder.ToSQL())
 	if err != nil {
 		defer log.With(log.F{"bindings": builder.GetBindings()}).Error(builder.ToSQL())
 		return nil, err
@@ -28,7 +41,7 @@ func (builder *Builder) Get(v ...interface{}) ([]xun.R, error) {
 
 	defer stmt.Close()
 
-	rows, err := stmt.Query(builder.GetBindings()...)
+	rows, err := stmt.QueryContext(builder.GetContext(), builder.GetBindings()...)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +91,12 @@ func (builder *Builder) ToSQL() string {
 	return builder.Grammar.CompileSelect(builder.Query)
 }
 
+// ToRawSQL Get the SQL representation of the query with the bindings interpolated.
+// The bindings are quoted and escaped by the grammar, eg: select * from `users` where `email` = 'john@yao.run'
+func (builder *Builder) ToRawSQL() string {
+	return builder.Grammar.Interpolate(builder.ToSQL(), builder.GetBindings())
+}
+
 // GetBindings Get the current query value bindings in a flattened array.
 func (builder *Builder) GetBindings() []interface{} {
 	return builder.Query.GetBindings()
@@ -85,10 +104,15 @@ func (builder *Builder) GetBindings() []interface{} {
 
 // Exists Determine if any rows exist for the current query.
 func (builder *Builder) Exists() (bool, error) {
+	err := builder.Grammar.CheckQuery(builder.Query)
+	if err != nil {
+		return false, err
+	}
+
 	sql := builder.Grammar.CompileExists(builder.Query)
 
-	db := builder.DB()
-	rows, err := db.Query(sql, builder.GetBindings()...)
+	db := builder.executor()
+	rows, err := db.QueryContext(builder.GetContext(), sql, builder.GetBindings()...)
 	if err != nil {
 		return false, err
 	}
@@ -163,10 +187,135 @@ func (builder *Builder) MustValue(column string, v ...interface{}) interface{} {
 	return res
 }
 
-// Pluck Get an array with the values of a given column.
-func (builder *Builder) Pluck() {
+// Pluck Get an array with the values of a given column, or a map keyed by the given key column.
+// Pluck("email") []interface{}{"john@yao.run", "lee@yao.run"}
+// Pluck("email", "id") map[interface{}]interface{}{1: "john@yao.run", 2: "lee@yao.run"}
+// Pluck("email", &emails) emails []string{"john@yao.run", "lee@yao.run"}
+// Pluck("email", "id", &emails) emails map[int]string{1: "john@yao.run", 2: "lee@yao.run"}
+func (builder *Builder) Pluck(column string, args ...interface{}) (interface{}, error) {
+	key, v := builder.preparePluckArgs(args...)
+	if key == "" {
+		builder.Select(column)
+	} else {
+		builder.Select(column, key)
+	}
+
+	rows, err := builder.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	name := builder.getColumnName(column)
+	keyName := builder.getColumnName(key)
+	if v != nil {
+		return nil, builder.pluckBind(rows, name, keyName, v)
+	}
+
+	if key == "" {
+		res := []interface{}{}
+		for _, row := range rows {
+			res = append(res, row.Get(name))
+		}
+		return res, nil
+	}
+
+	res := map[interface{}]interface{}{}
+	for _, row := range rows {
+		res[row.Get(keyName)] = row.Get(name)
+	}
+	return res, nil
+}
+
+// MustPluck Get an array with the values of a given column, or a map keyed by the given key column.
+func (builder *Builder) MustPluck(column string, args ...interface{}) interface{} {
+	res, err := builder.Pluck(column, args...)
+	utils.PanicIF(err)
+	return res
+}
+
+// Implode Concatenate values of a given column as a string.
+// Implode("email", ",") "john@yao.run,lee@yao.run"
+func (builder *Builder) Implode(column string, glue string) (string, error) {
+	res, err := builder.Pluck(column)
+	if err != nil {
+		return "", err
+	}
+
+	values := []string{}
+	for _, value := range res.([]interface{}) {
+		if value == nil {
+			values = append(values, "")
+			continue
+		}
+		values = append(values, fmt.Sprintf("%v", value))
+	}
+	return strings.Join(values, glue), nil
+}
+
+// MustImplode Concatenate values of a given column as a string.
+func (builder *Builder) MustImplode(column string, glue string) string {
+	res, err := builder.Implode(column, glue)
+	utils.PanicIF(err)
+	return res
+}
+
+// Sole Execute the query and get the first result if it's the sole matching record.
+// Returns ErrRecordsNotFound when no record matches, ErrMultipleRecordsFound when more than one record matches.
+// Sole() xun.R{"id": 1, "email": "john@yao.run"}
+// Sole(&user) user Item{ID: 1, Email: "john@yao.run"}
+func (builder *Builder) Sole(v ...interface{}) (xun.R, error) {
+	builder.Take(2)
+	if len(v) == 1 && v[0] != nil {
+		return xun.MakeR(), builder.soleBind(v[0])
+	}
+
+	rows, err := builder.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrRecordsNotFound
+	} else if len(rows) > 1 {
+		return nil, ErrMultipleRecordsFound
+	}
+	return rows[0], nil
 }
 
-// MustPluck Get an array with the values of a given column.
-func (builder *Builder) MustPluck() {
+// MustSole Execute the query and get the first result if it's the sole matching record.
+func (builder *Builder) MustSole(v ...interface{}) xun.R {
+	res, err := builder.Sole(v...)
+	utils.PanicIF(err)
+	return res
+}
+
+// KeyBy Execute the query and key the results by the given column.
+// KeyBy("id") map[interface{}]xun.R{1: {"id": 1, "email": "john@yao.run"}, 2: {"id": 2, "email": "lee@yao.run"}}
+// KeyBy("id", &users) users map[int]Item{1: {ID: 1, Email: "john@yao.run"}, 2: {ID: 2, Email: "lee@yao.run"}}
+func (builder *Builder) KeyBy(column string, v ...interface{}) (map[interface{}]xun.R, error) {
+	name := builder.getColumnName(column)
+	if len(v) == 1 && v[0] != nil {
+		return nil, builder.keyByBind(name, v[0])
+	}
+
+	rows, err := builder.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[interface{}]xun.R{}
+	for _, row := range rows {
+		if !row.Has(name) {
+			return nil, fmt.Errorf("the column %s does not exist in the results", name)
+		}
+		res[row.Get(name)] = row
+	}
+	return res, nil
+}
+
+// MustKeyBy Execute the query and key the results by the given column.
+func (builder *Builder) MustKeyBy(column string, v ...interface{}) map[interface{}]xun.R {
+	res, err := builder.KeyBy(column, v...)
+	utils.PanicIF(err)
+	return res
 }
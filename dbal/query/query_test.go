@@ -1,8 +1,10 @@
 package query
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/yaoapp/xun"
@@ -112,6 +114,40 @@ func TestQueryMustExistsTrue(t *testing.T) {
 	assert.True(t, res, "the return value should be true")
 }
 
+func TestQueryGetWithContext(t *testing.T) {
+	NewTableForQueryTest()
+	qb := getTestBuilder()
+	rows, err := qb.New().WithContext(context.Background()).
+		From("table_test_query").
+		Where("email", "like", "%@yao.run").
+		Get()
+	assert.Nil(t, err, "the return error should be nil")
+	assert.Equal(t, 4, len(rows), "the return value should has 4 items")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = qb.New().WithContext(ctx).
+		From("table_test_query").
+		Where("email", "like", "%@yao.run").
+		Get()
+	assert.ErrorIs(t, err, context.Canceled, "the return error should be context canceled")
+
+	_, err = qb.New().WithContext(ctx).
+		Table("table_test_query").
+		Where("email", "like", "%@yao.run").
+		Update(xun.R{"vote": 1})
+	assert.ErrorIs(t, err, context.Canceled, "the return error should be context canceled")
+
+	// the context of the shared builder should not be changed
+	shared := qb.New()
+	shared.WithContext(ctx)
+	rows, err = shared.From("table_test_query").
+		Where("email", "like", "%@yao.run").
+		Get()
+	assert.Nil(t, err, "the return error should be nil")
+	assert.Equal(t, 4, len(rows), "the return value should has 4 items")
+}
+
 func TestQueryMustExistsFalse(t *testing.T) {
 	NewTableForQueryTest()
 	qb := getTestBuilder()
@@ -279,6 +315,178 @@ func TestQueryMustValueBind(t *testing.T) {
 
 }
 
+func TestQueryMustPluck(t *testing.T) {
+	NewTableForQueryTest()
+	emails := getTestBuilder().Table("table_test_query as t").OrderBy("id").MustPluck("t.email")
+	assert.Equal(t, []interface{}{"john@yao.run", "lee@yao.run", "ken@yao.run", "ben@yao.run"}, emails, "the emails should be plucked")
+
+	names := getTestBuilder().Table("table_test_query as t").Where("vote", ">", 5).MustPluck("name as title", "email").(map[interface{}]interface{})
+	assert.Equal(t, 3, len(names), "the return value should have 3 items")
+	assert.Equal(t, "Ken", names["ken@yao.run"], "the name of ken@yao.run should be Ken")
+}
+
+func TestQueryMustPluckBind(t *testing.T) {
+	NewTableForQueryTest()
+	emails := []string{}
+	getTestBuilder().Table("table_test_query as t").OrderBy("id").MustPluck("email", &emails)
+	assert.Equal(t, []string{"john@yao.run", "lee@yao.run", "ken@yao.run", "ben@yao.run"}, emails, "the emails should be plucked")
+
+	votes := map[string]int{}
+	getTestBuilder().Table("table_test_query as t").MustPluck("vote", "email", &votes)
+	assert.Equal(t, 4, len(votes), "the votes should have 4 items")
+	assert.Equal(t, 125, votes["ken@yao.run"], "the vote of ken@yao.run should be 125")
+}
+
+func TestQueryMustPluckBindError(t *testing.T) {
+	NewTableForQueryTest()
+	assert.Panics(t, func() {
+		votes := map[string]int{}
+		getTestBuilder().Table("table_test_query as t").MustPluck("vote", &votes)
+	})
+	assert.Panics(t, func() {
+		votes := []int{}
+		getTestBuilder().Table("table_test_query as t").MustPluck("email", &votes)
+	})
+}
+
+func TestQueryMustImplode(t *testing.T) {
+	NewTableForQueryTest()
+	emails := getTestBuilder().Table("table_test_query as t").Where("vote", "<", 100).OrderBy("id").MustImplode("email", ",")
+	assert.Equal(t, "john@yao.run,lee@yao.run,ben@yao.run", emails, "the emails should be imploded")
+
+	empty := getTestBuilder().Table("table_test_query as t").Where("vote", ">", 1000).MustImplode("email", ",")
+	assert.Equal(t, "", empty, "the return value should be empty")
+}
+
+func TestQueryMustSole(t *testing.T) {
+	NewTableForQueryTest()
+	row := getTestBuilder().Table("table_test_query as t").Where("email", "ken@yao.run").MustSole()
+	assert.Equal(t, "Ken", row.Get("name"), "the name should be Ken")
+}
+
+func TestQueryMustSoleError(t *testing.T) {
+	NewTableForQueryTest()
+	_, err := getTestBuilder().Table("table_test_query as t").Where("email", "unknown@yao.run").Sole()
+	assert.Equal(t, ErrRecordsNotFound, err, "the error should be ErrRecordsNotFound")
+
+	_, err = getTestBuilder().Table("table_test_query as t").Where("status", "DONE").Sole()
+	assert.Equal(t, ErrMultipleRecordsFound, err, "the error should be ErrMultipleRecordsFound")
+
+	assert.Panics(t, func() {
+		getTestBuilder().Table("table_test_query as t").Where("status", "DONE").MustSole()
+	})
+}
+
+func TestQueryMustSoleBind(t *testing.T) {
+	NewTableForQueryTest()
+	type Item struct {
+		ID    int64
+		Email string
+		Vote  int
+	}
+
+	row := Item{}
+	getTestBuilder().Table("table_test_query as t").
+		Select("id", "email", "vote").
+		Where("email", "ken@yao.run").
+		MustSole(&row)
+	assert.Equal(t, "ken@yao.run", row.Email, "the email should be ken@yao.run")
+	assert.Equal(t, 125, row.Vote, "the vote should be 125")
+
+	_, err := getTestBuilder().Table("table_test_query as t").
+		Select("id", "email", "vote").
+		Where("status", "DONE").
+		Sole(&row)
+	assert.Equal(t, ErrMultipleRecordsFound, err, "the error should be ErrMultipleRecordsFound")
+}
+
+func TestQueryMustKeyBy(t *testing.T) {
+	NewTableForQueryTest()
+	rows := getTestBuilder().Table("table_test_query as t").MustKeyBy("email")
+	assert.Equal(t, 4, len(rows), "the return value should have 4 items")
+	assert.Equal(t, "Ken", rows["ken@yao.run"].Get("name"), "the name of ken@yao.run should be Ken")
+
+	assert.Panics(t, func() {
+		getTestBuilder().Table("table_test_query as t").MustKeyBy("unknown")
+	})
+}
+
+func TestQueryMustKeyByBind(t *testing.T) {
+	NewTableForQueryTest()
+	type Item struct {
+		ID    int64
+		Email string
+		Vote  int
+	}
+
+	rows := map[string]Item{}
+	getTestBuilder().Table("table_test_query as t").
+		Select("id", "email", "vote").
+		MustKeyBy("email", &rows)
+	assert.Equal(t, 4, len(rows), "the return value should have 4 items")
+	assert.Equal(t, 125, rows["ken@yao.run"].Vote, "the vote of ken@yao.run should be 125")
+}
+
+func TestQueryToRawSQL(t *testing.T) {
+	NewTableForQueryTest()
+	qb := getTestBuilder()
+	updatedAt := xun.MakeTime("2021-03-25 09:40:23")
+	scoreGrade := xun.MakeN(12.5)
+	qb.Table("table_test_query").
+		WhereRaw("name <> 'why?'").
+		Where(func(qb Query) {
+			qb.Where("email", "it's\\@yao.run").
+				OrWhere("vote", ">", 100).
+				OrWhere("score", "<", 50.5).
+				OrWhere("created_at", "<", time.Date(2021, 3, 25, 1, 0, 0, 0, time.UTC)).
+				OrWhere("updated_at", &updatedAt).
+				OrWhere("score_grade", &scoreGrade)
+		})
+
+	sql := qb.ToRawSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_query" where name <> 'why?' and ("email" = 'it''s\@yao.run' or "vote" > 100 or "score" < 50.5 or "created_at" < '2021-03-25 01:00:00' or "updated_at" = '2021-03-25 09:40:23' or "score_grade" = 12.5)`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select * from `table_test_query` where name <> 'why?' and (`email` = 'it''s\\@yao.run' or `vote` > 100 or `score` < 50.5 or `created_at` < '2021-03-25 01:00:00' or `updated_at` = '2021-03-25 09:40:23' or `score_grade` = 12.5)", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_query` where name <> 'why?' and (`email` = 'it\\'s\\\\@yao.run' or `vote` > 100 or `score` < 50.5 or `created_at` < '2021-03-25 01:00:00' or `updated_at` = '2021-03-25 09:40:23' or `score_grade` = 12.5)", sql, "the query sql not equal")
+	}
+
+	rows := qb.MustGet()
+	res, err := qb.DB().Queryx(sql)
+	assert.Nil(t, err, "the raw SQL should be executed")
+	count := 0
+	for res.Next() {
+		count++
+	}
+	res.Close()
+	assert.Equal(t, len(rows), count, "the raw SQL should return the same rows")
+}
+
+func TestQueryToRawSQLValues(t *testing.T) {
+	NewTableForQueryTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_query").
+		Where("vote", 10).
+		Where("status", "?").
+		WhereIn("name", []interface{}{"John", nil, true})
+
+	sql := qb.ToRawSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_query" where "vote" = 10 and "status" = '?' and "name" in ('John',NULL,true)`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_query` where `vote` = 10 and `status` = '?' and `name` in ('John',NULL,1)", sql, "the query sql not equal")
+	}
+
+	raw := qb.Builder().Grammar.Interpolate("select ? as `data`", []interface{}{[]byte("Lee")})
+	if unit.DriverIs("postgres") {
+		raw = qb.Builder().Grammar.Interpolate(`select $1 as "data"`, []interface{}{[]byte("Lee")})
+		assert.Equal(t, `select '\x4c6565'::bytea as "data"`, raw, "the raw sql not equal")
+	} else {
+		assert.Equal(t, "select X'4c6565' as `data`", raw, "the raw sql not equal")
+	}
+}
+
 // clean the test data
 func TestQueryClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
@@ -0,0 +1,39 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun"
+)
+
+// Returning Set the columns that should be returned by the InsertReturning, UpdateReturning, UpsertReturning and DeleteReturning methods.
+// Returning("id", "email")
+// Returning("id,email")
+func (builder *Builder) Returning(columns ...interface{}) Query {
+	builder.Query.Returning = builder.prepareColumns(columns...)
+	return builder
+}
+
+// execReturning Execute the given statement with the "returning" clause and get the returned rows.
+func (builder *Builder) execReturning(sql string, bindings []interface{}) ([]xun.R, error) {
+	returning, err := builder.Grammar.CompileReturning(builder.Query, builder.Query.Returning)
+	if err != nil {
+		return nil, err
+	}
+
+	sql = fmt.Sprintf("%s %s", sql, returning)
+	defer log.With(log.F{"bindings": bindings}).Debug(sql)
+
+	stmt, err := builder.executor(true).PrepareContext(builder.GetContext(), sql)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(builder.GetContext(), bindings...)
+	if err != nil {
+		return nil, err
+	}
+	return builder.mapScan(rows)
+}
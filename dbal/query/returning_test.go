@@ -0,0 +1,182 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/grammar/sqlite3"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestReturningInsertReturning(t *testing.T) {
+	NewTableForReturningTest()
+	qb := getTestBuilder()
+	rows, err := qb.Table("table_test_returning").
+		Returning("id", "email").
+		InsertReturning([]xun.R{
+			{"email": "max@yao.run", "vote": 1},
+			{"email": "ken@yao.run", "vote": 2},
+		})
+
+	if !isReturningSupported() {
+		assert.Contains(t, err.Error(), "does not support returning", "the error should be returned")
+		assert.Equal(t, int64(2), qb.Table("table_test_returning").MustCount(), "the rows count should be 2")
+		return
+	}
+
+	assert.Nil(t, err, "the insert returning should be executed")
+	assert.Equal(t, 2, len(rows), "the return value should has 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, int64(3), rows[0]["id"].(int64), "the id of the 1st row should be 3")
+		assert.Equal(t, "max@yao.run", rows[0]["email"].(string), "the email of the 1st row should be max@yao.run")
+		assert.Nil(t, rows[0]["vote"], "the vote column should not be returned")
+		assert.Equal(t, "ken@yao.run", rows[1]["email"].(string), "the email of the 2nd row should be ken@yao.run")
+	}
+}
+
+func TestReturningUpdateReturning(t *testing.T) {
+	NewTableForReturningTest()
+	qb := getTestBuilder()
+	rows, err := qb.Table("table_test_returning").
+		Where("email", "john@yao.run").
+		UpdateReturning(xun.R{"vote": 20})
+
+	if !isReturningSupported() {
+		assert.Contains(t, err.Error(), "does not support returning", "the error should be returned")
+		return
+	}
+
+	assert.Nil(t, err, "the update returning should be executed")
+	assert.Equal(t, 1, len(rows), "the return value should has 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, "john@yao.run", rows[0]["email"].(string), "the email of the row should be john@yao.run")
+		assert.Equal(t, int64(20), rows[0]["vote"].(int64), "the vote of the row should be 20")
+	}
+}
+
+func TestReturningUpsertReturning(t *testing.T) {
+	NewTableForReturningTest()
+	qb := getTestBuilder()
+	rows, err := qb.Table("table_test_returning").
+		Returning("email, vote").
+		UpsertReturning([]xun.R{
+			{"email": "john@yao.run", "vote": 30},
+			{"email": "max@yao.run", "vote": 1},
+		}, "email", []string{"vote"})
+
+	if !isReturningSupported() {
+		assert.Contains(t, err.Error(), "does not support returning", "the error should be returned")
+		return
+	}
+
+	assert.Nil(t, err, "the upsert returning should be executed")
+	assert.Equal(t, 2, len(rows), "the return value should has 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, int64(30), rows[0]["vote"].(int64), "the vote of john should be 30")
+		assert.Equal(t, "max@yao.run", rows[1]["email"].(string), "the email of the 2nd row should be max@yao.run")
+	}
+}
+
+func TestReturningDeleteReturning(t *testing.T) {
+	NewTableForReturningTest()
+	qb := getTestBuilder()
+	rows, err := qb.Table("table_test_returning").
+		Where("vote", "<", 10).
+		Returning("email").
+		DeleteReturning()
+
+	if !isReturningSupported() {
+		assert.Contains(t, err.Error(), "does not support returning", "the error should be returned")
+		assert.Equal(t, int64(2), qb.Table("table_test_returning").MustCount(), "the rows count should be 2")
+		return
+	}
+
+	assert.Nil(t, err, "the delete returning should be executed")
+	assert.Equal(t, 1, len(rows), "the return value should has 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, "lee@yao.run", rows[0]["email"].(string), "the email of the deleted row should be lee@yao.run")
+	}
+	assert.Equal(t, int64(1), qb.Table("table_test_returning").MustCount(), "the rows count should be 1")
+}
+
+func TestReturningCheckQuery(t *testing.T) {
+	if !unit.DriverIs("sqlite3") {
+		return
+	}
+
+	NewTableForReturningTest()
+	lateral := func(qb Query) {
+		qb.From("table_test_returning as t2").WhereColumn("t2.id", "table_test_returning.id").Limit(1)
+	}
+	_, err := getTestBuilder().Table("table_test_returning").
+		JoinLateral(lateral, "latest").
+		Returning("email").
+		UpdateReturning(xun.R{"vote": 1})
+	assert.Contains(t, err.Error(), "does not support lateral joins", "the error should be returned")
+
+	_, err = getTestBuilder().Table("table_test_returning").
+		JoinLateral(lateral, "latest").
+		Returning("email").
+		DeleteReturning()
+	assert.Contains(t, err.Error(), "does not support lateral joins", "the error should be returned")
+	assert.Equal(t, int64(2), getTestBuilder().Table("table_test_returning").MustCount(), "the rows count should be 2")
+}
+
+func TestReturningSQLiteVersion(t *testing.T) {
+	grammar := dbal.Grammars["sqlite3"].(sqlite3.SQLite3)
+	query := dbal.NewQuery()
+
+	grammar.Version = &dbal.Version{Version: semver.MustParse("3.35.0"), Driver: "sqlite3"}
+	sql, err := grammar.CompileReturning(query, []interface{}{"id", "email"})
+	assert.Nil(t, err, "the return error should be nil")
+	assert.Equal(t, "returning `id`, `email`", sql, "the returning sql not equal")
+
+	sql, err = grammar.CompileReturning(query, []interface{}{})
+	assert.Nil(t, err, "the return error should be nil")
+	assert.Equal(t, "returning *", sql, "the returning sql not equal")
+
+	grammar.Version = &dbal.Version{Version: semver.MustParse("3.34.1"), Driver: "sqlite3"}
+	_, err = grammar.CompileReturning(query, []interface{}{"id"})
+	assert.Contains(t, err.Error(), "SQLite 3.35.0+ is required (current: 3.34.1)", "the error should be returned")
+}
+
+// clean the test data
+func TestReturningClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_returning")
+}
+
+func NewTableForReturningTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_returning")
+	builder.MustCreateTable("table_test_returning", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.Integer("vote")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_returning").Insert([]xun.R{
+		{"email": "john@yao.run", "vote": 10},
+		{"email": "lee@yao.run", "vote": 5},
+	})
+}
+
+// isReturningSupported PostgreSQL and SQLite 3.35.0+ support the returning clause
+func isReturningSupported() bool {
+	if unit.DriverIs("postgres") {
+		return true
+	} else if unit.DriverIs("sqlite3") {
+		version, err := getTestBuilder().Builder().Grammar.GetVersion()
+		if err != nil {
+			return false
+		}
+		return version.GE(semver.MustParse("3.35.0"))
+	}
+	return false
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"unsafe"
 
+	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/utils"
@@ -68,6 +69,74 @@ func (builder *Builder) prepareInsertValues(v interface{}, columns ...interface{
 	return columns, insertValues
 }
 
+// chunkValues split the rows into the chunks which fit the placeholder limit of the database.
+// placeholders is the number of the placeholders of each row, reserved is the number of the other placeholders in the statement.
+func (builder *Builder) chunkValues(values [][]interface{}, placeholders int, reserved int) [][][]interface{} {
+	size := len(values)
+	if placeholders > 0 {
+		size = (builder.Grammar.GetMaxPlaceholders() - reserved) / placeholders
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	if len(values) <= size {
+		return [][][]interface{}{values}
+	}
+
+	chunks := [][][]interface{}{}
+	for start := 0; start < len(values); start = start + size {
+		end := start + size
+		if end > len(values) {
+			end = len(values)
+		}
+		chunks = append(chunks, values[start:end])
+	}
+	return chunks
+}
+
+// execChunks execute the statement of each chunk, the chunks are executed within one transaction and the affected rows are summed.
+func (builder *Builder) execChunks(chunks [][][]interface{}, exec func(qb *Builder, values [][]interface{}) (int64, error)) (int64, error) {
+	if len(chunks) == 1 {
+		return exec(builder, chunks[0])
+	}
+
+	var affected int64 = 0
+	err := builder.Transaction(func(tx Query) error {
+		for _, chunk := range chunks {
+			res, err := exec(tx.Builder(), chunk)
+			if err != nil {
+				return err
+			}
+			affected = affected + res
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// execAffected execute the statement and get the number of the affected rows
+func (builder *Builder) execAffected(sql string, bindings []interface{}) (int64, error) {
+	defer log.With(log.F{"bindings": bindings}).Debug(sql)
+
+	stmt, err := builder.executor(true).PrepareContext(builder.GetContext(), sql)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(builder.GetContext(), bindings...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
 // prepareColumns parepare the select columns
 // Select("field1", "field2")
 // Select("field1", "field2 as f2")
@@ -131,6 +200,170 @@ func (builder *Builder) prepareFindArgs(v ...interface{}) (interface{}, interfac
 	return column, ptr
 }
 
+func (builder *Builder) preparePluckArgs(v ...interface{}) (string, interface{}) {
+	var ptr interface{} = nil
+	var key = ""
+	for _, arg := range v {
+		if arg == nil {
+			continue
+		}
+		if value, ok := arg.(string); ok {
+			key = value
+		} else if reflect.TypeOf(arg).Kind() == reflect.Ptr {
+			ptr = arg
+		}
+	}
+	return key, ptr
+}
+
+// getColumnName get the result column name of the given column. "t.email as mail" => "mail", "t.email" => "email"
+func (builder *Builder) getColumnName(column string) string {
+	name := strings.TrimSpace(column)
+	if index := strings.LastIndex(strings.ToLower(name), " as "); index >= 0 {
+		name = strings.TrimSpace(name[index+4:])
+	}
+	if strings.Contains(name, ".") {
+		segments := strings.Split(name, ".")
+		name = segments[len(segments)-1]
+	}
+	return name
+}
+
+// pluckBind bind the plucked values to the given slice or map pointer
+func (builder *Builder) pluckBind(rows []xun.R, name string, keyName string, v interface{}) error {
+	vPtr := reflect.ValueOf(v)
+	if vPtr.Kind() != reflect.Ptr || vPtr.IsNil() {
+		return fmt.Errorf("The dest type is %s, it should be a pointer", vPtr.Kind().String())
+	}
+
+	target := vPtr.Elem()
+	switch target.Kind() {
+	case reflect.Slice:
+		values := reflect.MakeSlice(target.Type(), 0, len(rows))
+		for _, row := range rows {
+			value, err := builder.convertValue(row.Get(name), target.Type().Elem())
+			if err != nil {
+				return err
+			}
+			values = reflect.Append(values, value)
+		}
+		target.Set(values)
+		return nil
+
+	case reflect.Map:
+		if keyName == "" {
+			return fmt.Errorf("the key column should be given when the dest type is map")
+		}
+		values := reflect.MakeMapWithSize(target.Type(), len(rows))
+		for _, row := range rows {
+			key, err := builder.convertValue(row.Get(keyName), target.Type().Key())
+			if err != nil {
+				return err
+			}
+			value, err := builder.convertValue(row.Get(name), target.Type().Elem())
+			if err != nil {
+				return err
+			}
+			values.SetMapIndex(key, value)
+		}
+		target.Set(values)
+		return nil
+	}
+
+	return fmt.Errorf("The dest type is %s, it should be a pointer of slice or map", target.Kind().String())
+}
+
+// soleBind bind the sole matching record to the given struct pointer
+func (builder *Builder) soleBind(v interface{}) error {
+	structType, vStruct, err := builder.getStructType(v)
+	if err != nil {
+		return err
+	}
+
+	if !vStruct || reflect.Indirect(reflect.ValueOf(v)).Kind() != reflect.Struct {
+		return fmt.Errorf("The dest type is %s, it should be a pointer of struct", structType.Kind().String())
+	}
+
+	rows := reflect.New(reflect.SliceOf(structType))
+	_, err = builder.Get(rows.Interface())
+	if err != nil {
+		return err
+	}
+
+	if rows.Elem().Len() == 0 {
+		return ErrRecordsNotFound
+	} else if rows.Elem().Len() > 1 {
+		return ErrMultipleRecordsFound
+	}
+
+	reflect.ValueOf(v).Elem().Set(rows.Elem().Index(0))
+	return nil
+}
+
+// keyByBind bind the records keyed by the given column to the given map pointer
+func (builder *Builder) keyByBind(name string, v interface{}) error {
+	vPtr := reflect.ValueOf(v)
+	if vPtr.Kind() != reflect.Ptr || vPtr.IsNil() || vPtr.Elem().Kind() != reflect.Map {
+		return fmt.Errorf("The dest type is %s, it should be a pointer of map", vPtr.Kind().String())
+	}
+
+	target := vPtr.Elem()
+	structType := target.Type().Elem()
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("The map value type is %s, it should be a struct", structType.Kind().String())
+	}
+
+	fieldMap, err := builder.getFieldMap(structType)
+	if err != nil {
+		return err
+	}
+
+	field, has := fieldMap[name]
+	if !has {
+		return fmt.Errorf("the struct %s does not have the %s field", structType.Name(), name)
+	}
+
+	rows := reflect.New(reflect.SliceOf(structType))
+	_, err = builder.Get(rows.Interface())
+	if err != nil {
+		return err
+	}
+
+	values := reflect.MakeMapWithSize(target.Type(), rows.Elem().Len())
+	for i := 0; i < rows.Elem().Len(); i++ {
+		row := rows.Elem().Index(i)
+		key, err := builder.convertValue(row.FieldByName(field.Name).Interface(), target.Type().Key())
+		if err != nil {
+			return err
+		}
+		values.SetMapIndex(key, row)
+	}
+	target.Set(values)
+	return nil
+}
+
+// convertValue convert the given value to the given type
+func (builder *Builder) convertValue(value interface{}, typ reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(typ), nil
+	}
+
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Type().AssignableTo(typ) {
+		return reflectValue, nil
+	}
+
+	if typ.Kind() == reflect.String {
+		return reflect.ValueOf(fmt.Sprintf("%v", value)).Convert(typ), nil
+	}
+
+	if reflectValue.Kind() != reflect.String && reflectValue.Type().ConvertibleTo(typ) {
+		return reflectValue.Convert(typ), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("can't convert %#v to %s", value, typ.String())
+}
+
 // Parse the subquery into SQL and bindings.
 func (builder *Builder) parseSub(sub interface{}) string {
 	switch sub.(type) {
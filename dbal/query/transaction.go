@@ -0,0 +1,158 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun/utils"
+)
+
+// Transaction Execute a Closure within a transaction. The transaction will be committed if the closure returns nil, otherwise it will be rolled back.
+// If the query builder is already in a transaction, a savepoint will be used.
+func (builder *Builder) Transaction(callback func(tx Query) error) (err error) {
+	tx, err := builder.beginTransaction()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	err = callback(tx)
+	if err != nil {
+		errRollback := tx.Rollback()
+		if errRollback != nil {
+			return fmt.Errorf("%s (rollback: %s)", err, errRollback)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MustTransaction Execute a Closure within a transaction.
+func (builder *Builder) MustTransaction(callback func(tx Query) error) {
+	err := builder.Transaction(callback)
+	utils.PanicIF(err)
+}
+
+// BeginTransaction Start a new database transaction. If the query builder is already in a transaction, a savepoint will be created.
+func (builder *Builder) BeginTransaction() (Query, error) {
+	return builder.beginTransaction()
+}
+
+// MustBeginTransaction Start a new database transaction.
+func (builder *Builder) MustBeginTransaction() Query {
+	tx, err := builder.BeginTransaction()
+	utils.PanicIF(err)
+	return tx
+}
+
+// Commit Commit the active database transaction, or release the savepoint of the nested transaction.
+func (builder *Builder) Commit() error {
+	if builder.Tx == nil {
+		return fmt.Errorf("the query builder is not in a transaction")
+	}
+
+	var err error
+	if builder.Tx.Level == 1 {
+		err = builder.Tx.Tx.Commit()
+	} else {
+		sql := builder.Grammar.CompileSavepointRelease(builder.savepoint())
+		defer log.Debug(sql)
+		_, err = builder.Tx.Tx.ExecContext(builder.GetContext(), sql)
+	}
+
+	if err != nil {
+		return err
+	}
+	builder.Tx = nil
+	builder.Grammar = builder.Grammar.WithTransaction(nil)
+	return nil
+}
+
+// MustCommit Commit the active database transaction.
+func (builder *Builder) MustCommit() {
+	err := builder.Commit()
+	utils.PanicIF(err)
+}
+
+// Rollback Rollback the active database transaction, or rollback to the savepoint of the nested transaction.
+func (builder *Builder) Rollback() error {
+	if builder.Tx == nil {
+		return fmt.Errorf("the query builder is not in a transaction")
+	}
+
+	var err error
+	if builder.Tx.Level == 1 {
+		err = builder.Tx.Tx.Rollback()
+	} else {
+		sql := builder.Grammar.CompileSavepointRollBack(builder.savepoint())
+		defer log.Debug(sql)
+		_, err = builder.Tx.Tx.ExecContext(builder.GetContext(), sql)
+	}
+
+	if err != nil {
+		return err
+	}
+	builder.Tx = nil
+	builder.Grammar = builder.Grammar.WithTransaction(nil)
+	return nil
+}
+
+// MustRollback Rollback the active database transaction.
+func (builder *Builder) MustRollback() {
+	err := builder.Rollback()
+	utils.PanicIF(err)
+}
+
+// InTransaction Determine if the query builder is in a transaction.
+func (builder *Builder) InTransaction() bool {
+	return builder.Tx != nil
+}
+
+// WithTransaction Create a new query builder instance, the statements of it will be executed within the given transaction.
+// The transaction is managed by the caller, the Commit and Rollback of the new instance will end the given transaction.
+func (builder *Builder) WithTransaction(tx *sqlx.Tx) Query {
+	new := builder.clone()
+	new.Query.UseWriteConnection = true
+	new.Tx = &Transaction{Tx: tx, Level: 1}
+	new.Grammar = builder.Grammar.WithTransaction(tx)
+	return new
+}
+
+// beginTransaction create a new builder instance within a transaction
+func (builder *Builder) beginTransaction() (*Builder, error) {
+	new := builder.clone()
+	new.Query.UseWriteConnection = true
+
+	// nested transaction
+	if builder.Tx != nil {
+		new.Tx = &Transaction{Tx: builder.Tx.Tx, Level: builder.Tx.Level + 1}
+		sql := builder.Grammar.CompileSavepoint(new.savepoint())
+		defer log.Debug(sql)
+		_, err := new.Tx.Tx.ExecContext(new.GetContext(), sql)
+		if err != nil {
+			return nil, err
+		}
+		return new, nil
+	}
+
+	tx, err := builder.Conn.Write.BeginTxx(builder.GetContext(), nil)
+	if err != nil {
+		return nil, err
+	}
+	new.Tx = &Transaction{Tx: tx, Level: 1}
+	new.Grammar = builder.Grammar.WithTransaction(tx)
+	return new, nil
+}
+
+// savepoint get the savepoint name of the nested transaction
+func (builder *Builder) savepoint() string {
+	return fmt.Sprintf("trans%d", builder.Tx.Level)
+}
@@ -0,0 +1,143 @@
+package query
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestTransactionCommit(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	err := qb.Transaction(func(tx Query) error {
+		tx.Table("table_test_transaction").MustInsert(xun.R{"email": "max@yao.run", "vote": 1})
+		tx.Table("table_test_transaction").Where("email", "john@yao.run").MustUpdate(xun.R{"vote": 20})
+		assert.True(t, tx.InTransaction(), "the query builder should be in a transaction")
+		return nil
+	})
+	assert.Nil(t, err, "the transaction should be committed")
+	assert.Equal(t, int64(3), qb.Table("table_test_transaction").MustCount(), "The rows count should be 3")
+	assert.Equal(t, int64(20), qb.Table("table_test_transaction").Where("email", "john@yao.run").MustValue("vote").(int64), "The vote of john should be 20")
+}
+
+func TestTransactionRollback(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	err := qb.Transaction(func(tx Query) error {
+		tx.Table("table_test_transaction").MustInsert(xun.R{"email": "max@yao.run", "vote": 1})
+		tx.Table("table_test_transaction").Where("email", "john@yao.run").MustDelete()
+		return errors.New("something wrong")
+	})
+	assert.Equal(t, "something wrong", err.Error(), "the error should be returned")
+	assert.Equal(t, int64(2), qb.Table("table_test_transaction").MustCount(), "The rows count should be 2")
+	assert.True(t, qb.Table("table_test_transaction").Where("email", "john@yao.run").MustExists(), "The row of john should be exists")
+}
+
+func TestTransactionRollbackWhenPanic(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	assert.Panics(t, func() {
+		qb.Transaction(func(tx Query) error {
+			tx.Table("table_test_transaction").MustInsert(xun.R{"email": "max@yao.run", "vote": 1})
+			tx.Table("table_test_transaction").MustInsert(xun.R{"email": "max@yao.run", "vote": 1})
+			return nil
+		})
+	})
+	assert.Equal(t, int64(2), qb.Table("table_test_transaction").MustCount(), "The rows count should be 2")
+}
+
+func TestTransactionNested(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	err := qb.Transaction(func(tx Query) error {
+		tx.Table("table_test_transaction").MustInsert(xun.R{"email": "max@yao.run", "vote": 1})
+
+		err := tx.Transaction(func(nested Query) error {
+			nested.Table("table_test_transaction").MustInsert(xun.R{"email": "ken@yao.run", "vote": 2})
+			return errors.New("rollback the savepoint")
+		})
+		assert.Equal(t, "rollback the savepoint", err.Error(), "the error should be returned")
+
+		return tx.Transaction(func(nested Query) error {
+			nested.Table("table_test_transaction").MustInsert(xun.R{"email": "ben@yao.run", "vote": 3})
+			return nil
+		})
+	})
+	assert.Nil(t, err, "the transaction should be committed")
+	assert.Equal(t, int64(4), qb.Table("table_test_transaction").MustCount(), "The rows count should be 4")
+	assert.False(t, qb.Table("table_test_transaction").Where("email", "ken@yao.run").MustExists(), "The row of ken should be rolled back")
+	assert.True(t, qb.Table("table_test_transaction").Where("email", "ben@yao.run").MustExists(), "The row of ben should be exists")
+}
+
+func TestTransactionBeginCommitRollback(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+
+	tx := qb.MustBeginTransaction()
+	tx.Table("table_test_transaction").MustInsert(xun.R{"email": "max@yao.run", "vote": 1})
+	tx.MustCommit()
+	assert.False(t, tx.InTransaction(), "the query builder should not be in a transaction")
+	assert.Equal(t, int64(3), qb.Table("table_test_transaction").MustCount(), "The rows count should be 3")
+
+	tx = qb.MustBeginTransaction()
+	tx.Table("table_test_transaction").MustInsert(xun.R{"email": "ken@yao.run", "vote": 1})
+	tx.MustRollback()
+	assert.Equal(t, int64(3), qb.Table("table_test_transaction").MustCount(), "The rows count should be 3")
+
+	assert.Panics(t, func() { tx.MustCommit() })
+	assert.Panics(t, func() { tx.MustRollback() })
+}
+
+func TestTransactionWithTransactionGrammar(t *testing.T) {
+	if unit.DriverIs("mysql") {
+		return // the DDL statements are committed implicitly
+	}
+
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	db := qb.Builder().Conn.Write
+	sqltx, err := db.Beginx()
+	assert.Nil(t, err, "the transaction should be started")
+	if err != nil {
+		return
+	}
+
+	// the grammar of the new instance should be bound to the transaction
+	tx := qb.WithTransaction(sqltx)
+	sqltx.MustExec("CREATE TABLE table_test_transaction_tx (id INTEGER)")
+	has, err := tx.Builder().Grammar.TableExists("table_test_transaction_tx")
+	assert.Nil(t, err, "the return error should be nil")
+	assert.True(t, has, "the table should be visible within the transaction")
+
+	tx.MustRollback()
+	has, err = tx.Builder().Grammar.TableExists("table_test_transaction_tx")
+	assert.Nil(t, err, "the grammar should be unbound when the transaction is finished")
+	assert.False(t, has, "the table should be rolled back")
+}
+
+// clean the test data
+func TestTransactionClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_transaction")
+}
+
+func NewTableForTransactionTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_transaction")
+	builder.MustCreateTable("table_test_transaction", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.Integer("vote")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_transaction").Insert([]xun.R{
+		{"email": "john@yao.run", "vote": 10},
+		{"email": "lee@yao.run", "vote": 5},
+	})
+}
@@ -1,6 +1,9 @@
 package query
 
 import (
+	"context"
+	"database/sql"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun/dbal"
 )
@@ -13,6 +16,31 @@ type Builder struct {
 	Database string
 	Schema   string
 	Grammar  dbal.Grammar
+	Tx       *Transaction
+	Context  context.Context
+}
+
+// Transaction the database transaction of the query builder
+type Transaction struct {
+	Tx    *sqlx.Tx
+	Level int
+}
+
+// Cursor the lazy iterator of the query results, it reads one row at a time from the database.
+type Cursor struct {
+	builder *Builder
+	stmt    *sql.Stmt
+	rows    *sql.Rows
+	columns []string
+	err     error
+	closed  bool
+}
+
+// cursorPayload the payload of the cursor of the cursor paginator
+type cursorPayload struct {
+	Values map[string]interface{} `json:"values"`
+	Types  map[string]string      `json:"types,omitempty"` // the types of the values which are not JSON types. eg: time
+	Next   bool                   `json:"next"`
 }
 
 // Connection DB Connection
@@ -2,6 +2,8 @@ package query
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 
 	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/xun"
@@ -13,16 +15,26 @@ import (
 func (builder *Builder) Update(v interface{}) (int64, error) {
 
 	values := xun.MakeR(v).ToMap()
+	err := builder.Grammar.CheckQuery(builder.Query)
+	if err != nil {
+		return 0, err
+	}
+
+	err = checkJSONUpdateColumns(values)
+	if err != nil {
+		return 0, err
+	}
+
 	sql, bindings := builder.Grammar.CompileUpdate(builder.Query, values)
 	defer log.With(log.F{"bindings": bindings}).Debug(sql)
 
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
+	stmt, err := builder.executor(true).PrepareContext(builder.GetContext(), sql)
 	if err != nil {
 		return 0, err
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(bindings...)
+	res, err := stmt.ExecContext(builder.GetContext(), bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -37,6 +49,30 @@ func (builder *Builder) MustUpdate(v interface{}) int64 {
 	return affected
 }
 
+// UpdateReturning Update records in the database and get the rows returned by the "returning" clause. (PostgreSQL, SQLite 3.35.0+)
+func (builder *Builder) UpdateReturning(v interface{}) ([]xun.R, error) {
+	values := xun.MakeR(v).ToMap()
+	err := builder.Grammar.CheckQuery(builder.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkJSONUpdateColumns(values)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, bindings := builder.Grammar.CompileUpdate(builder.Query, values)
+	return builder.execReturning(sql, bindings)
+}
+
+// MustUpdateReturning Update records in the database and get the rows returned by the "returning" clause.
+func (builder *Builder) MustUpdateReturning(v interface{}) []xun.R {
+	rows, err := builder.UpdateReturning(v)
+	utils.PanicIF(err)
+	return rows
+}
+
 // UpdateOrInsert Insert or update a record matching the attributes, and fill it with values.
 func (builder *Builder) UpdateOrInsert(attributes interface{}, values ...interface{}) (bool, error) {
 
@@ -76,29 +112,97 @@ func (builder *Builder) MustUpdateOrInsert(attributes interface{}, values ...int
 }
 
 // Upsert new records or update the existing ones.
+// The update values are bound in every statement, so they are reserved from the placeholder limit of each batch.
 func (builder *Builder) Upsert(v interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) (int64, error) {
+	columns, values := builder.prepareInsertValues(v, columns...)
+
+	// the update values take the placeholders of each statement
+	reserved := 0
+	if reflect.ValueOf(update).Kind() == reflect.Map {
+		reserved = reflect.ValueOf(update).Len()
+		err := checkJSONUpdateColumns(xun.MakeR(update).ToMap())
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	chunks := builder.chunkValues(values, len(columns), reserved)
+	return builder.execChunks(chunks, func(qb *Builder, values [][]interface{}) (int64, error) {
+		sql, bindings := qb.Grammar.CompileUpsert(qb.Query, columns, values, utils.Flatten(uniqueBy), update)
+		return qb.execAffected(sql, bindings)
+	})
+}
+
+// MustUpsert new records or update the existing ones.
+func (builder *Builder) MustUpsert(values interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) int64 {
+	affected, err := builder.Upsert(values, uniqueBy, update, columns...)
+	utils.PanicIF(err)
+	return affected
+}
 
+// UpsertReturning Upsert new records or update the existing ones, and get the rows returned by the "returning" clause. (PostgreSQL, SQLite 3.35.0+)
+func (builder *Builder) UpsertReturning(v interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) ([]xun.R, error) {
 	columns, values := builder.prepareInsertValues(v, columns...)
 	sql, bindings := builder.Grammar.CompileUpsert(builder.Query, columns, values, utils.Flatten(uniqueBy), update)
-	defer log.With(log.F{"bindings": bindings}).Debug(sql)
+	return builder.execReturning(sql, bindings)
+}
 
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
-	if err != nil {
-		return 0, err
+// MustUpsertReturning Upsert new records or update the existing ones, and get the rows returned by the "returning" clause.
+func (builder *Builder) MustUpsertReturning(v interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) []xun.R {
+	rows, err := builder.UpsertReturning(v, uniqueBy, update, columns...)
+	utils.PanicIF(err)
+	return rows
+}
+
+// UpdateBatch Update many records with different values, the records are matched by the given key column.
+// The rows are split into chunks to stay under the placeholder limit of the database, and the chunks are executed within a transaction.
+// UpdateBatch([]xun.R{{"id": 1, "vote": 10}, {"id": 2, "vote": 20}}, "id")
+func (builder *Builder) UpdateBatch(v interface{}, key string) (int64, error) {
+	rows := xun.MakeRows(v)
+	if len(rows) == 0 {
+		return 0, nil
 	}
-	defer stmt.Close()
 
-	res, err := stmt.Exec(bindings...)
-	if err != nil {
-		return 0, err
+	keys := rows[0].KeysString()
+	sort.Strings(keys)
+	columns := []interface{}{}
+	for _, column := range keys {
+		columns = append(columns, column)
 	}
 
-	return res.RowsAffected()
+	if _, has := rows[0][key]; !has {
+		return 0, fmt.Errorf("the key column %s is required", key)
+	} else if len(columns) < 2 {
+		return 0, fmt.Errorf("there are no columns to update")
+	}
+
+	values := [][]interface{}{}
+	for _, row := range rows {
+		if len(row) != len(keys) {
+			return 0, fmt.Errorf("the rows should have the same columns")
+		}
+		value := []interface{}{}
+		for _, column := range keys {
+			cell, has := row[column]
+			if !has {
+				return 0, fmt.Errorf("the rows should have the same columns, %s is missing", column)
+			}
+			value = append(value, cell)
+		}
+		values = append(values, value)
+	}
+
+	// each row takes two placeholders for every updated column and one for the key
+	chunks := builder.chunkValues(values, 2*len(columns)-1, len(builder.Query.GetBindings("where")))
+	return builder.execChunks(chunks, func(qb *Builder, values [][]interface{}) (int64, error) {
+		sql, bindings := qb.Grammar.CompileUpdateBatch(qb.Query, key, columns, values)
+		return qb.execAffected(sql, bindings)
+	})
 }
 
-// MustUpsert new records or update the existing ones.
-func (builder *Builder) MustUpsert(values interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) int64 {
-	affected, err := builder.Upsert(values, uniqueBy, update, columns...)
+// MustUpdateBatch Update many records with different values, the records are matched by the given key column.
+func (builder *Builder) MustUpdateBatch(v interface{}, key string) int64 {
+	affected, err := builder.UpdateBatch(v, key)
 	utils.PanicIF(err)
 	return affected
 }
@@ -144,3 +248,17 @@ func (builder *Builder) MustDecrement(column interface{}, amount interface{}, ex
 	utils.PanicIF(err)
 	return affected
 }
+
+// checkJSONUpdateColumns Check that a column is not updated both as a whole and by its JSON selectors. eg: options, options->enabled
+func checkJSONUpdateColumns(values map[string]interface{}) error {
+	for key := range values {
+		if !dbal.IsJSONSelector(key) {
+			continue
+		}
+		column, _, _ := dbal.ParseJSONSelector(key)
+		if _, has := values[column]; has {
+			return fmt.Errorf("the column %s can not be updated with its JSON selector %s at the same time", column, key)
+		}
+	}
+	return nil
+}
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/blang/semver/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/grammar/sqlite3"
 	"github.com/yaoapp/xun/unit"
 )
 
@@ -89,6 +91,22 @@ func TestUpdateMustUpsertUpdateRaw(t *testing.T) {
 	}
 }
 
+func TestUpdateMustUpsertChunk(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+
+	// 14000 rows with 5 columns take 70000 placeholders
+	values := []xun.R{{"email": "john@yao.run", "name": "John", "vote": 99, "score": 96.32, "score_grade": 99.27}}
+	for i := 1; i < 14000; i++ {
+		values = append(values, xun.R{"email": fmt.Sprintf("user%d@yao.run", i), "name": "User", "vote": i, "score": 60.00, "score_grade": 60.00})
+	}
+	qb.Table("table_test_update").MustUpsert(values, "email", []string{"vote"})
+
+	assert.Equal(t, int64(14003), getTestBuilder().Table("table_test_update").MustCount(), "The rows count should be 14003")
+	row := getTestBuilder().Table("table_test_update").Where("email", "john@yao.run").MustFirst()
+	assert.Equal(t, int64(99), row.Get("vote").(int64), "The vote of john should be 99")
+}
+
 func TestUpdateMustUpdate(t *testing.T) {
 	NewTableForUpdateTest()
 	qb := getTestBuilder()
@@ -135,6 +153,68 @@ func TestUpdateMustUpdateWithJoin(t *testing.T) {
 	assert.Equal(t, int64(2), affected, "The affected rows should be 2")
 }
 
+func TestUpdateMustUpdateWithInnerJoin(t *testing.T) {
+	NewTableForUpdateTest()
+	NewTableForUpdateVoteTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_update").
+		Join("table_test_update_vote", "table_test_update_vote.user_id", "=", "table_test_update.id").
+		Where("table_test_update_vote.points", ">", 50)
+
+	// checking sql
+	sql, _ := qb.Builder().Grammar.CompileUpdate(qb.Builder().Query.Clone(), map[string]interface{}{"vote": dbal.Raw("table_test_update_vote.points")})
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `update "table_test_update" set "vote"=table_test_update_vote.points from "table_test_update_vote" where ("table_test_update_vote"."user_id" = "table_test_update"."id") and ("table_test_update_vote"."points" > $1)`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "update `table_test_update` set `vote`=table_test_update_vote.points from `table_test_update_vote` where (`table_test_update_vote`.`user_id` = `table_test_update`.`id`) and (`table_test_update_vote`.`points` > ?)", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "update `table_test_update` inner join `table_test_update_vote` on `table_test_update_vote`.`user_id` = `table_test_update`.`id` set `vote`=table_test_update_vote.points where `table_test_update_vote`.`points` > ?", sql, "the query sql not equal")
+	}
+
+	affected := qb.MustUpdate(xun.R{"vote": dbal.Raw("table_test_update_vote.points")})
+	assert.Equal(t, int64(2), affected, "The affected rows should be 2")
+
+	rows := getTestBuilder().Table("table_test_update").OrderBy("id").MustGet()
+	assert.Equal(t, 4, len(rows), "The return value should be have 4 rows")
+	if len(rows) == 4 {
+		assert.Equal(t, int64(100), rows[0]["vote"].(int64), "The vote of the 1st row should be 100")
+		assert.Equal(t, int64(5), rows[1]["vote"].(int64), "The vote of the 2nd row should be 5")
+		assert.Equal(t, int64(200), rows[2]["vote"].(int64), "The vote of the 3rd row should be 200")
+	}
+}
+
+func TestUpdateSQLiteUpdateFromVersion(t *testing.T) {
+	grammar := dbal.Grammars["sqlite3"].(sqlite3.SQLite3)
+	qb := getTestBuilder().New()
+	qb.Table("table_test_update as u").
+		Join("table_test_update_vote", "table_test_update_vote.user_id", "=", "u.id").
+		Where("table_test_update_vote.points", ">", 50)
+	values := map[string]interface{}{"vote": dbal.Raw("table_test_update_vote.points")}
+
+	grammar.Version = &dbal.Version{Version: semver.MustParse("3.33.0"), Driver: "sqlite3"}
+	sql, _ := grammar.CompileUpdate(qb.Builder().Query.Clone(), values)
+	assert.Equal(t, "update `table_test_update` as `u` set `vote`=table_test_update_vote.points from `table_test_update_vote` where (`table_test_update_vote`.`user_id` = `u`.`id`) and (`table_test_update_vote`.`points` > ?)", sql, "the query sql not equal")
+
+	// the joins are rewritten into the subquery of the row ids before SQLite 3.33.0
+	grammar.Version = &dbal.Version{Version: semver.MustParse("3.32.3"), Driver: "sqlite3"}
+	sql, _ = grammar.CompileUpdate(qb.Builder().Query.Clone(), values)
+	assert.Equal(t, "update `table_test_update` as `u` set `vote`=table_test_update_vote.points where `rowid` in (select `u`.`rowid` from `table_test_update` as `u` inner join `table_test_update_vote` on `table_test_update_vote`.`user_id` = `u`.`id` where `table_test_update_vote`.`points` > ?)", sql, "the query sql not equal")
+}
+
+func TestUpdateMustUpdateWithLeftJoin(t *testing.T) {
+	NewTableForUpdateTest()
+	NewTableForUpdateVoteTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_update").
+		LeftJoin("table_test_update_vote", "table_test_update_vote.user_id", "=", "table_test_update.id").
+		WhereNull("table_test_update_vote.id").
+		MustUpdate(xun.R{"status": "PENDING"})
+
+	assert.Equal(t, int64(1), affected, "The affected rows should be 1")
+	row := getTestBuilder().Table("table_test_update").Where("id", 4).MustFirst()
+	assert.Equal(t, "PENDING", row.Get("status").(string), "The status of the 4th row should be PENDING")
+}
+
 func TestUpdateMustIncrement(t *testing.T) {
 	NewTableForUpdateTest()
 	qb := getTestBuilder()
@@ -284,10 +364,200 @@ func TestUpdateMustUpdateOrInsertUpdateScore(t *testing.T) {
 	}
 }
 
+func TestUpdateMustUpdateJSON(t *testing.T) {
+	NewTableForUpdateJSONTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_update_json").Where("email", "john@yao.run")
+	values := map[string]interface{}{
+		"name":                  "John Doe",
+		"options->enabled":      true,
+		"options->dining->meal": "pizza",
+		"options->languages":    []string{"en", "fr"},
+	}
+
+	// checking sql
+	sql, bindings := qb.Builder().Grammar.CompileUpdate(qb.Builder().Query, values)
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `update "table_test_update_json" set "name"=$1, "options"=jsonb_set(jsonb_set(jsonb_set(coalesce("options", '{}')::jsonb, '{"dining","meal"}', $2), '{"enabled"}', $3), '{"languages"}', $4) where "email" = $5`, sql, "the query sql not equal")
+		assert.Equal(t, []interface{}{"John Doe", `"pizza"`, `true`, `["en","fr"]`, "john@yao.run"}, bindings, "the bindings should be equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "update `table_test_update_json` set `name`=?, `options`=json_set(ifnull(`options`, json('{}')), '$.\"dining\".\"meal\"', ?, '$.\"enabled\"', json('true'), '$.\"languages\"', json(?)) where `email` = ?", sql, "the query sql not equal")
+		assert.Equal(t, []interface{}{"John Doe", "pizza", `["en","fr"]`, "john@yao.run"}, bindings, "the bindings should be equal")
+	} else {
+		assert.Equal(t, "update `table_test_update_json` set `name`=?, `options`=json_set(ifnull(`options`, json_object()), '$.\"dining\".\"meal\"', ?, '$.\"enabled\"', true, '$.\"languages\"', cast(? as json)) where `email` = ?", sql, "the query sql not equal")
+		assert.Equal(t, []interface{}{"John Doe", "pizza", `["en","fr"]`, "john@yao.run"}, bindings, "the bindings should be equal")
+	}
+
+	// checking result
+	affected := qb.MustUpdate(values)
+	assert.Equal(t, int64(1), affected, "The affected rows should be 1")
+
+	row := getTestBuilder().Table("table_test_update_json").
+		Select("name", "options->dining->>meal as meal").
+		Where("email", "john@yao.run").
+		Where("options->enabled", true).
+		WhereJSONContains("options->languages", "fr").
+		MustFirst()
+	assert.Equal(t, "John Doe", row.Get("name"), "the name should be John Doe")
+	assert.Equal(t, "pizza", row.Get("meal"), "the meal should be pizza")
+}
+
+func TestUpdateMustUpdateJSONNull(t *testing.T) {
+	NewTableForUpdateJSONTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_update_json").
+		Where("email", "lee@yao.run").
+		MustUpdate(xun.R{"options->enabled": false})
+	assert.Equal(t, int64(1), affected, "The affected rows should be 1")
+
+	exists := getTestBuilder().Table("table_test_update_json").
+		Where("email", "lee@yao.run").
+		Where("options->enabled", false).
+		MustExists()
+	assert.True(t, exists, "the options of lee should be set")
+}
+
+func TestUpdateJSONConflict(t *testing.T) {
+	NewTableForUpdateJSONTest()
+	values := xun.R{"options": `{"enabled":true}`, "options->dining->meal": "pizza"}
+	_, err := getTestBuilder().Table("table_test_update_json").
+		Where("email", "john@yao.run").
+		Update(values)
+	assert.Error(t, err, "the return error should not be nil")
+	if err != nil {
+		assert.Contains(t, err.Error(), "options", "the error should contain the column name")
+	}
+
+	_, err = getTestBuilder().Table("table_test_update_json").
+		Upsert([]xun.R{{"email": "john@yao.run", "name": "John"}}, []string{"email"}, values)
+	assert.Error(t, err, "the return error should not be nil")
+
+	row := getTestBuilder().Table("table_test_update_json").
+		Select("options->dining->>meal as meal").
+		Where("email", "john@yao.run").
+		MustFirst()
+	assert.Equal(t, "salad", fmt.Sprintf("%s", row.Get("meal")), "the options of john should not be changed")
+}
+
+func TestUpdateMustUpsertJSON(t *testing.T) {
+	NewTableForUpdateJSONTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_update_json").MustUpsert([]xun.R{
+		{"email": "john@yao.run", "name": "John"},
+		{"email": "max@yao.run", "name": "Max"},
+	}, []string{"email"}, xun.R{"options->dining->meal": "soup"})
+
+	assert.Equal(t, int64(3), getTestBuilder().Table("table_test_update_json").MustCount(), "The rows count should be 3")
+	row := getTestBuilder().Table("table_test_update_json").
+		Select("options->dining->>meal as meal").
+		Where("email", "john@yao.run").
+		MustFirst()
+	assert.Equal(t, "soup", row.Get("meal"), "the meal of john should be soup")
+}
+
+func TestUpdateMustUpdateBatch(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_update").
+		MustUpdateBatch([]xun.R{
+			{"id": 1, "vote": 11, "name": "John Doe"},
+			{"id": 2, "vote": 12, "name": "Lee Doe"},
+			{"id": 3, "vote": 13, "name": dbal.Raw("'Ken Doe'")},
+		}, "id")
+
+	assert.Equal(t, int64(3), affected, "The affected rows should be 3")
+	rows := getTestBuilder().Table("table_test_update").OrderBy("id").MustGet()
+	assert.Equal(t, 4, len(rows), "The return value should be have 4 rows")
+	if len(rows) == 4 {
+		assert.Equal(t, "John Doe", rows[0]["name"].(string), "The name of the 1st row should be John Doe")
+		assert.Equal(t, int64(12), rows[1]["vote"].(int64), "The vote of the 2nd row should be 12")
+		assert.Equal(t, "Ken Doe", rows[2]["name"].(string), "The name of the 3rd row should be Ken Doe")
+		assert.Equal(t, int64(6), rows[3]["vote"].(int64), "The vote of the 4th row should be 6")
+	}
+}
+
+func TestUpdateMustUpdateBatchWithWhere(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_update").
+		Where("status", "DONE").
+		MustUpdateBatch([]xun.R{
+			{"id": 2, "vote": 12},
+			{"id": 3, "vote": 13},
+			{"id": 4, "vote": 14},
+		}, "id")
+
+	assert.Equal(t, int64(2), affected, "The affected rows should be 2")
+	rows := getTestBuilder().Table("table_test_update").OrderBy("id").MustGet()
+	if len(rows) == 4 {
+		assert.Equal(t, int64(5), rows[1]["vote"].(int64), "The vote of the 2nd row should be 5")
+		assert.Equal(t, int64(13), rows[2]["vote"].(int64), "The vote of the 3rd row should be 13")
+		assert.Equal(t, int64(14), rows[3]["vote"].(int64), "The vote of the 4th row should be 14")
+	}
+}
+
+func TestUpdateUpdateBatchGrammarWhere(t *testing.T) {
+	qb := getTestBuilder().New()
+	qb.Table("table_test_update").WhereDate("created_at", "2021-05-01")
+	columns := []interface{}{"id", "vote"}
+	values := [][]interface{}{{1, 11}, {2, 12}}
+
+	// the where clauses should be compiled by the grammar of each database
+	sql, bindings := dbal.Grammars["sqlite3"].CompileUpdateBatch(qb.(*Builder).Query, "id", columns, values)
+	assert.Equal(t, "update `table_test_update` set `vote`=case when `id`=? then ? when `id`=? then ? else `vote` end where `id` in (?,?) and (strftime('%Y-%m-%d',`created_at`) = cast(? as text))", sql, "the query sql not equal")
+	assert.Equal(t, []interface{}{1, 11, 2, 12, 1, 2, "2021-05-01"}, bindings, "the bindings should be the keys, the values and the date")
+
+	sql, _ = dbal.Grammars["mysql"].CompileUpdateBatch(qb.(*Builder).Query, "id", columns, values)
+	assert.Equal(t, "update `table_test_update` set `vote`=case when `id`=? then ? when `id`=? then ? else `vote` end where `id` in (?,?) and (date(`created_at`)=?)", sql, "the query sql not equal")
+}
+
+func TestUpdateMustUpdateBatchChunk(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	for chunk := 0; chunk < 8; chunk++ {
+		values := []xun.R{}
+		for i := 0; i < 1000; i++ {
+			values = append(values, xun.R{"email": fmt.Sprintf("user%d-%d@yao.run", chunk, i), "name": "User", "vote": 0, "score": 60.00, "score_grade": 60.00})
+		}
+		qb.Table("table_test_update").MustInsert(values)
+	}
+
+	// 8000 rows with 3 columns take 40000 placeholders
+	updates := []xun.R{}
+	for i := 5; i <= 8004; i++ {
+		updates = append(updates, xun.R{"id": i, "vote": i, "name": fmt.Sprintf("User %d", i)})
+	}
+	affected := qb.Table("table_test_update").MustUpdateBatch(updates, "id")
+	assert.Equal(t, int64(8000), affected, "The affected rows should be 8000")
+
+	row := getTestBuilder().Table("table_test_update").Where("id", 8004).MustFirst()
+	assert.Equal(t, int64(8004), row.Get("vote").(int64), "The vote of the last row should be 8004")
+	assert.Equal(t, "User 8004", row.Get("name").(string), "The name of the last row should be User 8004")
+}
+
+func TestUpdateMustUpdateBatchError(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	_, err := qb.Table("table_test_update").UpdateBatch([]xun.R{{"vote": 1}}, "id")
+	assert.NotNil(t, err, "The key column should be required")
+
+	_, err = qb.Table("table_test_update").UpdateBatch([]xun.R{{"id": 1}}, "id")
+	assert.NotNil(t, err, "The update columns should be required")
+
+	_, err = qb.Table("table_test_update").UpdateBatch([]xun.R{{"id": 1, "vote": 1}, {"id": 2, "name": "Lee"}}, "id")
+	assert.NotNil(t, err, "The rows should have the same columns")
+
+	assert.Panics(t, func() {
+		qb.Table("table_test_update").MustUpdateBatch([]xun.R{{"vote": 1}}, "id")
+	})
+}
+
 // clean the test data
 func TestUpdateClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
 	builder.DropTableIfExists("table_test_update")
+	builder.DropTableIfExists("table_test_update_json")
+	builder.DropTableIfExists("table_test_update_vote")
 }
 
 func NewTableForUpdateTest() {
@@ -314,3 +584,39 @@ func NewTableForUpdateTest() {
 		{"email": "ben@yao.run", "name": "Ben", "vote": 6, "score": 48.12, "score_grade": 99.27, "status": "DONE", "created_at": "2021-03-25 18:15:29"},
 	})
 }
+
+func NewTableForUpdateJSONTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_update_json")
+	builder.MustCreateTable("table_test_update_json", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.String("name").Null()
+		table.JSON("options").Null()
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_update_json").Insert([]xun.R{
+		{"email": "john@yao.run", "name": "John", "options": `{"dining":{"meal":"salad"},"enabled":false,"languages":["en"]}`},
+		{"email": "lee@yao.run", "name": "Lee", "options": nil},
+	})
+}
+
+func NewTableForUpdateVoteTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_update_vote")
+	builder.MustCreateTable("table_test_update_vote", func(table schema.Blueprint) {
+		table.ID("id")
+		table.BigInteger("user_id")
+		table.Integer("points")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_update_vote").Insert([]xun.R{
+		{"user_id": 1, "points": 100},
+		{"user_id": 2, "points": 20},
+		{"user_id": 3, "points": 200},
+	})
+}
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 
@@ -119,7 +120,10 @@ func (builder *Builder) where(column interface{}, operator string, value interfa
 	// If the column is making a JSON reference we'll check to see if the value
 	// is a boolean. If it is, we'll add the raw boolean string as an actual
 	// value to the query to ensure this is properly handled by the query.
-	//  if (Str::contains($column, '->') && is_bool($value)) {
+	if isBool, ok := value.(bool); ok && dbal.IsJSONSelector(column) {
+		queryType = "JSONBoolean"
+		value = dbal.Raw(fmt.Sprintf("%v", isBool))
+	}
 
 	// Where("email", "like", "%@yao.run")
 	// Now that we are working with just a simple query we can put the elements
@@ -645,25 +649,113 @@ func (builder *Builder) Unless(value bool, callback func(qb Query, value bool),
 }
 
 // WhereJSONContains Add a "where JSON contains" clause to the query.
-func (builder *Builder) WhereJSONContains() {
+func (builder *Builder) WhereJSONContains(column string, value interface{}) Query {
+	return builder.whereJSONContains(column, value, "and", false)
 }
 
 // OrWhereJSONContains Add an "or where JSON contains" clause to the query.
-func (builder *Builder) OrWhereJSONContains() {
+func (builder *Builder) OrWhereJSONContains(column string, value interface{}) Query {
+	return builder.whereJSONContains(column, value, "or", false)
 }
 
 // WhereJSONDoesntContain Add a "where JSON not contains" clause to the query.
-func (builder *Builder) WhereJSONDoesntContain() {
+func (builder *Builder) WhereJSONDoesntContain(column string, value interface{}) Query {
+	return builder.whereJSONContains(column, value, "and", true)
 }
 
 // OrWhereJSONDoesntContain Add an "or where JSON not contains" clause to the query.
-func (builder *Builder) OrWhereJSONDoesntContain() {
+func (builder *Builder) OrWhereJSONDoesntContain(column string, value interface{}) Query {
+	return builder.whereJSONContains(column, value, "or", true)
+}
+
+// whereJSONContains Add a "where JSON contains" clause to the query.
+func (builder *Builder) whereJSONContains(column string, value interface{}, boolean string, not bool) Query {
+
+	// The value will be bound as a JSON document unless it is an expression.
+	// eg: "en" => `"en"`,  []string{"en", "de"} => `["en","de"]`
+	if !builder.isExpression(value) {
+		bytes, err := json.Marshal(value)
+		utils.PanicIF(err)
+		value = string(bytes)
+	}
+
+	builder.Query.Wheres = append(builder.Query.Wheres, dbal.Where{
+		Type:    "JSONContains",
+		Column:  column,
+		Value:   value,
+		Boolean: boolean,
+		Not:     not,
+		Offset:  1,
+	})
+
+	if !builder.isExpression(value) {
+		builder.Query.AddBinding("where", value)
+	}
+	return builder
+}
+
+// WhereFullText Add a "where fulltext" clause to the query.
+// WhereFullText("body", "xun")
+// WhereFullText("title,body", "+xun -yao", map[string]interface{}{"mode": "boolean"})
+// WhereFullText([]string{"title", "body"}, "query builder", map[string]interface{}{"mode": "websearch", "language": "english"})
+func (builder *Builder) WhereFullText(columns interface{}, value string, options ...map[string]interface{}) Query {
+	return builder.whereFullText(columns, value, "and", options...)
+}
+
+// OrWhereFullText Add an "or where fulltext" clause to the query.
+func (builder *Builder) OrWhereFullText(columns interface{}, value string, options ...map[string]interface{}) Query {
+	return builder.whereFullText(columns, value, "or", options...)
+}
+
+// whereFullText Add a "where fulltext" clause to the query.
+func (builder *Builder) whereFullText(columns interface{}, value string, boolean string, options ...map[string]interface{}) Query {
+	builder.Query.Wheres = append(builder.Query.Wheres, dbal.Where{
+		Type:    "fullText",
+		Column:  builder.makeFullText(columns, options...),
+		Value:   value,
+		Boolean: boolean,
+		Offset:  1,
+	})
+	builder.Query.AddBinding("where", value)
+	return builder
+}
+
+// makeFullText make a new full text search
+func (builder *Builder) makeFullText(columns interface{}, options ...map[string]interface{}) dbal.FullText {
+	fulltext := dbal.FullText{
+		Columns: builder.prepareColumns(columns),
+		Options: map[string]interface{}{},
+	}
+	if len(options) > 0 && options[0] != nil {
+		fulltext.Options = options[0]
+	}
+	return fulltext
 }
 
 // WhereJSONLength Add a "where JSON length" clause to the query.
-func (builder *Builder) WhereJSONLength() {
+func (builder *Builder) WhereJSONLength(column string, args ...interface{}) Query {
+	operator, value, boolean, _ := builder.prepareWhereArgs(args...)
+	if builder.invalidOperator(operator) {
+		operator = "="
+	}
+
+	builder.Query.Wheres = append(builder.Query.Wheres, dbal.Where{
+		Type:     "JSONLength",
+		Column:   column,
+		Operator: operator,
+		Value:    value,
+		Boolean:  boolean,
+		Offset:   1,
+	})
+
+	if !builder.isExpression(value) {
+		builder.Query.AddBinding("where", value)
+	}
+	return builder
 }
 
 // OrWhereJSONLength Add an "or where JSON length" clause to the query.
-func (builder *Builder) OrWhereJSONLength() {
+func (builder *Builder) OrWhereJSONLength(column string, args ...interface{}) Query {
+	operator, value, _, _ := builder.prepareWhereArgs(args...)
+	return builder.WhereJSONLength(column, operator, value, "or")
 }
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -1519,10 +1520,369 @@ func TestWhereUnlessValueIsFalseWidthDefaults(t *testing.T) {
 	checkVoteGT(t, qb)
 }
 
+func TestWhereWhereJSONSelector(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_json").
+		Where("options->dining->>meal", "salad").
+		OrderBy("id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_where_json" where "options"->'dining'->>'meal' = $1 order by "id" asc`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select * from `table_test_where_json` where json_extract(`options`, '$.\"dining\".\"meal\"') = ? order by `id` asc", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_where_json` where json_unquote(json_extract(`options`, '$.\"dining\".\"meal\"')) = ? order by `id` asc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, "john@yao.run", rows[0]["email"].(string), "the email of the 1st row should be john@yao.run")
+		assert.Equal(t, "ken@yao.run", rows[1]["email"].(string), "the email of the 2nd row should be ken@yao.run")
+	}
+}
+
+func TestWhereWhereJSONSelectorOperator(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_json").
+		Select("options->dining as dining", "options->dining->>meal as meal").
+		Where("email", "ken@yao.run")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "options"->'dining' as "dining", "options"->'dining'->>'meal' as "meal" from "table_test_where_json" where "email" = $1`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select json_quote(json_extract(`options`, '$.\"dining\"')) as `dining`, json_extract(`options`, '$.\"dining\".\"meal\"') as `meal` from `table_test_where_json` where `email` = ?", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select json_extract(`options`, '$.\"dining\"') as `dining`, json_unquote(json_extract(`options`, '$.\"dining\".\"meal\"')) as `meal` from `table_test_where_json` where `email` = ?", sql, "the query sql not equal")
+	}
+
+	// checking result
+	row := qb.MustFirst()
+	assert.Contains(t, fmt.Sprintf("%s", row.Get("dining")), `"meal"`, "the dining should be a JSON document")
+	assert.Equal(t, "salad", fmt.Sprintf("%s", row.Get("meal")), "the meal should be the unquoted text")
+}
+
+func TestWhereWhereJSONSelectorArrayIndex(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_json").
+		Select("email", "options->>languages[0] as language").
+		Where("options->>languages[0]", "de")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "email", "options"->'languages'->>0 as "language" from "table_test_where_json" where "options"->'languages'->>0 = $1`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select `email`, json_extract(`options`, '$.\"languages\"[0]') as `language` from `table_test_where_json` where json_extract(`options`, '$.\"languages\"[0]') = ?", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `email`, json_unquote(json_extract(`options`, '$.\"languages\"[0]')) as `language` from `table_test_where_json` where json_unquote(json_extract(`options`, '$.\"languages\"[0]')) = ?", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should be have 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, "ken@yao.run", rows[0]["email"].(string), "the email of the 1st row should be ken@yao.run")
+		assert.Equal(t, "de", rows[0]["language"].(string), "the language of the 1st row should be de")
+	}
+}
+
+func TestWhereWhereJSONBoolean(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_json").
+		Where("options->enabled", true)
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_where_json" where ("options"->'enabled')::jsonb = 'true'::jsonb`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_where_json` where json_extract(`options`, '$.\"enabled\"') = true", sql, "the query sql not equal")
+	}
+	assert.Equal(t, 0, len(qb.GetBindings()), "the bindings should be empty")
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should be have 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, "john@yao.run", rows[0]["email"].(string), "the email of the 1st row should be john@yao.run")
+	}
+}
+
+func TestWhereWhereJSONContains(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_json").
+		Where("id", ">", 0).
+		WhereJSONContains("options->languages", []string{"en", "de"})
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_where_json" where "id" > $1 and ("options"->'languages')::jsonb @> $2`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select * from `table_test_where_json` where `id` > ? and not exists (select 1 from json_each(?) as `contains` where `contains`.`value` not in (select `value` from json_each(`options`, '$.\"languages\"')))", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_where_json` where `id` > ? and json_contains(`options`, ?, '$.\"languages\"')", sql, "the query sql not equal")
+	}
+	assert.Equal(t, []interface{}{0, `["en","de"]`}, qb.GetBindings(), "the bindings should be equal")
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should be have 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, "ken@yao.run", rows[0]["email"].(string), "the email of the 1st row should be ken@yao.run")
+	}
+}
+
+func TestWhereOrWhereJSONContains(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_json").
+		WhereJSONContains("options->languages", "de").
+		OrWhereJSONContains("options->languages", "fr").
+		OrderBy("id")
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, "lee@yao.run", rows[0]["email"].(string), "the email of the 1st row should be lee@yao.run")
+		assert.Equal(t, "ken@yao.run", rows[1]["email"].(string), "the email of the 2nd row should be ken@yao.run")
+	}
+}
+
+func TestWhereWhereJSONDoesntContain(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_json").
+		WhereJSONDoesntContain("options->languages", "en").
+		OrWhereJSONDoesntContain("options->languages", "de").
+		OrderBy("id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_where_json" where not ("options"->'languages')::jsonb @> $1 or not ("options"->'languages')::jsonb @> $2 order by "id" asc`, sql, "the query sql not equal")
+	} else if unit.DriverIs("mysql") {
+		assert.Equal(t, "select * from `table_test_where_json` where not json_contains(`options`, ?, '$.\"languages\"') or not json_contains(`options`, ?, '$.\"languages\"') order by `id` asc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, "john@yao.run", rows[0]["email"].(string), "the email of the 1st row should be john@yao.run")
+		assert.Equal(t, "lee@yao.run", rows[1]["email"].(string), "the email of the 2nd row should be lee@yao.run")
+	}
+}
+
+func TestWhereWhereJSONLength(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_json").
+		WhereJSONLength("options->languages", ">", 1)
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_where_json" where jsonb_array_length(("options"->'languages')::jsonb) > $1`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select * from `table_test_where_json` where json_array_length(`options`, '$.\"languages\"') > ?", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_where_json` where json_length(`options`, '$.\"languages\"') > ?", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should be have 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, "ken@yao.run", rows[0]["email"].(string), "the email of the 1st row should be ken@yao.run")
+	}
+}
+
+func TestWhereOrWhereJSONLength(t *testing.T) {
+	NewTableForWhereJSONTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_json").
+		WhereJSONLength("options->languages", 0).
+		OrWhereJSONLength("options->languages", ">", 1).
+		OrderBy("id")
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, "john@yao.run", rows[0]["email"].(string), "the email of the 1st row should be john@yao.run")
+		assert.Equal(t, "ken@yao.run", rows[1]["email"].(string), "the email of the 2nd row should be ken@yao.run")
+	}
+}
+
+func TestWhereWhereFullText(t *testing.T) {
+	NewTableForWhereFullTextTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_fulltext").
+		WhereFullText("title,body", "database")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_where_fulltext" where (to_tsvector('english', "title") || to_tsvector('english', "body")) @@ plainto_tsquery('english', $1)`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select * from `table_test_where_fulltext` where `table_test_where_fulltext` match '{title body} : (' || ? || ')'", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_where_fulltext` where match (`title`, `body`) against (? in natural language mode)", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+}
+
+func TestWhereWhereFullTextBoolean(t *testing.T) {
+	NewTableForWhereFullTextTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_fulltext as t").
+		WhereFullText([]string{"t.title", "t.body"}, "runtime", map[string]interface{}{"mode": "boolean"})
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_where_fulltext" as "t" where (to_tsvector('english', "t"."title") || to_tsvector('english', "t"."body")) @@ to_tsquery('english', $1)`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select * from `table_test_where_fulltext` as `t` where `t`.`table_test_where_fulltext` match '{title body} : (' || ? || ')'", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_where_fulltext` as `t` where match (`t`.`title`, `t`.`body`) against (? in boolean mode)", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should be have 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, "Gou runtime", rows[0]["title"].(string), "the title of the 1st row should be Gou runtime")
+	}
+}
+
+func TestWhereOrWhereFullText(t *testing.T) {
+	NewTableForWhereFullTextTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_fulltext").
+		WhereFullText("title", "engine").
+		OrWhereFullText("body", "models")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_where_fulltext" where to_tsvector('english', "title") @@ plainto_tsquery('english', $1) or to_tsvector('english', "body") @@ plainto_tsquery('english', $2)`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select * from `table_test_where_fulltext` where `table_test_where_fulltext` match '{title} : (' || ? || ')' or `table_test_where_fulltext` match '{body} : (' || ? || ')'", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_where_fulltext` where match (`title`) against (? in natural language mode) or match (`body`) against (? in natural language mode)", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+}
+
+func TestWhereOrderByFullText(t *testing.T) {
+	NewTableForWhereFullTextTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_fulltext").
+		WhereFullText("title,body", "xun").
+		OrderByFullText("title,body", "xun")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_where_fulltext" where (to_tsvector('english', "title") || to_tsvector('english', "body")) @@ plainto_tsquery('english', $1) order by ts_rank((to_tsvector('english', "title") || to_tsvector('english', "body")), plainto_tsquery('english', $2)) desc`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select * from `table_test_where_fulltext` where `table_test_where_fulltext` match '{title body} : (' || ? || ')' order by bm25(`table_test_where_fulltext`) asc", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_where_fulltext` where match (`title`, `body`) against (? in natural language mode) order by match (`title`, `body`) against (? in natural language mode) desc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, "Xun query builder", rows[0]["title"].(string), "the title of the 1st row should be Xun query builder")
+		assert.Equal(t, "Yao app engine", rows[1]["title"].(string), "the title of the 2nd row should be Yao app engine")
+	}
+}
+
+func TestWhereOrderByFullTextThenWhere(t *testing.T) {
+	NewTableForWhereFullTextTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_where_fulltext").
+		OrderByFullText("title,body", "xun").
+		WhereFullText("title,body", "xun").
+		Where("title", "<>", "Gou runtime")
+
+	// the placeholders of the order should be numbered after the where clauses
+	sql := dbal.Grammars["postgres"].CompileSelect(qb.Builder().Query)
+	assert.Equal(t, `select * from "table_test_where_fulltext" where (to_tsvector('english', "title") || to_tsvector('english', "body")) @@ plainto_tsquery('english', $1) and "title" <> $2 order by ts_rank((to_tsvector('english', "title") || to_tsvector('english', "body")), plainto_tsquery('english', $3)) desc`, sql, "the query sql not equal")
+
+	bindings := qb.GetBindings()
+	if unit.DriverIs("sqlite3") {
+		assert.Equal(t, []interface{}{"xun", "Gou runtime"}, bindings, "the bindings should be the where values")
+	} else {
+		assert.Equal(t, []interface{}{"xun", "Gou runtime", "xun"}, bindings, "the where values should be bound before the search value")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows), "the return value should be have 2 rows")
+	if len(rows) == 2 {
+		assert.Equal(t, "Xun query builder", rows[0]["title"].(string), "the title of the 1st row should be Xun query builder")
+	}
+}
+
+func TestWhereFullTextFromSub(t *testing.T) {
+	if !unit.DriverIs("sqlite3") {
+		return
+	}
+
+	NewTableForWhereFullTextTest()
+	qb := getTestBuilder()
+	qb.FromSub(func(sub Query) {
+		sub.Table("table_test_where_fulltext")
+	}, "t").
+		WhereFullText("title,body", "xun").
+		OrderByFullText("title,body", "xun")
+
+	assert.NotPanics(t, func() { qb.ToSQL() }, "the full text search of the subquery should not be panic")
+	_, err := qb.Get()
+	assert.EqualError(t, err, "The full text search requires a FTS5 table", "the error should be returned")
+
+	qb = getTestBuilder()
+	qb.FromRaw("table_test_where_fulltext").
+		Where(func(qb Query) {
+			qb.WhereFullText("title", "xun")
+		})
+	_, err = qb.Get()
+	assert.EqualError(t, err, "The full text search requires a FTS5 table", "the error of the nested where should be returned")
+}
+
 // clean the test data
 func TestWhereClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
 	builder.DropTableIfExists("table_test_where")
+	builder.DropTableIfExists("table_test_where_json")
+	if unit.DriverIs("sqlite3") {
+		getTestBuilder().Builder().Conn.Write.Exec("DROP TABLE IF EXISTS `table_test_where_fulltext`")
+		return
+	}
+	builder.DropTableIfExists("table_test_where_fulltext")
 }
 
 func NewTableForWhereTest() {
@@ -1550,6 +1910,61 @@ func NewTableForWhereTest() {
 	})
 }
 
+func NewTableForWhereJSONTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_where_json")
+	builder.MustCreateTable("table_test_where_json", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.JSON("options")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_where_json").Insert([]xun.R{
+		{"email": "john@yao.run", "options": `{"dining":{"meal":"salad"},"languages":[],"enabled":true}`},
+		{"email": "lee@yao.run", "options": `{"dining":{"meal":"pizza"},"languages":["fr"],"enabled":false}`},
+		{"email": "ken@yao.run", "options": `{"dining":{"meal":"salad"},"languages":["de","en"],"enabled":false}`},
+	})
+}
+
+func NewTableForWhereFullTextTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	qb := getTestBuilder()
+	if unit.DriverIs("sqlite3") {
+		// the full text search of SQLite requires a FTS5 virtual table
+		_, err := qb.Builder().Conn.Write.Exec("DROP TABLE IF EXISTS `table_test_where_fulltext`")
+		if err != nil {
+			panic(err)
+		}
+		_, err = qb.Builder().Conn.Write.Exec("CREATE VIRTUAL TABLE `table_test_where_fulltext` USING fts5(title, body)")
+		if err != nil {
+			panic(err)
+		}
+	} else {
+		builder.DropTableIfExists("table_test_where_fulltext")
+		builder.MustCreateTable("table_test_where_fulltext", func(table schema.Blueprint) {
+			table.ID("id")
+			table.String("title")
+			table.Text("body")
+		})
+	}
+
+	if unit.DriverIs("mysql") {
+		_, err := qb.Builder().Conn.Write.Exec("ALTER TABLE `table_test_where_fulltext` ADD FULLTEXT `table_test_where_fulltext_fulltext` (`title`, `body`)")
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	qb.Table("table_test_where_fulltext").Insert([]xun.R{
+		{"title": "Xun query builder", "body": "Xun is a lightweight database abstraction layer, the xun query builder is written in golang"},
+		{"title": "Yao app engine", "body": "Build web applications with the xun database"},
+		{"title": "Gou runtime", "body": "Process and models runtime"},
+	})
+}
+
 func checkVoteGT(t *testing.T, qb Query) {
 	// checking sql
 	sql := qb.ToSQL()
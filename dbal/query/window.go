@@ -0,0 +1,108 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// SelectWindow Add a window function column to the query.
+// SelectWindow("row_number()", "cate", "score desc", "rank")
+// SelectWindow("sum(score)", []string{"cate"}, "id", "total", "rows between unbounded preceding and current row")
+func (builder *Builder) SelectWindow(fn interface{}, partitionBy interface{}, orderBy interface{}, alias string, frame ...string) Query {
+	builder.addSelect(dbal.WindowFunction{
+		Func:   fn,
+		Window: builder.makeWindow("", partitionBy, orderBy, frame...),
+		Alias:  alias,
+	})
+	return builder
+}
+
+// SelectOver Add a window function column over the named window to the query.
+// SelectOver("sum(score)", "w", "total")
+func (builder *Builder) SelectOver(fn interface{}, window string, alias string) Query {
+	builder.addSelect(dbal.WindowFunction{
+		Func:   fn,
+		Window: dbal.Window{Name: window},
+		Alias:  alias,
+	})
+	return builder
+}
+
+// Window Add a named window definition to the query.
+// Window("w", "cate", "score desc")
+// Window("w", "cate", "id", "rows between 1 preceding and 1 following")
+func (builder *Builder) Window(name string, partitionBy interface{}, orderBy interface{}, frame ...string) Query {
+	builder.Query.Windows = append(builder.Query.Windows, builder.makeWindow(name, partitionBy, orderBy, frame...))
+	return builder
+}
+
+// makeWindow make a new window definition
+func (builder *Builder) makeWindow(name string, partitionBy interface{}, orderBy interface{}, frame ...string) dbal.Window {
+	window := dbal.Window{
+		Name:        name,
+		PartitionBy: builder.prepareWindowColumns(partitionBy),
+		OrderBy:     builder.prepareWindowOrders(orderBy),
+	}
+
+	if len(frame) > 0 && frame[0] != "" {
+		units := strings.ToLower(strings.Split(strings.TrimSpace(frame[0]), " ")[0])
+		if !utils.StringHave([]string{"rows", "range", "groups"}, units) {
+			panic(fmt.Errorf(`Window frame must start with "rows", "range" or "groups"`))
+		}
+		window.Frame = strings.TrimSpace(frame[0])
+	}
+	return window
+}
+
+// prepareWindowColumns parse the partition by columns
+func (builder *Builder) prepareWindowColumns(columns interface{}) []interface{} {
+	switch columns.(type) {
+	case nil:
+		return []interface{}{}
+	case string:
+		values := []interface{}{}
+		for _, column := range strings.Split(columns.(string), ",") {
+			column = strings.TrimSpace(column)
+			if column != "" {
+				values = append(values, column)
+			}
+		}
+		return values
+	case []string:
+		values := []interface{}{}
+		for _, column := range columns.([]string) {
+			values = append(values, column)
+		}
+		return values
+	case []interface{}:
+		return columns.([]interface{})
+	}
+	return []interface{}{columns}
+}
+
+// prepareWindowOrders parse the order by columns. eg: "score desc, id"
+func (builder *Builder) prepareWindowOrders(columns interface{}) []dbal.Order {
+	orders := []dbal.Order{}
+	for _, column := range builder.prepareWindowColumns(columns) {
+		value, ok := column.(string)
+		if !ok {
+			orders = append(orders, dbal.Order{Type: "basic", Column: column, Direction: "asc"})
+			continue
+		}
+
+		direction := "asc"
+		fields := strings.Fields(value)
+		if len(fields) == 2 {
+			value = fields[0]
+			direction = strings.ToLower(fields[1])
+		}
+		if !utils.StringHave([]string{"asc", "desc"}, direction) {
+			panic(fmt.Errorf(`Order direction must be "asc" or "desc`))
+		}
+		orders = append(orders, dbal.Order{Type: "basic", Column: value, Direction: direction})
+	}
+	return orders
+}
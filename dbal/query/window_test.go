@@ -0,0 +1,161 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/grammar/mysql"
+	"github.com/yaoapp/xun/grammar/sqlite3"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestWindowSelectWindow(t *testing.T) {
+	NewTableForWindowTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_window as t").
+		Select("t.id", "t.cate", "t.score").
+		SelectWindow("row_number()", "t.cate", "t.score desc, t.id", "rank").
+		OrderBy("t.id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "t"."id", "t"."cate", "t"."score", row_number() over (partition by "t"."cate" order by "t"."score" desc, "t"."id" asc) as "rank" from "table_test_window" as "t" order by "t"."id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `t`.`id`, `t`.`cate`, `t`.`score`, row_number() over (partition by `t`.`cate` order by `t`.`score` desc, `t`.`id` asc) as `rank` from `table_test_window` as `t` order by `t`.`id` asc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 5, len(rows), "the return value should has 5 rows")
+	if len(rows) == 5 {
+		assert.Equal(t, int64(2), rows[0]["rank"].(int64), "the rank of the 1st row should be 2")
+		assert.Equal(t, int64(1), rows[1]["rank"].(int64), "the rank of the 2nd row should be 1")
+		assert.Equal(t, int64(1), rows[4]["rank"].(int64), "the rank of the 5th row should be 1")
+	}
+}
+
+func TestWindowSelectWindowFrame(t *testing.T) {
+	NewTableForWindowTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_window").
+		Select("id").
+		SelectWindow("sum(score)", nil, "id", "total", "rows between unbounded preceding and current row").
+		OrderBy("id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id", sum("score") over (order by "id" asc rows between unbounded preceding and current row) as "total" from "table_test_window" order by "id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id`, sum(`score`) over (order by `id` asc rows between unbounded preceding and current row) as `total` from `table_test_window` order by `id` asc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 5, len(rows), "the return value should has 5 rows")
+	if len(rows) == 5 {
+		assert.Equal(t, float64(15), xun.MakeN(rows[4]["total"]).MustToFixed(0), "the total of the 5th row should be 15")
+	}
+
+	assert.Panics(t, func() {
+		qb.Table("table_test_window").SelectWindow("sum(score)", nil, "id", "total", "between unbounded preceding and current row")
+	}, "the frame should start with rows, range or groups")
+}
+
+func TestWindowSelectOver(t *testing.T) {
+	NewTableForWindowTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_window").
+		Select("id").
+		SelectOver("rank()", "w", "rank").
+		SelectOver("count(*)", "w", "total").
+		Window("w", []string{"cate"}, "score desc").
+		OrderBy("id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id", rank() over "w" as "rank", count(*) over "w" as "total" from "table_test_window" window "w" as (partition by "cate" order by "score" desc) order by "id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id`, rank() over `w` as `rank`, count(*) over `w` as `total` from `table_test_window` window `w` as (partition by `cate` order by `score` desc) order by `id` asc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 5, len(rows), "the return value should has 5 rows")
+	if len(rows) == 5 {
+		assert.Equal(t, int64(2), rows[0]["rank"].(int64), "the rank of the 1st row should be 2")
+		assert.Equal(t, int64(2), rows[0]["total"].(int64), "the running total of the 1st row should be 2")
+		assert.Equal(t, int64(1), rows[4]["total"].(int64), "the running total of the 5th row should be 1")
+	}
+}
+
+// clean the test data
+func TestWindowSelectWindowArguments(t *testing.T) {
+	NewTableForWindowTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_window").
+		Select("id").
+		SelectWindow("count(distinct cate)", nil, nil, "cates").
+		SelectWindow("lag(score, 1, null)", nil, "id", "prev").
+		SelectWindow("first_value(coalesce(score, 0))", nil, "id", "first").
+		SelectWindow(dbal.Raw("max(case when cate = 'a,b' then score end)"), nil, nil, "top").
+		OrderBy("id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id", count(distinct cate) over () as "cates", lag("score", 1, null) over (order by "id" asc) as "prev", first_value(coalesce(score, 0)) over (order by "id" asc) as "first", max(case when cate = 'a,b' then score end) over () as "top" from "table_test_window" order by "id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id`, count(distinct cate) over () as `cates`, lag(`score`, 1, null) over (order by `id` asc) as `prev`, first_value(coalesce(score, 0)) over (order by `id` asc) as `first`, max(case when cate = 'a,b' then score end) over () as `top` from `table_test_window` order by `id` asc", sql, "the query sql not equal")
+	}
+}
+
+func TestWindowVersion(t *testing.T) {
+	qb := getTestBuilder().New()
+	qb.Table("table_test_window").SelectWindow("row_number()", "cate", "id", "rank")
+
+	grammarMySQL := dbal.Grammars["mysql"].(mysql.MySQL)
+	grammarMySQL.Version = &dbal.Version{Version: semver.MustParse("8.0.0"), Driver: "mysql"}
+	assert.Nil(t, grammarMySQL.CheckQuery(qb.Builder().Query), "the window functions should be supported by MySQL 8.0")
+	grammarMySQL.Version = &dbal.Version{Version: semver.MustParse("5.7.33"), Driver: "mysql"}
+	err := grammarMySQL.CheckQuery(qb.Builder().Query)
+	assert.Contains(t, err.Error(), "MySQL 8.0+ is required (current: 5.7.33)", "the error should be returned")
+
+	grammarSQLite := dbal.Grammars["sqlite3"].(sqlite3.SQLite3)
+	grammarSQLite.Version = &dbal.Version{Version: semver.MustParse("3.25.0"), Driver: "sqlite3"}
+	assert.Nil(t, grammarSQLite.CheckQuery(qb.Builder().Query), "the window functions should be supported by SQLite 3.25.0")
+	grammarSQLite.Version = &dbal.Version{Version: semver.MustParse("3.24.0"), Driver: "sqlite3"}
+	err = grammarSQLite.CheckQuery(qb.Builder().Query)
+	assert.Contains(t, err.Error(), "SQLite 3.25.0+ is required (current: 3.24.0)", "the error should be returned")
+}
+
+func TestWindowClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_window")
+}
+
+func NewTableForWindowTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_window")
+	builder.MustCreateTable("table_test_window", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("cate")
+		table.Integer("score")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_window").Insert([]xun.R{
+		{"cate": "A", "score": 2},
+		{"cate": "A", "score": 5},
+		{"cate": "A", "score": 1},
+		{"cate": "B", "score": 3},
+		{"cate": "B", "score": 4},
+	})
+}
@@ -0,0 +1,59 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/yaoapp/xun/dbal"
+)
+
+// With Add a common table expression to the query. (MySQL 8.0+)
+func (builder *Builder) With(name string, query interface{}, columns ...string) Query {
+	return builder.with(name, query, columns, false, false)
+}
+
+// WithRecursive Add a recursive common table expression to the query.
+func (builder *Builder) WithRecursive(name string, columns []string, query interface{}) Query {
+	return builder.with(name, query, columns, true, false)
+}
+
+// WithMaterialized Add a materialized common table expression to the query. (the materialized hint is only compiled for PostgreSQL)
+func (builder *Builder) WithMaterialized(name string, query interface{}, columns ...string) Query {
+	return builder.with(name, query, columns, false, true)
+}
+
+// with Add a common table expression to the query.
+func (builder *Builder) with(name string, query interface{}, columns []string, recursive bool, materialized bool) *Builder {
+
+	cte := dbal.CTE{
+		Name:         name,
+		Columns:      columns,
+		Recursive:    recursive,
+		Materialized: materialized,
+	}
+
+	switch query.(type) {
+	case *Builder:
+		qb := query.(*Builder)
+		cte.Query = qb.Query
+		builder.Query.AddBinding("with", qb.GetBindings())
+		break
+	case func(Query):
+		callback := query.(func(Query))
+		qb := builder.new()
+		callback(qb)
+		cte.Query = qb.Query
+		builder.Query.AddBinding("with", qb.GetBindings())
+		break
+	case dbal.Expression:
+		cte.SQL = query.(dbal.Expression).GetValue()
+		break
+	case string:
+		cte.SQL = query.(string)
+		break
+	default:
+		panic(fmt.Errorf("the query of the common table expression must be a query builder instance, a Closure, or a string"))
+	}
+
+	builder.Query.CTEs = append(builder.Query.CTEs, cte)
+	return builder
+}
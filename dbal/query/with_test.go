@@ -0,0 +1,176 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/grammar/mysql"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestWithWith(t *testing.T) {
+	NewTableForWithTest()
+	qb := getTestBuilder().New()
+	qb.With("children", func(qb Query) {
+		qb.Table("table_test_with").
+			Where("parent_id", 2).
+			Select("id", "name")
+	}).
+		From("children").
+		Where("id", ">", 3)
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `with "children" as (select "id", "name" from "table_test_with" where "parent_id" = $1) select * from "children" where "id" > $2`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "with `children` as (select `id`, `name` from `table_test_with` where `parent_id` = ?) select * from `children` where `id` > ?", sql, "the query sql not equal")
+	}
+
+	bindings := qb.GetBindings()
+	assert.Equal(t, 2, len(bindings), "the bindings should have 2 items")
+	if len(bindings) == 2 {
+		assert.Equal(t, 2, bindings[0].(int), "the 1st binding should be 2")
+		assert.Equal(t, 3, bindings[1].(int), "the 2nd binding should be 3")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should has 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, "Laptops", rows[0]["name"].(string), "the name of the first row should be Laptops")
+	}
+	assert.Equal(t, int64(1), qb.MustCount(), "the count should be 1")
+}
+
+func TestWithWithRecursive(t *testing.T) {
+	NewTableForWithTest()
+	qb := getTestBuilder().New()
+	qb.WithRecursive("tree", []string{"id", "name", "depth"}, func(qb Query) {
+		qb.Table("table_test_with").
+			Select("id", "name", dbal.Raw("1")).
+			WhereNull("parent_id").
+			Where("name", "Products").
+			UnionAll(func(qb Query) {
+				qb.From("table_test_with as menu").
+					Join("tree", "tree.id", "=", "menu.parent_id").
+					Select("menu.id", "menu.name", dbal.Raw("tree.depth + 1"))
+			})
+	}).
+		From("tree").
+		OrderBy("id")
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `with recursive "tree" ("id", "name", "depth") as (select "id", "name", 1 from "table_test_with" where "parent_id" is null and "name" = $1 union all select "menu"."id", "menu"."name", tree.depth + 1 from "table_test_with" as "menu" inner join "tree" on "tree"."id" = "menu"."parent_id") select * from "tree" order by "id" asc`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "with recursive `tree` (`id`, `name`, `depth`) as (select `id`, `name`, 1 from `table_test_with` where `parent_id` is null and `name` = ? union all select `menu`.`id`, `menu`.`name`, tree.depth + 1 from `table_test_with` as `menu` inner join `tree` on `tree`.`id` = `menu`.`parent_id`) select * from `tree` order by `id` asc", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 4, len(rows), "the return value should has 4 rows")
+	if len(rows) == 4 {
+		assert.Equal(t, "Products", rows[0]["name"].(string), "the name of the 1st row should be Products")
+		assert.Equal(t, int64(1), rows[0]["depth"].(int64), "the depth of the 1st row should be 1")
+		assert.Equal(t, "Laptops", rows[2]["name"].(string), "the name of the 3rd row should be Laptops")
+		assert.Equal(t, int64(2), rows[2]["depth"].(int64), "the depth of the 3rd row should be 2")
+		assert.Equal(t, "Gaming Laptops", rows[3]["name"].(string), "the name of the 4th row should be Gaming Laptops")
+		assert.Equal(t, int64(3), rows[3]["depth"].(int64), "the depth of the 4th row should be 3")
+	}
+}
+
+func TestWithWithMaterialized(t *testing.T) {
+	NewTableForWithTest()
+	qb := getTestBuilder().New()
+	qb.WithMaterialized("roots", func(qb Query) {
+		qb.Table("table_test_with").WhereNull("parent_id")
+	}).
+		With("ids", "select 1 as id", "id").
+		From("roots").
+		WhereIn("id", func(qb Query) {
+			qb.From("ids").Select("id")
+		})
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `with "roots" as materialized (select * from "table_test_with" where "parent_id" is null), "ids" ("id") as (select 1 as id) select * from "roots" where "id" in (select "id" from "ids")`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "with `roots` as (select * from `table_test_with` where `parent_id` is null), `ids` (`id`) as (select 1 as id) select * from `roots` where `id` in (select `id` from `ids`)", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should has 1 row")
+	if len(rows) == 1 {
+		assert.Equal(t, "Home", rows[0]["name"].(string), "the name of the first row should be Home")
+	}
+}
+
+func TestWithWithGrammarWhere(t *testing.T) {
+	qb := getTestBuilder().New()
+	qb.With("recent", func(qb Query) {
+		qb.Table("table_test_with").
+			WhereDate("created_at", "2021-05-01").
+			WhereJSONContains("tags", "phone")
+	}).
+		From("recent").
+		Where("id", ">", 3)
+
+	// the subquery of the expression should be compiled by the grammar of each database
+	sql := dbal.Grammars["postgres"].CompileSelect(qb.(*Builder).Query)
+	assert.Equal(t, `with "recent" as (select * from "table_test_with" where "created_at"::date =$1 and ("tags")::jsonb @> $2) select * from "recent" where "id" > $3`, sql, "the query sql not equal")
+
+	sql = dbal.Grammars["sqlite3"].CompileSelect(qb.(*Builder).Query)
+	assert.Equal(t, "with `recent` as (select * from `table_test_with` where strftime('%Y-%m-%d',`created_at`) = cast(? as text) and not exists (select 1 from json_each(?) as `contains` where `contains`.`value` not in (select `value` from json_each(`tags`, '$')))) select * from `recent` where `id` > ?", sql, "the query sql not equal")
+
+	sql = dbal.Grammars["mysql"].CompileSelect(qb.(*Builder).Query)
+	assert.Equal(t, "with `recent` as (select * from `table_test_with` where date(`created_at`)=? and json_contains(`tags`, ?, '$')) select * from `recent` where `id` > ?", sql, "the query sql not equal")
+}
+
+// clean the test data
+func TestWithWithMySQLVersion(t *testing.T) {
+	grammar := dbal.Grammars["mysql"].(mysql.MySQL)
+	qb := getTestBuilder().New()
+	qb.With("children", func(qb Query) {
+		qb.Table("table_test_with").Where("parent_id", 2)
+	}).From("children")
+
+	grammar.Version = &dbal.Version{Version: semver.MustParse("8.0.0"), Driver: "mysql"}
+	assert.Nil(t, grammar.CheckQuery(qb.Builder().Query), "the common table expressions should be supported by MySQL 8.0")
+
+	grammar.Version = &dbal.Version{Version: semver.MustParse("5.7.33"), Driver: "mysql"}
+	err := grammar.CheckQuery(qb.Builder().Query)
+	assert.Contains(t, err.Error(), "MySQL 8.0+ is required (current: 5.7.33)", "the error should be returned")
+}
+
+func TestWithClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_with")
+}
+
+func NewTableForWithTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_with")
+	builder.MustCreateTable("table_test_with", func(table schema.Blueprint) {
+		table.ID("id")
+		table.BigInteger("parent_id").Null()
+		table.String("name")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_with").Insert([]xun.R{
+		{"name": "Home", "parent_id": nil},
+		{"name": "Products", "parent_id": nil},
+		{"name": "Phones", "parent_id": 2},
+		{"name": "Laptops", "parent_id": 2},
+		{"name": "Gaming Laptops", "parent_id": 4},
+	})
+}
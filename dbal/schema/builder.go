@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -105,6 +106,21 @@ func (builder *Builder) table(name string) *Table {
 	return table
 }
 
+// WithContext create a new schema builder instance, the statements of it will be executed using the given context.
+func (builder *Builder) WithContext(ctx context.Context) Schema {
+	new := *builder
+	new.Grammar = builder.Grammar.WithContext(ctx)
+	return &new
+}
+
+// WithTransaction create a new schema builder instance, the statements of it will be executed within the given transaction.
+// Notes: MySQL commits the transaction implicitly when executing the DDL statements.
+func (builder *Builder) WithTransaction(tx *sqlx.Tx) Schema {
+	new := *builder
+	new.Grammar = builder.Grammar.WithTransaction(tx)
+	return &new
+}
+
 // SetOption set the option of connection
 func (builder *Builder) SetOption(option *dbal.Option) {
 	builder.Conn.Option = option
@@ -219,6 +235,22 @@ func (builder *Builder) GetTable(name string) (Blueprint, error) {
 		}
 	}
 
+	// attaching foreign keys
+	for _, foreign := range table.Table.Foreigns {
+		table.ForeignMap[foreign.Name] = &Foreign{
+			Foreign: foreign,
+			Table:   table,
+		}
+	}
+
+	// attaching constraints
+	for _, constraint := range table.Table.Constraints {
+		table.ConstraintMap[constraint.Name] = &Constraint{
+			Constraint: constraint,
+			Table:      table,
+		}
+	}
+
 	// attaching primary
 	if table.Table.Primary != nil {
 		table.Primary = &Primary{
@@ -241,11 +273,16 @@ func (builder *Builder) MustGetTable(name string) Blueprint {
 func (builder *Builder) CreateTable(name string, callback func(table Blueprint)) error {
 	table := builder.table(name)
 	callback(table)
-	err := builder.Grammar.CreateTable(table.Table)
+	return builder.createTable(table)
+}
+
+// createTable create the table after the foreign keys are checked
+func (builder *Builder) createTable(table *Table) error {
+	err := checkForeigns(table.Table)
 	if err != nil {
 		return err
 	}
-	return nil
+	return builder.Grammar.CreateTable(table.Table)
 }
 
 // MustCreateTable create a new table on the schema.
@@ -258,11 +295,11 @@ func (builder *Builder) MustCreateTable(name string, callback func(table Bluepri
 func (builder *Builder) AlterTable(name string, callback func(table Blueprint)) error {
 	table := builder.MustGetTable(name)
 	callback(table)
-	err := builder.Grammar.AlterTable(table.Get().Table)
+	err := checkForeigns(table.Get().Table)
 	if err != nil {
 		return err
 	}
-	return nil
+	return builder.Grammar.AlterTable(table.Get().Table)
 }
 
 // MustAlterTable alter a table on the schema.
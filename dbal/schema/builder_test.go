@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -180,6 +181,21 @@ func TestBuilderHasTable(t *testing.T) {
 	assert.False(t, has, "the return value should be false")
 }
 
+func TestBuilderWithContext(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	_, err := builder.WithContext(context.Background()).HasTable("table_test_builder")
+	assert.True(t, err == nil, "the return error should be nil")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = builder.WithContext(ctx).HasTable("table_test_builder")
+	assert.ErrorIs(t, err, context.Canceled, "the return error should be context canceled")
+
+	_, err = builder.HasTable("table_test_builder")
+	assert.True(t, err == nil, "the context of the origin builder should not be changed")
+}
+
 func TestBuilderCreateTable(t *testing.T) {
 	defer unit.Catch()
 	builder := getTestBuilder()
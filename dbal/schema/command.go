@@ -46,3 +46,23 @@ func (table *Table) dropIndexCommand(name string, success func(), fail func()) {
 func (table *Table) renameIndexCommand(old string, new string, success func(), fail func()) {
 	table.AddCommand("RenameIndex", success, fail, old, new)
 }
+
+// createForeignCommand add a new command that creating a foreign key
+func (table *Table) createForeignCommand(foreign *dbal.Foreign, success func(), fail func()) {
+	table.AddCommand("CreateForeign", success, fail, foreign)
+}
+
+// dropForeignCommand add a new command that dropping a foreign key
+func (table *Table) dropForeignCommand(name string, success func(), fail func()) {
+	table.AddCommand("DropForeign", success, fail, name)
+}
+
+// createConstraintCommand add a new command that creating a unique or check constraint
+func (table *Table) createConstraintCommand(constraint *dbal.Constraint, success func(), fail func()) {
+	table.AddCommand("CreateConstraint", success, fail, constraint)
+}
+
+// dropConstraintCommand add a new command that dropping a unique or check constraint
+func (table *Table) dropConstraintCommand(name string, typ string, success func(), fail func()) {
+	table.AddCommand("DropConstraint", success, fail, name, typ)
+}
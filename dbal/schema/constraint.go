@@ -1,3 +1,103 @@
 package schema
 
 // the constraint methods definition
+
+// GetConstraint get the constraint instance for the given name, if the constraint does not exist return nil.
+func (table *Table) GetConstraint(name string) *Constraint {
+	return table.ConstraintMap[name]
+}
+
+// GetConstraints Get the unique and check constraints map of the table
+func (table *Table) GetConstraints() map[string]*Constraint {
+	return table.ConstraintMap
+}
+
+// HasConstraint Determine if the table has the given constraints.
+func (table *Table) HasConstraint(name ...string) bool {
+	has := true
+	for _, n := range name {
+		_, has = table.ConstraintMap[n]
+		if !has {
+			return has
+		}
+	}
+	return has
+}
+
+// GetUniqueConstraint get the unique constraint instance for the given name, if the unique constraint does not exist return nil.
+func (table *Table) GetUniqueConstraint(name string) *Constraint {
+	return table.getConstraint(name, "UNIQUE")
+}
+
+// AddUniqueConstraint Indicate that the given unique constraint should be created.
+//
+//	table.AddUniqueConstraint("name_email", "name", "email")
+func (table *Table) AddUniqueConstraint(name string, columnNames ...string) *Constraint {
+	constraint := table.newConstraint(name, "UNIQUE")
+	constraint.Columns = columnNames
+	table.pushConstraint(constraint)
+	table.createConstraintCommand(constraint.Constraint, nil, func() {
+		delete(table.ConstraintMap, constraint.Name)
+	})
+	return constraint
+}
+
+// DropUniqueConstraint Indicate that the given unique constraints should be dropped.
+func (table *Table) DropUniqueConstraint(name ...string) {
+	for _, n := range name {
+		table.dropConstraintCommand(n, "UNIQUE", func() {
+			delete(table.ConstraintMap, n)
+		}, nil)
+	}
+}
+
+// GetCheck get the check constraint instance for the given name, if the check constraint does not exist return nil.
+func (table *Table) GetCheck(name string) *Constraint {
+	return table.getConstraint(name, "CHECK")
+}
+
+// AddCheck Indicate that the given check constraint should be created.
+//
+//	table.AddCheck("vote_range", "vote >= 0 AND vote <= 100")
+func (table *Table) AddCheck(name string, expression string) *Constraint {
+	constraint := table.newConstraint(name, "CHECK")
+	constraint.Expression = expression
+	table.pushConstraint(constraint)
+	table.createConstraintCommand(constraint.Constraint, nil, func() {
+		delete(table.ConstraintMap, constraint.Name)
+	})
+	return constraint
+}
+
+// DropCheck Indicate that the given check constraints should be dropped.
+func (table *Table) DropCheck(name ...string) {
+	for _, n := range name {
+		table.dropConstraintCommand(n, "CHECK", func() {
+			delete(table.ConstraintMap, n)
+		}, nil)
+	}
+}
+
+// getConstraint get the constraint instance for the given name and type
+func (table *Table) getConstraint(name string, typ string) *Constraint {
+	constraint, has := table.ConstraintMap[name]
+	if !has || constraint.Type != typ {
+		return nil
+	}
+	return constraint
+}
+
+// newConstraint Create a new constraint instance
+func (table *Table) newConstraint(name string, typ string) *Constraint {
+	return &Constraint{
+		Constraint: table.Table.NewTableConstraint(name, typ),
+		Table:      table,
+	}
+}
+
+// pushConstraint add a constraint to the table
+func (table *Table) pushConstraint(constraint *Constraint) *Table {
+	table.Table.PushConstraint(constraint.Constraint)
+	table.ConstraintMap[constraint.Name] = constraint
+	return table
+}
@@ -0,0 +1,157 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/grammar/mysql"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestConstraintCreateTable(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForConstraintTest()
+
+	table := builder.MustGetTable("table_test_constraint")
+	assert.True(t, table.HasConstraint("name_email", "vote_range"), "the table should have the name_email and vote_range constraints")
+	assert.Equal(t, 2, len(table.GetConstraints()), "the table should have 2 constraints")
+	for name, index := range table.GetIndexes() {
+		assert.NotEqual(t, "unique", index.Type, "the unique index %s of the constraint should not be listed as an index", name)
+	}
+
+	unique := table.GetUniqueConstraint("name_email")
+	assert.NotNil(t, unique, "the name_email unique constraint should be returned")
+	if unique != nil {
+		assert.Equal(t, []string{"name", "email"}, unique.Columns, "the columns of the name_email constraint should be name and email")
+	}
+	assert.Nil(t, table.GetUniqueConstraint("vote_range"), "the vote_range constraint is not an unique constraint")
+
+	check := table.GetCheck("vote_range")
+	assert.NotNil(t, check, "the vote_range check constraint should be returned")
+	if check != nil && unit.DriverIs("sqlite3") {
+		assert.Equal(t, "vote >= 0", check.Expression, "the expression of the vote_range constraint should be vote >= 0")
+	} else if check != nil {
+		assert.Contains(t, check.Expression, ">= 0", "the expression of the vote_range constraint should contain >= 0")
+	}
+}
+
+func TestConstraintViolation(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForConstraintTest()
+	db := builder.MustGetDB()
+
+	_, err := db.Exec("INSERT INTO table_test_constraint (name, email, vote) VALUES ('Max', 'max@example.com', 1)")
+	assert.Equal(t, nil, err, "the return error should be nil")
+
+	_, err = db.Exec("INSERT INTO table_test_constraint (name, email, vote) VALUES ('Max', 'max@example.com', 2)")
+	assert.False(t, err == nil, "the unique constraint should be violated")
+
+	_, err = db.Exec("INSERT INTO table_test_constraint (name, email, vote) VALUES ('Ava', 'ava@example.com', -1)")
+	assert.False(t, err == nil, "the check constraint should be violated")
+}
+
+func TestConstraintAlterTable(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForConstraintTest()
+	builder.MustGetDB().MustExec("INSERT INTO table_test_constraint (name, email, vote) VALUES ('Max', 'max@example.com', 1)")
+
+	// Create
+	builder.MustAlterTable("table_test_constraint", func(table Blueprint) {
+		table.AddUniqueConstraint("email", "email")
+		table.AddCheck("vote_max", "vote <= 100")
+	})
+	table := builder.MustGetTable("table_test_constraint")
+	assert.True(t, table.HasConstraint("name_email", "vote_range", "email", "vote_max"), "the table should have the constraints")
+
+	count := 0
+	err := builder.MustGetDB().Get(&count, "SELECT COUNT(*) FROM table_test_constraint")
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.Equal(t, 1, count, "the rows of the table should be kept")
+
+	// Drop
+	builder.MustAlterTable("table_test_constraint", func(table Blueprint) {
+		table.DropUniqueConstraint("name_email")
+		table.DropCheck("vote_range")
+	})
+	table = builder.MustGetTable("table_test_constraint")
+	assert.False(t, table.HasConstraint("name_email"), "the table should not have the name_email constraint")
+	assert.False(t, table.HasConstraint("vote_range"), "the table should not have the vote_range constraint")
+	assert.True(t, table.HasConstraint("email", "vote_max"), "the table should have the email and vote_max constraints")
+
+	// Drop Fail
+	err = builder.AlterTable("table_test_constraint", func(table Blueprint) {
+		table.DropCheck("vote_range")
+	})
+	assert.False(t, err == nil, "The return error should not be nil")
+}
+
+func TestConstraintAdd(t *testing.T) {
+	builder := getTestBuilderInstance()
+	table := NewTable("table_test_constraint", builder)
+
+	unique := table.AddUniqueConstraint("name_email", "name", "email")
+	assert.Equal(t, "UNIQUE", unique.Type, "the type of the constraint should be UNIQUE")
+	assert.Equal(t, []string{"name", "email"}, unique.Columns, "the columns of the constraint should be name and email")
+	assert.Equal(t, table, unique.Table, "the constraint should belong to the table")
+
+	check := table.AddCheck("vote_range", "vote >= 0")
+	assert.Equal(t, "CHECK", check.Type, "the type of the constraint should be CHECK")
+	assert.Equal(t, "vote >= 0", check.Expression, "the expression of the constraint should be vote >= 0")
+	assert.Equal(t, check, table.GetCheck("vote_range"), "the constraint should be returned by GetCheck")
+}
+
+func TestConstraintUniqueIndexWithTablePrefix(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForConstraintTest()
+
+	// the unique index named with the table prefix is not an unique constraint
+	builder.MustAlterTable("table_test_constraint", func(table Blueprint) {
+		table.AddUnique("table_test_constraint_vote", "vote")
+	})
+
+	table := builder.MustGetTable("table_test_constraint")
+	assert.True(t, table.HasIndex("table_test_constraint_vote"), "the table should have the table_test_constraint_vote index")
+	assert.False(t, table.HasConstraint("vote"), "the unique index should not be listed as a constraint")
+	assert.Equal(t, 2, len(table.GetConstraints()), "the table should have 2 constraints")
+}
+
+func TestConstraintCheckMySQLVersion(t *testing.T) {
+	grammar := dbal.Grammars["mysql"].(mysql.MySQL)
+	grammar.Version = &dbal.Version{Version: semver.MustParse("8.0.15"), Driver: "mysql"}
+
+	table := dbal.NewTable("table_test_constraint", "", "")
+	constraint := dbal.NewConstraint("", "table_test_constraint", "")
+	constraint.Name = "vote_range"
+	constraint.Type = "CHECK"
+	constraint.Expression = "vote >= 0"
+	table.AddCommand("CreateConstraint", nil, nil, constraint)
+
+	err := grammar.AlterTable(table)
+	assert.Contains(t, err.Error(), "MySQL 8.0.16+ is required (current: 8.0.15)", "the check constraint should not be supported")
+}
+
+// clean the test data
+func TestConstraintClean(t *testing.T) {
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_constraint")
+}
+
+func NewTableForConstraintTest() {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_constraint")
+	builder.MustCreateTable("table_test_constraint", func(table Blueprint) {
+		table.ID("id")
+		table.String("name", 80)
+		table.String("email", 80)
+		table.Integer("vote")
+		table.AddUniqueConstraint("name_email", "name", "email")
+		table.AddCheck("vote_range", "vote >= 0")
+	})
+}
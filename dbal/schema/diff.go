@@ -0,0 +1,344 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+)
+
+// defaultQuoted the quoted string literal of the default value. eg: 'draft', 'draft'::character varying
+var defaultQuoted = regexp.MustCompile(`^'((?:[^']|'')*)'(::[\w\s]+)?$`)
+
+// defaultKeyword the keywords of the default value. eg: CURRENT_TIMESTAMP
+var defaultKeyword = regexp.MustCompile(`(?i)^(CURRENT_TIMESTAMP|CURRENT_DATE|CURRENT_TIME|LOCALTIMESTAMP|LOCALTIME)$`)
+
+// defaultCurrentTimestamp the current timestamp expressions of the default value. eg: NOW(), CURRENT_TIMESTAMP(6), (datetime('now','localtime'))
+var defaultCurrentTimestamp = regexp.MustCompile(`(?i)^\(?(NOW\(\)|CURRENT_TIMESTAMP(\(\d*\))?|LOCALTIMESTAMP(\(\d*\))?|datetime\('now',\s*'localtime'\))\)?$`)
+
+// commentType the type prefix of the column comment. eg: T:uuid|the comment
+var commentType = regexp.MustCompile(`^T:[a-zA-Z]+\|?`)
+
+// storedTypes the types the database stores when the type is not supported natively ( driver -> desired -> current ).
+// eg: SQLite stores the uuid column as VARCHAR(36), reported as string.
+// MySQL and PostgreSQL keep the type of the mapping types in the column comment, it is restored when the column is read.
+var storedTypes = map[string]map[string]string{
+	"sqlite3": {
+		"uuid":        "string",
+		"json":        "text",
+		"jsonb":       "text",
+		"ipAddress":   "integer",
+		"macAddress":  "bigInteger",
+		"year":        "smallInteger",
+		"dateTimeTz":  "dateTime",
+		"timestampTz": "timestamp",
+		"timeTz":      "time",
+	},
+	"mysql": {
+		"dateTimeTz":  "dateTime",
+		"timestampTz": "timestamp",
+		"timeTz":      "time",
+	},
+}
+
+// Diff Compute the commands that alter the current table to match the desired table.
+// The columns, indexes and primary key are compared, a column is renamed when the RenameFrom hint of the desired column is set.
+// The commands are ordered as DropIndex, DropPrimary, RenameColumn, DropColumn, AddColumn, ChangeColumn, CreatePrimary, CreateIndex.
+// The driver of the database is used to ignore the differences the database makes (see storedTypes), the columns are compared strictly if it is not given.
+//
+//	commands := schema.Diff(current.Get().Table, desired.Get().Table, "sqlite3")
+func Diff(current *dbal.Table, desired *dbal.Table, driver ...string) []*dbal.Command {
+	dbalTable := *current
+	dbalTable.Commands = []*dbal.Command{}
+	table := &Table{
+		Table:         &dbalTable,
+		ColumnMap:     map[string]*Column{},
+		IndexMap:      map[string]*Index{},
+		ForeignMap:    map[string]*Foreign{},
+		ConstraintMap: map[string]*Constraint{},
+	}
+	if len(driver) > 0 {
+		table.diff(desired, driver[0])
+	} else {
+		table.diff(desired, "")
+	}
+	return table.Table.Commands
+}
+
+// ApplyDiff Indicate that the table should be altered to match the desired table, return the commands added.
+//
+//	builder.AlterTable("users", func(table schema.Blueprint) {
+//		table.ApplyDiff(desired.Get().Table)
+//	})
+func (table *Table) ApplyDiff(desired *dbal.Table) []*dbal.Command {
+	offset := len(table.Table.Commands)
+	driver := ""
+	if table.Builder != nil && table.Builder.Conn != nil && table.Builder.Conn.WriteConfig != nil {
+		driver = table.Builder.Conn.WriteConfig.Driver
+	}
+	table.diff(desired, driver)
+	return table.Table.Commands[offset:]
+}
+
+// diff add the commands that alter the current table to match the desired table
+func (table *Table) diff(desired *dbal.Table, driver string) {
+	current := table.Table
+
+	// the renamed columns ( old name -> new name )
+	renames := map[string]string{}
+	for _, column := range desired.Columns {
+		if column.RenameFrom == "" || column.RenameFrom == column.Name ||
+			!current.HasColumn(column.RenameFrom) || current.HasColumn(column.Name) ||
+			desired.HasColumn(column.RenameFrom) {
+			continue
+		}
+		renames[column.RenameFrom] = column.Name
+	}
+
+	// the column name after renaming
+	nameOf := func(column *dbal.Column) string {
+		if name, has := renames[column.Name]; has {
+			return name
+		}
+		return column.Name
+	}
+
+	// DropIndex
+	for _, index := range current.Indexes {
+		if index.Type == "primary" || !current.HasIndex(index.Name) {
+			continue
+		}
+		if desired.HasIndex(index.Name) && !diffIndexChanged(index, desired.GetIndex(index.Name), nameOf) {
+			continue
+		}
+		name := index.Name
+		table.dropIndexCommand(name, func() {
+			delete(table.IndexMap, name)
+		}, nil)
+	}
+
+	// DropPrimary
+	primaryChanged := diffPrimaryChanged(current.Primary, desired.Primary, nameOf)
+	if current.Primary != nil && primaryChanged {
+		table.dropPrimaryCommand(&Primary{Primary: current.Primary, Table: table}, func() {
+			table.Primary = nil
+		}, nil)
+	}
+
+	// RenameColumn
+	for _, column := range current.Columns {
+		old, new := column.Name, nameOf(column)
+		if old == new {
+			continue
+		}
+		table.renameColumnCommand(old, new, func() {
+			table.renameColumnMap(old, new)
+		}, nil)
+	}
+
+	// DropColumn
+	for _, column := range current.Columns {
+		if desired.HasColumn(nameOf(column)) {
+			continue
+		}
+		name := column.Name
+		table.dropColumnCommand(name, func() {
+			delete(table.ColumnMap, name)
+		}, nil)
+	}
+
+	// AddColumn & ChangeColumn
+	columns := map[string]*dbal.Column{}
+	for _, column := range current.Columns {
+		columns[nameOf(column)] = column
+	}
+	changed := []*dbal.Column{}
+	for _, want := range desired.Columns {
+		column := *want
+		column.Table = current
+		column.TableName = current.TableName
+		column.Indexes = []*dbal.Index{}
+		if _, has := columns[want.Name]; !has {
+			table.addColumnCommand(&column, nil, nil)
+			continue
+		}
+		if diffColumnChanged(columns[want.Name], want, driver) {
+			changed = append(changed, &column)
+		}
+	}
+	for _, column := range changed {
+		table.changeColumnCommand(column, nil, nil)
+	}
+
+	// CreatePrimary
+	if desired.Primary != nil && primaryChanged {
+		primary := current.NewPrimary(desired.Primary.Name, desired.Primary.Columns...)
+		table.createPrimaryCommand(primary, nil, nil)
+	}
+
+	// CreateIndex
+	for _, want := range desired.Indexes {
+		if want.Type == "primary" || !desired.HasIndex(want.Name) {
+			continue
+		}
+		if current.HasIndex(want.Name) && !diffIndexChanged(current.GetIndex(want.Name), want, nameOf) {
+			continue
+		}
+		index := *want
+		index.Table = current
+		index.TableName = current.TableName
+		table.createIndexCommand(&index, nil, nil)
+	}
+}
+
+// renameColumnMap rename the column of the blueprint column map
+func (table *Table) renameColumnMap(old string, new string) {
+	column, has := table.ColumnMap[old]
+	if !has {
+		return
+	}
+	delete(table.ColumnMap, old)
+	column.Name = new
+	table.ColumnMap[new] = column
+}
+
+// diffIndexChanged Determine if the index type or columns have been changed
+func diffIndexChanged(current *dbal.Index, desired *dbal.Index, nameOf func(column *dbal.Column) string) bool {
+	if current.Type != desired.Type || len(current.Columns) != len(desired.Columns) {
+		return true
+	}
+	for i, column := range current.Columns {
+		if nameOf(column) != desired.Columns[i].Name {
+			return true
+		}
+	}
+	return false
+}
+
+// diffPrimaryChanged Determine if the primary key has been added, dropped or its columns have been changed
+func diffPrimaryChanged(current *dbal.Primary, desired *dbal.Primary, nameOf func(column *dbal.Column) string) bool {
+	if current == nil || desired == nil {
+		return current != desired
+	}
+	if len(current.Columns) != len(desired.Columns) {
+		return true
+	}
+	for i, column := range current.Columns {
+		if nameOf(column) != desired.Columns[i].Name {
+			return true
+		}
+	}
+	return false
+}
+
+// diffColumnChanged Determine if the column definition has been changed.
+// The length, precision and scale are compared only if they are set in both columns,
+// the default value, comment and options are compared only if they are set in the desired column.
+// The type of the auto-increment primary key and the nullable of the primary key are not compared, SQLite reports it as a nullable INTEGER.
+// On SQLite, a nullable column is not changed to not null if the desired column has no default value, SQLite creates it as nullable.
+// The type and unsigned are unchanged if the database of the driver stores the desired type as the current type (see storedTypes),
+// the current timestamp defaults are compared as CURRENT_TIMESTAMP, and the comment is not compared if the database does not report it.
+func diffColumnChanged(current *dbal.Column, desired *dbal.Column, driver string) bool {
+	autoIncrement := current.Primary && desired.Extra != nil && *desired.Extra == "AutoIncrement"
+	stored := current.Type != desired.Type && storedTypes[driver][desired.Type] == current.Type
+	if current.Type != desired.Type && !stored && !autoIncrement {
+		return true
+	}
+
+	// SQLite creates the not null column without default value as a nullable column
+	nullable := driver == "sqlite3" && current.Nullable && desired.Default == nil && desired.DefaultRaw == ""
+	if current.Nullable != desired.Nullable && !current.Primary && !nullable {
+		return true
+	}
+
+	if current.IsUnsigned != desired.IsUnsigned && !stored {
+		return true
+	}
+
+	for _, pair := range [][2]*int{
+		{current.Length, desired.Length},
+		{current.Precision, desired.Precision},
+		{current.Scale, desired.Scale},
+		{current.DateTimePrecision, desired.DateTimePrecision},
+	} {
+		if pair[0] != nil && pair[1] != nil && *pair[0] != *pair[1] {
+			return true
+		}
+	}
+
+	defaultValue, defaultRaw := splitDefaultValue(current.Default)
+	if current.DefaultRaw != "" {
+		defaultRaw = current.DefaultRaw
+	}
+
+	if desired.Default != nil && fmt.Sprintf("%v", defaultValue) != fmt.Sprintf("%v", desired.Default) {
+		return true
+	}
+
+	if desired.DefaultRaw != "" && diffDefaultRaw(defaultRaw) != diffDefaultRaw(desired.DefaultRaw) {
+		return true
+	}
+
+	if desired.Comment != nil && current.Comment != nil && diffComment(*current.Comment) != *desired.Comment {
+		return true
+	}
+
+	if len(desired.Option) > 0 && !reflect.DeepEqual(current.Option, desired.Option) {
+		return true
+	}
+
+	return false
+}
+
+// diffDefaultRaw normalize the current timestamp expression of the default value to CURRENT_TIMESTAMP
+//
+//	NOW() -> CURRENT_TIMESTAMP
+//	(datetime('now','localtime')) -> CURRENT_TIMESTAMP
+func diffDefaultRaw(raw string) string {
+	if defaultCurrentTimestamp.MatchString(raw) {
+		return "CURRENT_TIMESTAMP"
+	}
+	return raw
+}
+
+// diffComment remove the type prefix of the column comment
+//
+//	T:uuid|the comment -> the comment
+func diffComment(comment string) string {
+	return commentType.ReplaceAllString(comment, "")
+}
+
+// splitDefaultValue split the default value reported by the database into the value and the raw expression
+//
+//	'draft'::character varying -> draft
+//	datetime('now','localtime') -> (datetime('now','localtime'))
+//	CURRENT_TIMESTAMP -> CURRENT_TIMESTAMP
+func splitDefaultValue(value interface{}) (interface{}, string) {
+	text, ok := value.(string)
+	if !ok {
+		return value, ""
+	}
+
+	if matches := defaultQuoted.FindStringSubmatch(text); matches != nil {
+		return strings.ReplaceAll(matches[1], "''", "'"), ""
+	}
+
+	if strings.ToUpper(text) == "NULL" {
+		return nil, ""
+	}
+
+	if defaultKeyword.MatchString(text) {
+		return nil, text
+	}
+
+	if strings.Contains(text, "(") {
+		if !strings.HasPrefix(text, "(") || !strings.HasSuffix(text, ")") {
+			text = fmt.Sprintf("(%s)", text)
+		}
+		return nil, text
+	}
+
+	return text, ""
+}
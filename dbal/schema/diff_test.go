@@ -0,0 +1,224 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestDiffNothingChanged(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForDiffTest()
+
+	current := builder.MustGetTable("table_test_diff")
+	commands := Diff(current.Get().Table, NewDesiredTableForDiffTest().Table, unit.Driver())
+	assert.Equal(t, []string{}, diffCommandNames(commands), "there should be no commands")
+}
+
+func TestDiffCommands(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForDiffTest()
+
+	desired := NewTable("table_test_diff", builder.Builder())
+	desired.ID("id")
+	desired.String("title", 80).Null().Index().RenameFrom = "name"
+	desired.String("email", 200).Null().Index()
+	desired.Integer("score").Null()
+
+	current := builder.MustGetTable("table_test_diff")
+	commands := Diff(current.Get().Table, desired.Table, unit.Driver())
+	assert.Equal(t, []string{
+		"DropIndex", "DropIndex",
+		"RenameColumn", "DropColumn",
+		"AddColumn", "ChangeColumn",
+		"CreateIndex", "CreateIndex",
+	}, diffCommandNames(commands), "the commands should be ordered")
+	if len(commands) != 8 {
+		return
+	}
+	assert.ElementsMatch(t, []interface{}{"name_index", "email_unique"}, []interface{}{commands[0].Params[0], commands[1].Params[0]}, "the name_index and email_unique indexes should be dropped")
+	assert.Equal(t, []interface{}{"name", "title"}, commands[2].Params, "the name column should be renamed to title")
+	assert.Equal(t, "vote", commands[3].Params[0], "the vote column should be dropped")
+	assert.Equal(t, "score", commands[4].Params[0].(*dbal.Column).Name, "the score column should be added")
+	assert.Equal(t, "email", commands[5].Params[0].(*dbal.Column).Name, "the email column should be changed")
+	assert.Equal(t, "title_index", commands[6].Params[0].(*dbal.Index).Name, "the title_index index should be created")
+	assert.Equal(t, "email_index", commands[7].Params[0].(*dbal.Index).Name, "the email_index index should be created")
+	assert.Equal(t, 0, len(current.Get().Table.Commands), "the current table should not be changed")
+}
+
+func TestDiffPrimary(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilderInstance()
+	current := NewTable("table_test_diff", builder)
+	current.Integer("id")
+	current.Integer("vote")
+	current.AddPrimary("id")
+
+	desired := NewTable("table_test_diff", builder)
+	desired.Integer("id")
+	desired.Integer("vote")
+	desired.AddPrimary("id", "vote")
+
+	commands := Diff(current.Table, desired.Table, unit.Driver())
+	assert.Equal(t, []string{"DropPrimary", "CreatePrimary"}, diffCommandNames(commands), "the primary key should be recreated")
+	if len(commands) == 2 {
+		assert.Equal(t, 2, len(commands[1].Params[0].(*dbal.Primary).Columns), "the primary key should have 2 columns")
+	}
+}
+
+func TestDiffDefault(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_diff")
+	define := func(table Blueprint) {
+		table.ID("id")
+		table.String("status", 20).SetDefault("draft")
+		table.String("title", 80).SetDefault("it's")
+		table.Integer("vote").SetDefault(0)
+		table.Timestamp("created_at").SetDefaultRaw("CURRENT_TIMESTAMP")
+	}
+	builder.MustCreateTable("table_test_diff", define)
+
+	desired := NewTable("table_test_diff", builder.Builder())
+	define(desired)
+	current := builder.MustGetTable("table_test_diff")
+	commands := Diff(current.Get().Table, desired.Table, unit.Driver())
+	assert.Equal(t, []string{}, diffCommandNames(commands), "there should be no commands")
+
+	desired = NewTable("table_test_diff", builder.Builder())
+	desired.ID("id")
+	desired.String("status", 20).SetDefault("published")
+	desired.String("title", 80).SetDefault("it's")
+	desired.Integer("vote").SetDefault(0)
+	desired.Timestamp("created_at").SetDefaultRaw("CURRENT_TIMESTAMP")
+	commands = Diff(current.Get().Table, desired.Table, unit.Driver())
+	assert.Equal(t, []string{"ChangeColumn"}, diffCommandNames(commands), "the status column should be changed")
+	if len(commands) == 1 {
+		assert.Equal(t, "status", commands[0].Params[0].(*dbal.Column).Name, "the status column should be changed")
+	}
+}
+
+func TestDiffApplyDiff(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForDiffTest()
+	builder.MustGetDB().MustExec("INSERT INTO table_test_diff (name, email, vote) VALUES ('Max', 'max@example.com', 1)")
+
+	desired := NewTable("table_test_diff", builder.Builder())
+	desired.ID("id")
+	desired.String("nickname", 80).Null().RenameFrom = "name"
+	desired.String("email", 100).Null().Unique()
+	desired.Integer("vote").Null()
+	desired.String("title", 80).Null().Index()
+
+	builder.MustAlterTable("table_test_diff", func(table Blueprint) {
+		commands := table.ApplyDiff(desired.Table)
+		if unit.DriverIs("sqlite3") {
+			assert.Equal(t, []string{"DropIndex", "RenameColumn", "AddColumn", "CreateIndex"}, diffCommandNames(commands), "the commands should be ordered")
+		}
+	})
+
+	table := builder.MustGetTable("table_test_diff")
+	assert.True(t, table.HasColumn("nickname", "title"), "the table should have the nickname and title columns")
+	assert.False(t, table.HasColumn("name"), "the name column should be renamed")
+	assert.True(t, table.HasIndex("title_index", "email_unique"), "the table should have the title_index and email_unique indexes")
+	assert.False(t, table.HasIndex("name_index"), "the name_index index should be dropped")
+
+	nickname := ""
+	err := builder.MustGetDB().Get(&nickname, "SELECT nickname FROM table_test_diff")
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.Equal(t, "Max", nickname, "the data of the renamed column should be kept")
+
+	// the table matches the desired table
+	commands := Diff(table.Get().Table, desired.Table, unit.Driver())
+	assert.Equal(t, []string{}, diffCommandNames(commands), "there should be no commands")
+}
+
+func TestDiffTypes(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_diff")
+	define := func(table Blueprint) {
+		table.ID("id")
+		table.UUID("uuid").Null()
+		table.JSON("extra").Null()
+		table.JSONB("options").Null()
+		table.IPAddress("ip").Null()
+		table.MACAddress("mac").Null()
+		table.Year("year").Null()
+		table.DateTimeTz("published_at").Null()
+		table.TimestampTz("updated_at").Null()
+		table.String("email", 100).Null().SetComment("the email")
+		table.Timestamp("created_at").SetDefaultRaw("NOW()")
+	}
+	builder.MustCreateTable("table_test_diff", define)
+
+	desired := NewTable("table_test_diff", builder.Builder())
+	define(desired)
+	current := builder.MustGetTable("table_test_diff")
+	commands := Diff(current.Get().Table, desired.Table, unit.Driver())
+	assert.Equal(t, []string{}, diffCommandNames(commands), "there should be no commands")
+}
+
+func TestDiffColumnChangedDriver(t *testing.T) {
+	// SQLite creates the not null column without default value as a nullable column
+	current := &dbal.Column{Name: "email", Type: "string", Nullable: true}
+	desired := &dbal.Column{Name: "email", Type: "string", Nullable: false}
+	assert.False(t, diffColumnChanged(current, desired, "sqlite3"), "the nullable column should not be changed on SQLite")
+	assert.True(t, diffColumnChanged(current, desired, "mysql"), "the nullable column should be changed on MySQL")
+	assert.True(t, diffColumnChanged(current, desired, "postgres"), "the nullable column should be changed on PostgreSQL")
+
+	// SQLite stores the uuid column as string
+	current = &dbal.Column{Name: "uuid", Type: "string", Nullable: true}
+	desired = &dbal.Column{Name: "uuid", Type: "uuid", Nullable: true}
+	assert.False(t, diffColumnChanged(current, desired, "sqlite3"), "the uuid column should not be changed on SQLite")
+	assert.True(t, diffColumnChanged(current, desired, "mysql"), "the uuid column should be changed on MySQL")
+	assert.True(t, diffColumnChanged(current, desired, "postgres"), "the uuid column should be changed on PostgreSQL")
+
+	// MySQL stores the dateTimeTz column as dateTime
+	current = &dbal.Column{Name: "published_at", Type: "dateTime", Nullable: true}
+	desired = &dbal.Column{Name: "published_at", Type: "dateTimeTz", Nullable: true}
+	assert.False(t, diffColumnChanged(current, desired, "mysql"), "the dateTimeTz column should not be changed on MySQL")
+	assert.True(t, diffColumnChanged(current, desired, "postgres"), "the dateTimeTz column should be changed on PostgreSQL")
+	assert.True(t, diffColumnChanged(current, desired, ""), "the columns should be compared strictly without the driver")
+}
+
+// clean the test data
+func TestDiffClean(t *testing.T) {
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_diff")
+}
+
+func diffCommandNames(commands []*dbal.Command) []string {
+	names := []string{}
+	for _, command := range commands {
+		names = append(names, command.Name)
+	}
+	return names
+}
+
+func NewDesiredTableForDiffTest() *Table {
+	builder := getTestBuilder()
+	table := NewTable("table_test_diff", builder.Builder())
+	table.ID("id")
+	table.String("name", 80).Null().Index()
+	table.String("email", 100).Null().Unique()
+	table.Integer("vote").Null()
+	return table
+}
+
+func NewTableForDiffTest() {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_diff")
+	builder.MustCreateTable("table_test_diff", func(table Blueprint) {
+		table.ID("id")
+		table.String("name", 80).Null().Index()
+		table.String("email", 100).Null().Unique()
+		table.Integer("vote").Null()
+	})
+}
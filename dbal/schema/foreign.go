@@ -0,0 +1,162 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+)
+
+// ForeignActions the referential actions of the foreign keys
+var ForeignActions = map[string]bool{
+	"CASCADE":     true,
+	"SET NULL":    true,
+	"SET DEFAULT": true,
+	"RESTRICT":    true,
+	"NO ACTION":   true,
+}
+
+// GetForeign get the foreign key instance for the given name, if the foreign key does not exist return nil.
+func (table *Table) GetForeign(name string) *Foreign {
+	return table.ForeignMap[name]
+}
+
+// GetForeignKeys Get the foreign keys map of the table
+func (table *Table) GetForeignKeys() map[string]*Foreign {
+	return table.ForeignMap
+}
+
+// HasForeign Determine if the table has the given foreign keys.
+func (table *Table) HasForeign(name ...string) bool {
+	has := true
+	for _, n := range name {
+		_, has = table.ForeignMap[n]
+		if !has {
+			return has
+		}
+	}
+	return has
+}
+
+// Foreign Indicate that the given columns should reference the columns of another table.
+// The foreign key name is "{table}_{columns}_foreign" by default, and the referenced column is "id" by default.
+//
+//	table.Foreign("user_id").References("id").On("users").OnDelete("cascade")
+func (table *Table) Foreign(columnNames ...string) *Foreign {
+	name := fmt.Sprintf("%s_%s_foreign", table.GetFullName(), strings.Join(columnNames, "_"))
+	foreign := &Foreign{
+		Foreign: table.Table.NewForeign(name, columnNames...),
+		Table:   table,
+	}
+	foreign.ReferencedColumns = []string{"id"}
+	table.pushForeign(foreign)
+	table.createForeignCommand(foreign.Foreign, nil, func() {
+		delete(table.ForeignMap, foreign.Name)
+	})
+	return foreign
+}
+
+// ConstrainedForeignID Create a new unsigned big integer (8-byte) column and the foreign key references it to another table.
+// The referenced column is "id" by default.
+//
+//	table.ConstrainedForeignID("user_id", "users")
+//	table.ConstrainedForeignID("author_id", "users", "id")
+func (table *Table) ConstrainedForeignID(name string, referencedTable string, referencedColumn ...string) *Foreign {
+	column := "id"
+	if len(referencedColumn) > 0 {
+		column = referencedColumn[0]
+	}
+	table.ForeignID(name)
+	return table.Foreign(name).References(column).On(referencedTable)
+}
+
+// DropForeign Indicate that the given foreign keys should be dropped.
+func (table *Table) DropForeign(name ...string) {
+	for _, n := range name {
+		table.dropForeignCommand(n, func() {
+			delete(table.ForeignMap, n)
+		}, nil)
+	}
+}
+
+// SetName set the name of the foreign key
+func (foreign *Foreign) SetName(name string) *Foreign {
+	delete(foreign.Table.ForeignMap, foreign.Name)
+	delete(foreign.Table.Table.ForeignMap, foreign.Name)
+	foreign.Name = name
+	foreign.Table.ForeignMap[name] = foreign
+	foreign.Table.Table.ForeignMap[name] = foreign.Foreign
+	return foreign
+}
+
+// References set the referenced columns of the foreign key
+func (foreign *Foreign) References(columnNames ...string) *Foreign {
+	foreign.ReferencedColumns = columnNames
+	return foreign
+}
+
+// On set the referenced table of the foreign key ( without prefix )
+func (foreign *Foreign) On(name string) *Foreign {
+	foreign.ReferencedTableName = fmt.Sprintf("%s%s", foreign.Table.Prefix, name)
+	return foreign
+}
+
+// OnDelete set the referential action on delete. cascade, set null, set default, restrict, no action
+func (foreign *Foreign) OnDelete(action string) *Foreign {
+	foreign.Foreign.OnDelete = foreignAction(action)
+	return foreign
+}
+
+// OnUpdate set the referential action on update. cascade, set null, set default, restrict, no action
+func (foreign *Foreign) OnUpdate(action string) *Foreign {
+	foreign.Foreign.OnUpdate = foreignAction(action)
+	return foreign
+}
+
+// CascadeOnDelete Indicate that deletes should cascade.
+func (foreign *Foreign) CascadeOnDelete() *Foreign {
+	return foreign.OnDelete("cascade")
+}
+
+// NullOnDelete Indicate that deletes should set the foreign key value to null.
+func (foreign *Foreign) NullOnDelete() *Foreign {
+	return foreign.OnDelete("set null")
+}
+
+// CascadeOnUpdate Indicate that updates should cascade.
+func (foreign *Foreign) CascadeOnUpdate() *Foreign {
+	return foreign.OnUpdate("cascade")
+}
+
+// pushForeign add a foreign key to the table
+func (table *Table) pushForeign(foreign *Foreign) *Table {
+	table.Table.PushForeign(foreign.Foreign)
+	table.ForeignMap[foreign.Name] = foreign
+	return table
+}
+
+// foreignAction normalize the referential action, the action is checked before the table is created or altered
+func foreignAction(action string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(action), " "))
+}
+
+// checkForeigns check the foreign keys to create, the referenced table is required and the actions should be supported
+func checkForeigns(table *dbal.Table) error {
+	for _, command := range table.Commands {
+		if command.Name != "CreateForeign" {
+			continue
+		}
+
+		foreign := command.Params[0].(*dbal.Foreign)
+		if foreign.ReferencedTableName == "" {
+			return fmt.Errorf("the referenced table of the foreign key %s is required", foreign.Name)
+		}
+
+		for _, action := range []string{foreign.OnDelete, foreign.OnUpdate} {
+			if _, has := ForeignActions[action]; action != "" && !has {
+				return fmt.Errorf("the action %q of the foreign key %s is not supported", action, foreign.Name)
+			}
+		}
+	}
+	return nil
+}
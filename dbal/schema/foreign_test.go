@@ -0,0 +1,211 @@
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestForeignCreateTable(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForForeignTest()
+
+	table := builder.MustGetTable("table_test_foreign_posts")
+	assert.True(t, table.HasForeign("table_test_foreign_posts_user_id_foreign"), "the table should have the table_test_foreign_posts_user_id_foreign foreign key")
+	if !table.HasForeign("table_test_foreign_posts_user_id_foreign") {
+		return
+	}
+
+	foreign := table.GetForeign("table_test_foreign_posts_user_id_foreign")
+	assert.Equal(t, []string{"user_id"}, foreign.Columns, "the columns of the foreign key should be user_id")
+	assert.Equal(t, "table_test_foreign_users", foreign.ReferencedTableName, "the referenced table should be table_test_foreign_users")
+	assert.Equal(t, []string{"id"}, foreign.ReferencedColumns, "the referenced columns of the foreign key should be id")
+	assert.Equal(t, "CASCADE", foreign.Foreign.OnDelete, "the on delete action should be CASCADE")
+	assert.Equal(t, "RESTRICT", foreign.Foreign.OnUpdate, "the on update action should be RESTRICT")
+	assert.Equal(t, 1, len(table.GetForeignKeys()), "the table should have 1 foreign key")
+}
+
+func TestForeignConstrainedForeignID(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForForeignTest()
+
+	builder.MustCreateTable("table_test_foreign_comments", func(table Blueprint) {
+		table.ID("id")
+		table.ConstrainedForeignID("author_id", "table_test_foreign_users")
+		table.ConstrainedForeignID("post_id", "table_test_foreign_posts", "id").CascadeOnDelete()
+	})
+
+	table := builder.MustGetTable("table_test_foreign_comments")
+	assert.True(t, table.HasColumn("author_id", "post_id"), "the table should have the author_id and post_id columns")
+	assert.True(t, table.HasForeign("table_test_foreign_comments_author_id_foreign", "table_test_foreign_comments_post_id_foreign"), "the table should have the foreign keys")
+	if table.HasForeign("table_test_foreign_comments_post_id_foreign") {
+		foreign := table.GetForeign("table_test_foreign_comments_post_id_foreign")
+		assert.Equal(t, "table_test_foreign_posts", foreign.ReferencedTableName, "the referenced table should be table_test_foreign_posts")
+		assert.Equal(t, "CASCADE", foreign.Foreign.OnDelete, "the on delete action should be CASCADE")
+	}
+}
+
+func TestForeignAlterTable(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForForeignTest()
+
+	db, err := builder.GetDB()
+	assert.Equal(t, nil, err, "the return error should be nil")
+	if err != nil {
+		return
+	}
+	db.MustExec("INSERT INTO table_test_foreign_users (name) VALUES ('Max')")
+	db.MustExec("INSERT INTO table_test_foreign_posts (user_id, editor_id, title) VALUES (1, 1, 'Hello')")
+
+	// CreateForeign
+	builder.MustAlterTable("table_test_foreign_posts", func(table Blueprint) {
+		table.Foreign("editor_id").On("table_test_foreign_users").SetName("posts_editor_foreign")
+	})
+	table := builder.MustGetTable("table_test_foreign_posts")
+	assert.True(t, table.HasForeign("posts_editor_foreign", "table_test_foreign_posts_user_id_foreign"), "the table should have the foreign keys")
+	assert.True(t, table.HasIndex("title_index"), "the table should have the title_index index")
+
+	count := 0
+	err = db.Get(&count, "SELECT COUNT(*) FROM table_test_foreign_posts")
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.Equal(t, 1, count, "the rows of the table should be kept")
+
+	// DropForeign
+	builder.MustAlterTable("table_test_foreign_posts", func(table Blueprint) {
+		table.DropForeign("posts_editor_foreign")
+	})
+	table = builder.MustGetTable("table_test_foreign_posts")
+	assert.False(t, table.HasForeign("posts_editor_foreign"), "the table should not have the posts_editor_foreign foreign key")
+	assert.True(t, table.HasForeign("table_test_foreign_posts_user_id_foreign"), "the table should have the table_test_foreign_posts_user_id_foreign foreign key")
+
+	// DropForeign Fail
+	err = builder.AlterTable("table_test_foreign_posts", func(table Blueprint) {
+		table.DropForeign("posts_editor_foreign")
+	})
+	assert.False(t, err == nil, "The return error should not be nil")
+}
+
+func TestForeignActionFail(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForForeignTest()
+
+	err := builder.AlterTable("table_test_foreign_posts", func(table Blueprint) {
+		table.Foreign("editor_id").On("table_test_foreign_users").OnDelete("drop")
+	})
+	assert.Contains(t, err.Error(), `the action "DROP" of the foreign key`, "the unsupported action should return an error")
+
+	err = builder.AlterTable("table_test_foreign_posts", func(table Blueprint) {
+		table.Foreign("editor_id").References("id")
+	})
+	assert.Contains(t, err.Error(), "the referenced table of the foreign key", "the referenced table should be required")
+
+	table := builder.MustGetTable("table_test_foreign_posts")
+	assert.False(t, table.HasForeign("table_test_foreign_posts_editor_id_foreign"), "the foreign key should not be created")
+}
+
+func TestForeignDropUnnamed(t *testing.T) {
+	defer unit.Catch()
+	if !unit.DriverIs("sqlite3") {
+		return
+	}
+
+	builder := getTestBuilder()
+	NewTableForForeignTest()
+	db := builder.MustGetDB()
+	db.MustExec("DROP TABLE IF EXISTS table_test_foreign_comments")
+	db.MustExec(
+		"CREATE TABLE table_test_foreign_comments (id INTEGER PRIMARY KEY, post_id INTEGER, user_id INTEGER, " +
+			"FOREIGN KEY (post_id) REFERENCES table_test_foreign_posts (id) ON DELETE CASCADE, " +
+			"FOREIGN KEY (user_id) REFERENCES table_test_foreign_users (id))",
+	)
+
+	// the unnamed foreign keys are named by the columns
+	table := builder.MustGetTable("table_test_foreign_comments")
+	assert.True(t, table.HasForeign("table_test_foreign_comments_post_id_foreign", "table_test_foreign_comments_user_id_foreign"), "the table should have the unnamed foreign keys")
+
+	builder.MustAlterTable("table_test_foreign_comments", func(table Blueprint) {
+		table.DropForeign("table_test_foreign_comments_post_id_foreign")
+	})
+	table = builder.MustGetTable("table_test_foreign_comments")
+	assert.False(t, table.HasForeign("table_test_foreign_comments_post_id_foreign"), "the post_id foreign key should be dropped")
+	assert.True(t, table.HasForeign("table_test_foreign_comments_user_id_foreign"), "the user_id foreign key should be kept")
+}
+
+func TestForeignRebuildWithinTransaction(t *testing.T) {
+	defer unit.Catch()
+	if !unit.DriverIs("sqlite3") {
+		return
+	}
+
+	builder := getTestBuilder()
+	NewTableForForeignTest()
+	db := builder.MustGetDB()
+	db.MustExec("INSERT INTO table_test_foreign_users (name) VALUES ('Max')")
+	db.MustExec("INSERT INTO table_test_foreign_posts (user_id, editor_id, title) VALUES (1, 1, 'Hello')")
+
+	// the foreign keys setting is per connection, and it can't be changed within a transaction.
+	ctx := context.Background()
+	conn, err := db.Connx(ctx)
+	assert.Equal(t, nil, err, "the return error should be nil")
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
+	_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	assert.Equal(t, nil, err, "the return error should be nil")
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	assert.Equal(t, nil, err, "the return error should be nil")
+	if err != nil {
+		return
+	}
+
+	// rebuilding the parent table drops it, the posts would be deleted by ON DELETE CASCADE.
+	err = builder.WithTransaction(tx).AlterTable("table_test_foreign_users", func(table Blueprint) {
+		table.AddCheck("name_length", "length(name) > 0")
+	})
+	assert.Contains(t, err.Error(), "can't be rebuilt within a transaction while the foreign keys are enabled", "the rebuilding should be refused")
+	assert.Nil(t, tx.Rollback(), "the transaction should be rolled back")
+
+	count := 0
+	err = db.Get(&count, "SELECT COUNT(*) FROM table_test_foreign_posts")
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.Equal(t, 1, count, "the rows of the child table should be kept")
+
+	table := builder.MustGetTable("table_test_foreign_users")
+	assert.Nil(t, table.GetCheck("name_length"), "the check constraint should not be created")
+}
+
+// clean the test data
+func TestForeignClean(t *testing.T) {
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_foreign_comments")
+	builder.DropTableIfExists("table_test_foreign_posts")
+	builder.DropTableIfExists("table_test_foreign_users")
+}
+
+func NewTableForForeignTest() {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_foreign_comments")
+	builder.DropTableIfExists("table_test_foreign_posts")
+	builder.DropTableIfExists("table_test_foreign_users")
+	builder.MustCreateTable("table_test_foreign_users", func(table Blueprint) {
+		table.ID("id")
+		table.String("name", 80)
+	})
+	builder.MustCreateTable("table_test_foreign_posts", func(table Blueprint) {
+		table.ID("id")
+		table.ForeignID("user_id")
+		table.ForeignID("editor_id")
+		table.String("title", 80).Index()
+		table.Foreign("user_id").References("id").On("table_test_foreign_users").OnDelete("cascade").OnUpdate("restrict")
+	})
+}
@@ -1,6 +1,8 @@
 package schema
 
 import (
+	"context"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun/dbal"
 )
@@ -8,6 +10,8 @@ import (
 // Schema The schema interface
 type Schema interface {
 	SetOption(option *dbal.Option)
+	WithContext(ctx context.Context) Schema
+	WithTransaction(tx *sqlx.Tx) Schema
 
 	Builder() *Builder
 	GetConnection() (*dbal.Connection, error)
@@ -23,6 +27,8 @@ type Schema interface {
 	HasTable(name string) (bool, error)
 	RenameTable(old string, new string) error
 	DropTableIfExists(name string) error
+	CreateTableFromJSON(data []byte) error
+	ApplyJSON(data []byte) error
 
 	MustGetConnection() *dbal.Connection
 	MustGetDB() *sqlx.DB
@@ -37,6 +43,8 @@ type Schema interface {
 	MustHasTable(name string) bool
 	MustRenameTable(old string, new string) Blueprint
 	MustDropTableIfExists(name string)
+	MustCreateTableFromJSON(data []byte)
+	MustApplyJSON(data []byte)
 
 	DB() *sqlx.DB // alias MustGetDB
 }
@@ -74,8 +82,30 @@ type Blueprint interface {
 	RenameIndex(old string, new string) *Index
 	DropIndex(name ...string)
 
+	// defined in foreign.go
+	GetForeign(name string) *Foreign
+	GetForeignKeys() map[string]*Foreign
+	HasForeign(name ...string) bool
+	Foreign(columnNames ...string) *Foreign
+	ConstrainedForeignID(name string, referencedTable string, referencedColumn ...string) *Foreign
+	DropForeign(name ...string)
+
 	// defined in constraint.go
-	// @todo: GetUniqueConstraint, AddUniqueConstraint, DropUniqueConstraint
+	GetConstraint(name string) *Constraint
+	GetConstraints() map[string]*Constraint
+	HasConstraint(name ...string) bool
+	GetUniqueConstraint(name string) *Constraint
+	AddUniqueConstraint(name string, columnNames ...string) *Constraint
+	DropUniqueConstraint(name ...string)
+	GetCheck(name string) *Constraint
+	AddCheck(name string, expression string) *Constraint
+	DropCheck(name ...string)
+
+	// defined in diff.go
+	ApplyDiff(desired *dbal.Table) []*dbal.Command
+
+	// defined in json.go
+	ToJSON() ([]byte, error)
 
 	// defined in blueprint.go
 	// Character types
@@ -0,0 +1,337 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// JSONVersion the version of the JSON table definition format
+const JSONVersion = "1.0"
+
+// jsonColumnArgs the argument of the column types, the types not listed have no argument.
+var jsonColumnArgs = map[string]string{
+	"string":      "length",
+	"char":        "length",
+	"binary":      "length",
+	"dateTime":    "datetime_precision",
+	"dateTimeTz":  "datetime_precision",
+	"time":        "datetime_precision",
+	"timeTz":      "datetime_precision",
+	"timestamp":   "datetime_precision",
+	"timestampTz": "datetime_precision",
+	"decimal":     "precision",
+	"float":       "precision",
+	"double":      "precision",
+}
+
+// jsonColumnTypes the blueprint methods of the column types ( except enum )
+var jsonColumnTypes = map[string]func(table *Table, name string, args ...int) *Column{
+	"string":       (*Table).String,
+	"char":         (*Table).Char,
+	"text":         jsonColumnType((*Table).Text),
+	"mediumText":   jsonColumnType((*Table).MediumText),
+	"longText":     jsonColumnType((*Table).LongText),
+	"binary":       (*Table).Binary,
+	"date":         jsonColumnType((*Table).Date),
+	"dateTime":     (*Table).DateTime,
+	"dateTimeTz":   (*Table).DateTimeTz,
+	"time":         (*Table).Time,
+	"timeTz":       (*Table).TimeTz,
+	"timestamp":    (*Table).Timestamp,
+	"timestampTz":  (*Table).TimestampTz,
+	"tinyInteger":  jsonColumnType((*Table).TinyInteger),
+	"smallInteger": jsonColumnType((*Table).SmallInteger),
+	"integer":      jsonColumnType((*Table).Integer),
+	"bigInteger":   jsonColumnType((*Table).BigInteger),
+	"decimal":      (*Table).Decimal,
+	"float":        (*Table).Float,
+	"double":       (*Table).Double,
+	"boolean":      jsonColumnType((*Table).Boolean),
+	"json":         jsonColumnType((*Table).JSON),
+	"jsonb":        jsonColumnType((*Table).JSONB),
+	"uuid":         jsonColumnType((*Table).UUID),
+	"ipAddress":    jsonColumnType((*Table).IPAddress),
+	"macAddress":   jsonColumnType((*Table).MACAddress),
+	"year":         jsonColumnType((*Table).Year),
+}
+
+// NewTableFromJSON create a new blueprint instance using the given JSON definition
+//
+//	{
+//	  "version": "1.0",
+//	  "name": "users",
+//	  "columns": [
+//	    { "name": "id", "type": "bigInteger", "unsigned": true, "auto_increment": true },
+//	    { "name": "email", "type": "string", "length": 120, "comment": "the email" },
+//	    { "name": "vote", "type": "decimal", "precision": 10, "scale": 2, "nullable": true }
+//	  ],
+//	  "indexes": [{ "name": "email_unique", "type": "unique", "columns": ["email"] }],
+//	  "primary": ["id"]
+//	}
+//
+// The table comment, foreign keys and unique or check constraints are not part of the definition,
+// use the blueprint methods to manage them, ApplyJSON keeps them unchanged.
+func NewTableFromJSON(data []byte, builder *Builder) (*Table, error) {
+	def := TableJSON{}
+	err := json.Unmarshal(data, &def)
+	if err != nil {
+		return nil, err
+	}
+
+	if def.Version == "" {
+		return nil, fmt.Errorf("the version of the table definition is required")
+	}
+	if strings.Split(def.Version, ".")[0] != strings.Split(JSONVersion, ".")[0] {
+		return nil, fmt.Errorf("the version %s of the table definition is not supported, the version should be %s", def.Version, JSONVersion)
+	}
+	if def.Name == "" {
+		return nil, fmt.Errorf("the name of the table is required")
+	}
+
+	table := NewTable(def.Name, builder)
+	for _, columnDef := range def.Columns {
+		_, err = table.putColumnJSON(columnDef)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, indexDef := range def.Indexes {
+		err = table.putIndexJSON(indexDef)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(def.Primary) > 0 {
+		for _, name := range def.Primary {
+			if !table.HasColumn(name) {
+				return nil, fmt.Errorf("the column %s of the primary key does not exists", name)
+			}
+		}
+		table.AddPrimary(def.Primary...)
+	}
+
+	return table, nil
+}
+
+// CreateTableFromJSON create a new table on the schema using the given JSON definition.
+func (builder *Builder) CreateTableFromJSON(data []byte) error {
+	table, err := NewTableFromJSON(data, builder)
+	if err != nil {
+		return err
+	}
+	return builder.createTable(table)
+}
+
+// MustCreateTableFromJSON create a new table on the schema using the given JSON definition.
+func (builder *Builder) MustCreateTableFromJSON(data []byte) {
+	err := builder.CreateTableFromJSON(data)
+	utils.PanicIF(err)
+}
+
+// ApplyJSON create the table if it does not exist, otherwise alter the table to match the given JSON definition.
+func (builder *Builder) ApplyJSON(data []byte) error {
+	desired, err := NewTableFromJSON(data, builder)
+	if err != nil {
+		return err
+	}
+
+	has, err := builder.HasTable(desired.Name)
+	if err != nil {
+		return err
+	}
+
+	if !has {
+		return builder.createTable(desired)
+	}
+
+	return builder.AlterTable(desired.Name, func(table Blueprint) {
+		table.ApplyDiff(desired.Table)
+	})
+}
+
+// MustApplyJSON create the table if it does not exist, otherwise alter the table to match the given JSON definition.
+func (builder *Builder) MustApplyJSON(data []byte) {
+	err := builder.ApplyJSON(data)
+	utils.PanicIF(err)
+}
+
+// ToJSON Get the JSON definition of the table.
+// The types not supported natively by the database are exported as the stored types, see storedTypes.
+// eg: SQLite exports the uuid column as string, the json column as text, the timestampTz column as timestamp
+// and the auto-increment primary key as integer.
+func (table *Table) ToJSON() ([]byte, error) {
+	def := TableJSON{
+		Version: JSONVersion,
+		Name:    table.Name,
+		Columns: []*ColumnJSON{},
+		Indexes: []*IndexJSON{},
+	}
+
+	for _, column := range table.Table.Columns {
+		if !table.Table.HasColumn(column.Name) {
+			continue
+		}
+		def.Columns = append(def.Columns, columnJSON(column))
+	}
+
+	for _, index := range table.Table.Indexes {
+		if index.Type == "primary" || !table.Table.HasIndex(index.Name) {
+			continue
+		}
+		def.Indexes = append(def.Indexes, indexJSON(index))
+	}
+	sort.Slice(def.Indexes, func(i, j int) bool {
+		return def.Indexes[i].Name < def.Indexes[j].Name
+	})
+
+	if table.Table.Primary != nil {
+		for _, column := range table.Table.Primary.Columns {
+			def.Primary = append(def.Primary, column.Name)
+		}
+	}
+
+	return json.MarshalIndent(def, "", "  ")
+}
+
+// putColumnJSON add a column to the table using the given JSON definition
+func (table *Table) putColumnJSON(def *ColumnJSON) (*Column, error) {
+	if def.Name == "" {
+		return nil, fmt.Errorf("the name of the column is required")
+	}
+	if table.HasColumn(def.Name) {
+		return nil, fmt.Errorf("the column %s is duplicated", def.Name)
+	}
+
+	var column *Column
+	if def.Type == "enum" {
+		column = table.Enum(def.Name, def.Option)
+	} else {
+		create, has := jsonColumnTypes[def.Type]
+		if !has {
+			return nil, fmt.Errorf("the type %s of the column %s is not supported", def.Type, def.Name)
+		}
+
+		args := []int{}
+		switch jsonColumnArgs[def.Type] {
+		case "length":
+			args = append(args, utils.IntVal(def.Length))
+		case "datetime_precision":
+			args = append(args, utils.IntVal(def.DateTimePrecision))
+		case "precision":
+			args = append(args, utils.IntVal(def.Precision), utils.IntVal(def.Scale))
+		}
+		column = create(table, def.Name, args...)
+	}
+
+	if def.Unsigned {
+		column.Unsigned()
+	}
+	if def.Nullable {
+		column.Null()
+	}
+	if def.AutoIncrement {
+		column.AutoIncrement()
+	}
+	if def.Default != nil {
+		column.SetDefault(def.Default)
+	}
+	if def.DefaultRaw != "" {
+		column.SetDefaultRaw(def.DefaultRaw)
+	}
+	if def.Comment != "" {
+		column.SetComment(def.Comment)
+	}
+	column.RenameFrom = def.RenameFrom
+	return column, nil
+}
+
+// putIndexJSON add an index to the table using the given JSON definition
+func (table *Table) putIndexJSON(def *IndexJSON) error {
+	if def.Name == "" {
+		return fmt.Errorf("the name of the index is required")
+	}
+	if len(def.Columns) == 0 {
+		return fmt.Errorf("the columns of the index %s are required", def.Name)
+	}
+	for _, name := range def.Columns {
+		if !table.HasColumn(name) {
+			return fmt.Errorf("the column %s of the index %s does not exists", name, def.Name)
+		}
+	}
+
+	switch def.Type {
+	case "", "index":
+		table.AddIndex(def.Name, def.Columns...)
+	case "unique":
+		table.AddUnique(def.Name, def.Columns...)
+	default:
+		return fmt.Errorf("the type %s of the index %s is not supported", def.Type, def.Name)
+	}
+
+	if def.Comment != "" {
+		table.GetIndex(def.Name).Comment = &def.Comment
+	}
+	return nil
+}
+
+// columnJSON get the JSON definition of the column
+func columnJSON(column *dbal.Column) *ColumnJSON {
+	def := &ColumnJSON{
+		Name:          column.Name,
+		Type:          column.Type,
+		Nullable:      column.Nullable,
+		Unsigned:      column.IsUnsigned,
+		AutoIncrement: utils.StringVal(column.Extra) == "AutoIncrement",
+		DefaultRaw:    column.DefaultRaw,
+		Comment:       diffComment(utils.StringVal(column.Comment)),
+		RenameFrom:    column.RenameFrom,
+	}
+
+	def.Default, def.DefaultRaw = splitDefaultValue(column.Default)
+	if column.DefaultRaw != "" {
+		def.DefaultRaw = column.DefaultRaw
+	}
+
+	switch jsonColumnArgs[column.Type] {
+	case "length":
+		def.Length = column.Length
+	case "datetime_precision":
+		def.DateTimePrecision = column.DateTimePrecision
+	case "precision":
+		def.Precision = column.Precision
+		def.Scale = column.Scale
+	}
+
+	if column.Type == "enum" {
+		def.Option = column.Option
+	}
+	return def
+}
+
+// indexJSON get the JSON definition of the index
+func indexJSON(index *dbal.Index) *IndexJSON {
+	def := &IndexJSON{
+		Name:    index.Name,
+		Type:    index.Type,
+		Columns: []string{},
+		Comment: utils.StringVal(index.Comment),
+	}
+	for _, column := range index.Columns {
+		def.Columns = append(def.Columns, column.Name)
+	}
+	return def
+}
+
+// jsonColumnType wrap the blueprint method of the column type which has no argument
+func jsonColumnType(create func(table *Table, name string) *Column) func(table *Table, name string, args ...int) *Column {
+	return func(table *Table, name string, args ...int) *Column {
+		return create(table, name)
+	}
+}
@@ -0,0 +1,266 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestJSONCreateTable(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForJSONTest()
+
+	table := builder.MustGetTable("table_test_json")
+	def := TableJSON{}
+	err := json.Unmarshal([]byte(testJSONTable), &def)
+	assert.Equal(t, nil, err, "the return error should be nil")
+	for _, column := range def.Columns {
+		assert.True(t, table.HasColumn(column.Name), "the table should have the %s column", column.Name)
+	}
+	assert.True(t, table.HasIndex("title_index", "email_unique", "name_vote_index"), "the table should have the indexes")
+	assert.Equal(t, "unique", table.GetIndex("email_unique").Type, "the email_unique type should be unique")
+	assert.Equal(t, "id", table.GetPrimary().Columns[0].Name, "the primary key should have the id column")
+	assert.Equal(t, "AutoIncrement", *table.GetColumn("id").Extra, "the id column should be auto-increment")
+	assert.Equal(t, 120, *table.GetColumn("email").Length, "the length of the email column should be 120")
+	assert.True(t, table.GetColumn("title").Nullable, "the title column should be nullable")
+	assert.Equal(t, []string{"draft", "published"}, table.GetColumn("status").Option, "the options of the status column should be draft and published")
+	if unit.DriverNot("sqlite3") {
+		assert.Equal(t, "the email", *table.GetColumn("email").Comment, "the comment of the email column should be the email")
+		assert.Equal(t, 12, *table.GetColumn("amount").Precision, "the precision of the amount column should be 12")
+		assert.Equal(t, 3, *table.GetColumn("amount").Scale, "the scale of the amount column should be 3")
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForJSONTest()
+
+	data, err := builder.MustGetTable("table_test_json").ToJSON()
+	assert.Equal(t, nil, err, "the return error should be nil")
+
+	builder.DropTableIfExists("table_test_json_copy")
+	copy := strings.Replace(string(data), `"name": "table_test_json"`, `"name": "table_test_json_copy"`, 1)
+	err = builder.CreateTableFromJSON([]byte(copy))
+	assert.Equal(t, nil, err, "the return error should be nil")
+
+	copyData, err := builder.MustGetTable("table_test_json_copy").ToJSON()
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.Equal(t, copy, string(copyData), "the JSON definition of the copied table should be the same")
+
+	// the table matches the definition exported
+	commands := Diff(builder.MustGetTable("table_test_json").Get().Table, testNewTableFromJSON(t, data).Table, unit.Driver())
+	assert.Equal(t, []string{}, diffCommandNames(commands), "there should be no commands")
+}
+
+func TestJSONApplyNothingChanged(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForJSONTest()
+
+	// the table matches the source definition
+	commands := Diff(builder.MustGetTable("table_test_json").Get().Table, testNewTableFromJSON(t, []byte(testJSONTable)).Table, unit.Driver())
+	assert.Equal(t, []string{}, diffCommandNames(commands), "there should be no commands")
+
+	err := builder.AlterTable("table_test_json", func(table Blueprint) {
+		commands := table.ApplyDiff(testNewTableFromJSON(t, []byte(testJSONTable)).Table)
+		assert.Equal(t, []string{}, diffCommandNames(commands), "applying the definition again should have no commands")
+	})
+	assert.Equal(t, nil, err, "the return error should be nil")
+}
+
+func TestJSONTypes(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	NewTableForJSONTest()
+
+	source := TableJSON{}
+	err := json.Unmarshal([]byte(testJSONTable), &source)
+	assert.Equal(t, nil, err, "the return error should be nil")
+
+	data, err := builder.MustGetTable("table_test_json").ToJSON()
+	assert.Equal(t, nil, err, "the return error should be nil")
+	def := TableJSON{}
+	err = json.Unmarshal(data, &def)
+	assert.Equal(t, nil, err, "the return error should be nil")
+
+	columns := map[string]*ColumnJSON{}
+	for _, column := range def.Columns {
+		columns[column.Name] = column
+	}
+	for _, column := range source.Columns {
+		if !assert.NotNil(t, columns[column.Name], "the %s column should be exported", column.Name) {
+			continue
+		}
+		typ := columns[column.Name].Type
+		if column.AutoIncrement && unit.DriverIs("sqlite3") {
+			typ = column.Type
+		}
+		assert.True(t, typ == column.Type || typ == storedTypes[unit.Driver()][column.Type], "the type of the %s column should be %s or the stored type, %s given", column.Name, column.Type, typ)
+		if unit.DriverNot("sqlite3") {
+			assert.Equal(t, column.Comment, columns[column.Name].Comment, "the comment of the %s column should not have the type prefix", column.Name)
+		}
+	}
+}
+
+func TestJSONBlueprintToJSON(t *testing.T) {
+	table := testNewTableFromJSON(t, []byte(testJSONTable))
+	data, err := table.ToJSON()
+	assert.Equal(t, nil, err, "the return error should be nil")
+
+	def := TableJSON{}
+	err = json.Unmarshal(data, &def)
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.Equal(t, JSONVersion, def.Version, "the version should be %s", JSONVersion)
+	assert.Equal(t, "table_test_json", def.Name, "the name should be table_test_json")
+	assert.Equal(t, []string{"id"}, def.Primary, "the primary key should have the id column")
+	assert.Equal(t, 3, len(def.Indexes), "the definition should have 3 indexes")
+	assert.Equal(t, "email_unique", def.Indexes[0].Name, "the indexes should be sorted by name")
+
+	columns := map[string]*ColumnJSON{}
+	for _, column := range def.Columns {
+		columns[column.Name] = column
+	}
+	assert.Equal(t, "bigInteger", columns["id"].Type, "the type of the id column should be bigInteger")
+	assert.True(t, columns["id"].AutoIncrement, "the id column should be auto-increment")
+	assert.True(t, columns["id"].Unsigned, "the id column should be unsigned")
+	assert.Equal(t, 120, *columns["email"].Length, "the length of the email column should be 120")
+	assert.Equal(t, "the email", columns["email"].Comment, "the comment of the email column should be the email")
+	assert.Equal(t, 12, *columns["amount"].Precision, "the precision of the amount column should be 12")
+	assert.Equal(t, 3, *columns["amount"].Scale, "the scale of the amount column should be 3")
+	assert.Equal(t, 6, *columns["published_at"].DateTimePrecision, "the datetime precision of the published_at column should be 6")
+	assert.Equal(t, "NOW()", columns["created_at"].DefaultRaw, "the default raw of the created_at column should be NOW()")
+	assert.Equal(t, float64(0), columns["vote"].Default, "the default of the vote column should be 0")
+	assert.Equal(t, []string{"draft", "published"}, columns["status"].Option, "the options of the status column should be draft and published")
+	assert.Nil(t, columns["vote"].Length, "the vote column should not have the length")
+}
+
+func TestJSONApply(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_json")
+
+	// Create
+	err := builder.ApplyJSON([]byte(testJSONTable))
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.True(t, builder.MustHasTable("table_test_json"), "the table_test_json table should be created")
+	builder.MustGetDB().MustExec("INSERT INTO table_test_json (email, title, name, status) VALUES ('max@example.com', 'Hello', 'Max', 'draft')")
+
+	// Alter
+	def := TableJSON{}
+	json.Unmarshal([]byte(testJSONTable), &def)
+	for _, column := range def.Columns {
+		if column.Name == "title" {
+			column.Name = "subject"
+			column.RenameFrom = "title"
+		}
+	}
+	def.Columns = append(def.Columns, &ColumnJSON{Name: "points", Type: "integer", Nullable: true})
+	def.Indexes = []*IndexJSON{
+		{Name: "email_unique", Type: "unique", Columns: []string{"email"}},
+		{Name: "points_index", Type: "index", Columns: []string{"points"}},
+	}
+	data, _ := json.Marshal(def)
+	err = builder.ApplyJSON(data)
+	assert.Equal(t, nil, err, "the return error should be nil")
+
+	table := builder.MustGetTable("table_test_json")
+	assert.True(t, table.HasColumn("subject", "points"), "the table should have the subject and points columns")
+	assert.False(t, table.HasColumn("title"), "the title column should be renamed")
+	assert.True(t, table.HasIndex("email_unique", "points_index"), "the table should have the email_unique and points_index indexes")
+	assert.False(t, table.HasIndex("title_index"), "the title_index index should be dropped")
+	assert.False(t, table.HasIndex("name_vote_index"), "the name_vote_index index should be dropped")
+
+	subject := ""
+	err = builder.MustGetDB().Get(&subject, "SELECT subject FROM table_test_json")
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.Equal(t, "Hello", subject, "the data of the renamed column should be kept")
+}
+
+func TestJSONFail(t *testing.T) {
+	builder := getTestBuilderInstance()
+	for name, data := range map[string]string{
+		"syntax":        `{"version": "1.0", "name": }`,
+		"version":       `{"name": "test", "columns": [{"name": "id", "type": "integer"}]}`,
+		"unsupported":   `{"version": "2.0", "name": "test", "columns": [{"name": "id", "type": "integer"}]}`,
+		"name":          `{"version": "1.0", "columns": [{"name": "id", "type": "integer"}]}`,
+		"type":          `{"version": "1.0", "name": "test", "columns": [{"name": "id", "type": "geometry"}]}`,
+		"duplicated":    `{"version": "1.0", "name": "test", "columns": [{"name": "id", "type": "integer"}, {"name": "id", "type": "string"}]}`,
+		"index column":  `{"version": "1.0", "name": "test", "columns": [{"name": "id", "type": "integer"}], "indexes": [{"name": "name_index", "columns": ["name"]}]}`,
+		"index type":    `{"version": "1.0", "name": "test", "columns": [{"name": "id", "type": "integer"}], "indexes": [{"name": "id_index", "type": "spatial", "columns": ["id"]}]}`,
+		"primary":       `{"version": "1.0", "name": "test", "columns": [{"name": "id", "type": "integer"}], "primary": ["uid"]}`,
+		"column name":   `{"version": "1.0", "name": "test", "columns": [{"type": "integer"}]}`,
+		"index columns": `{"version": "1.0", "name": "test", "columns": [{"name": "id", "type": "integer"}], "indexes": [{"name": "id_index"}]}`,
+	} {
+		_, err := NewTableFromJSON([]byte(data), builder)
+		assert.False(t, err == nil, "the %s error should be returned", name)
+	}
+}
+
+// clean the test data
+func TestJSONClean(t *testing.T) {
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_json")
+	builder.DropTableIfExists("table_test_json_copy")
+}
+
+func testNewTableFromJSON(t *testing.T, data []byte) *Table {
+	table, err := NewTableFromJSON(data, getTestBuilderInstance())
+	assert.Equal(t, nil, err, "the return error should be nil")
+	return table
+}
+
+func NewTableForJSONTest() {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_json")
+	builder.MustCreateTableFromJSON([]byte(testJSONTable))
+}
+
+var testJSONTable = `{
+  "version": "1.0",
+  "name": "table_test_json",
+  "columns": [
+    { "name": "id", "type": "bigInteger", "unsigned": true, "auto_increment": true },
+    { "name": "email", "type": "string", "length": 120, "comment": "the email" },
+    { "name": "title", "type": "string", "length": 200, "nullable": true },
+    { "name": "code", "type": "char", "length": 10, "nullable": true },
+    { "name": "content", "type": "text", "nullable": true },
+    { "name": "summary", "type": "mediumText", "nullable": true },
+    { "name": "body", "type": "longText", "nullable": true },
+    { "name": "hash", "type": "binary", "length": 64, "nullable": true },
+    { "name": "birthday", "type": "date", "nullable": true },
+    { "name": "published_at", "type": "dateTime", "datetime_precision": 6, "nullable": true },
+    { "name": "published_tz", "type": "dateTimeTz", "nullable": true },
+    { "name": "start_time", "type": "time", "nullable": true },
+    { "name": "start_tz", "type": "timeTz", "nullable": true },
+    { "name": "created_at", "type": "timestamp", "default_raw": "NOW()" },
+    { "name": "updated_at", "type": "timestampTz", "nullable": true },
+    { "name": "level", "type": "tinyInteger", "nullable": true },
+    { "name": "rank", "type": "smallInteger", "nullable": true },
+    { "name": "vote", "type": "integer", "default": 0 },
+    { "name": "views", "type": "bigInteger", "unsigned": true, "nullable": true },
+    { "name": "amount", "type": "decimal", "precision": 12, "scale": 3, "nullable": true },
+    { "name": "rate", "type": "float", "nullable": true },
+    { "name": "score", "type": "double", "nullable": true },
+    { "name": "active", "type": "boolean", "nullable": true },
+    { "name": "status", "type": "enum", "option": ["draft", "published"], "nullable": true },
+    { "name": "extra", "type": "json", "nullable": true },
+    { "name": "options", "type": "jsonb", "nullable": true },
+    { "name": "uuid", "type": "uuid", "nullable": true },
+    { "name": "ip", "type": "ipAddress", "nullable": true },
+    { "name": "mac", "type": "macAddress", "nullable": true },
+    { "name": "year", "type": "year", "nullable": true },
+    { "name": "name", "type": "string", "length": 80, "nullable": true }
+  ],
+  "indexes": [
+    { "name": "title_index", "type": "index", "columns": ["title"] },
+    { "name": "email_unique", "type": "unique", "columns": ["email"] },
+    { "name": "name_vote_index", "type": "index", "columns": ["name", "vote"] }
+  ],
+  "primary": ["id"]
+}`
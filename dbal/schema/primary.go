@@ -19,10 +19,12 @@ func (table *Table) DropPrimary() {
 	primary := table.GetPrimary()
 	table.dropPrimaryCommand(primary, func() {
 		table.Primary = nil
+		table.Table.Primary = nil
 	}, nil)
 }
 
 // addPrimaryWithName Indicate that the given column should be a primary index.
+// The primary key of the dbal table is set as well, so that Diff and ToJSON see the blueprint primary key.
 func (table *Table) addPrimaryWithName(name string, columnNames ...string) {
 	columns := []*dbal.Column{}
 	for _, columnName := range columnNames {
@@ -37,7 +39,9 @@ func (table *Table) addPrimaryWithName(name string, columnNames ...string) {
 	}
 
 	table.Primary = primary
+	table.Table.Primary = primary.Primary
 	table.createPrimaryCommand(primary.Primary, nil, func() {
 		table.Primary = nil
+		table.Table.Primary = nil
 	})
 }
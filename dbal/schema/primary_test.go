@@ -22,6 +22,20 @@ func TestPrimaryAddPrimary(t *testing.T) {
 	CheckPrimaryKey(t, primryKey)
 }
 
+func TestPrimaryAddPrimaryTable(t *testing.T) {
+	table := NewTable("table_test_primary", getTestBuilderInstance())
+	table.Integer("id")
+	table.AddPrimary("id")
+	assert.True(t, table.Table.Primary != nil, "the primary key of the dbal table should be set")
+	if table.Table.Primary != nil {
+		assert.Equal(t, "id", table.Table.Primary.Columns[0].Name, "the primary key of the dbal table should have the id column")
+	}
+
+	table.DropPrimary()
+	table.Table.Commands[len(table.Table.Commands)-1].Success()
+	assert.True(t, table.Table.Primary == nil, "the primary key of the dbal table should be nil")
+}
+
 func TestPrimaryAddPrimaryFail(t *testing.T) {
 	defer unit.Catch()
 	if unit.DriverIs("sqlite3") {
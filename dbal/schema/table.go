@@ -25,14 +25,16 @@ func (table *Table) GetFullName() string {
 func NewTable(name string, builder *Builder) *Table {
 	tableName := fmt.Sprintf("%s%s", builder.Conn.Option.Prefix, name)
 	table := &Table{
-		Name:        name,
-		Prefix:      builder.Conn.Option.Prefix,
-		Table:       dbal.NewTable(tableName, builder.Schema, builder.Database),
-		Builder:     builder,
-		IndexNames:  []string{},
-		ColumnNames: []string{},
-		ColumnMap:   map[string]*Column{},
-		IndexMap:    map[string]*Index{},
+		Name:          name,
+		Prefix:        builder.Conn.Option.Prefix,
+		Table:         dbal.NewTable(tableName, builder.Schema, builder.Database),
+		Builder:       builder,
+		IndexNames:    []string{},
+		ColumnNames:   []string{},
+		ColumnMap:     map[string]*Column{},
+		IndexMap:      map[string]*Index{},
+		ForeignMap:    map[string]*Foreign{},
+		ConstraintMap: map[string]*Constraint{},
 	}
 	return table
 }
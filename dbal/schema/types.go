@@ -27,12 +27,14 @@ type Table struct {
 	*dbal.Table
 	*Builder
 	*Primary
-	ColumnNames []string
-	ColumnMap   map[string]*Column
-	IndexNames  []string
-	IndexMap    map[string]*Index
-	Name        string
-	Prefix      string
+	ColumnNames   []string
+	ColumnMap     map[string]*Column
+	IndexNames    []string
+	IndexMap      map[string]*Index
+	ForeignMap    map[string]*Foreign
+	ConstraintMap map[string]*Constraint
+	Name          string
+	Prefix        string
 }
 
 // Column the table column struct
@@ -47,8 +49,55 @@ type Index struct {
 	Table *Table
 }
 
+// Foreign the table foreign key constraint
+type Foreign struct {
+	*dbal.Foreign
+	Table *Table
+}
+
+// Constraint the table unique or check constraint
+type Constraint struct {
+	*dbal.Constraint
+	Table *Table
+}
+
 // Primary the table primary key
 type Primary struct {
 	*dbal.Primary
 	Table *Table
 }
+
+// TableJSON the JSON definition of the table
+type TableJSON struct {
+	Version string        `json:"version"`
+	Name    string        `json:"name"`
+	Columns []*ColumnJSON `json:"columns"`
+	Indexes []*IndexJSON  `json:"indexes,omitempty"`
+	Primary []string      `json:"primary,omitempty"`
+}
+
+// ColumnJSON the JSON definition of the table column
+type ColumnJSON struct {
+	Name              string      `json:"name"`
+	Type              string      `json:"type"`
+	Length            *int        `json:"length,omitempty"`
+	Precision         *int        `json:"precision,omitempty"`
+	Scale             *int        `json:"scale,omitempty"`
+	DateTimePrecision *int        `json:"datetime_precision,omitempty"`
+	Option            []string    `json:"option,omitempty"`
+	Nullable          bool        `json:"nullable,omitempty"`
+	Unsigned          bool        `json:"unsigned,omitempty"`
+	AutoIncrement     bool        `json:"auto_increment,omitempty"`
+	Default           interface{} `json:"default,omitempty"`
+	DefaultRaw        string      `json:"default_raw,omitempty"`
+	Comment           string      `json:"comment,omitempty"`
+	RenameFrom        string      `json:"rename_from,omitempty"`
+}
+
+// IndexJSON the JSON definition of the table index
+type IndexJSON struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"` // index, unique
+	Columns []string `json:"columns"`
+	Comment string   `json:"comment,omitempty"`
+}
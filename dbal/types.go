@@ -55,8 +55,12 @@ type Table struct {
 	Primary       *Primary
 	ColumnMap     map[string]*Column
 	IndexMap      map[string]*Index
+	ForeignMap    map[string]*Foreign
+	ConstraintMap map[string]*Constraint
 	Columns       []*Column
 	Indexes       []*Index
+	Foreigns      []*Foreign
+	Constraints   []*Constraint
 	Commands      []*Command
 }
 
@@ -92,6 +96,7 @@ type Column struct {
 	MaxDateTimePrecision     int
 	DefaultDateTimePrecision int
 	Option                   []string
+	RenameFrom               string // the former name of the column, a hint for computing the schema diff
 	Table                    *Table
 	Indexes                  []*Index
 	Constraint               *Constraint
@@ -118,6 +123,21 @@ type Index struct {
 	Columns      []*Column
 }
 
+// Foreign the table foreign key constraint
+type Foreign struct {
+	DBName               string `db:"db_name"`
+	TableName            string `db:"table_name"`
+	Name                 string `db:"foreign_name"`
+	ColumnName           string `db:"column_name"`
+	ReferencedTableName  string `db:"referenced_table_name"`
+	ReferencedColumnName string `db:"referenced_column_name"`
+	OnDelete             string `db:"on_delete"`
+	OnUpdate             string `db:"on_update"`
+	Table                *Table
+	Columns              []string
+	ReferencedColumns    []string
+}
+
 // Primary the table primary key
 type Primary struct {
 	DBName    string `db:"db_name"`
@@ -129,11 +149,14 @@ type Primary struct {
 
 // Constraint the table constraint
 type Constraint struct {
-	SchemaName string
-	TableName  string
-	ColumnName string
-	Type       string
+	SchemaName string `db:"schema_name"`
+	TableName  string `db:"table_name"`
+	ColumnName string `db:"column_name"`
+	Name       string `db:"constraint_name"`
+	Type       string `db:"constraint_type"` // UNIQUE, CHECK
+	Expression string `db:"expression"`      // the expression of the CHECK constraint
 	Args       []string
+	Columns    []string // the columns of the UNIQUE constraint
 	Table      *Table
 }
 
@@ -175,14 +198,21 @@ type Where struct {
 	Offset   int
 }
 
+// FullText the full text search of the query
+type FullText struct {
+	Columns []interface{}          // The columns to search
+	Options map[string]interface{} // The search options. eg: {"mode": "boolean", "language": "english"}
+}
+
 // Join the join clause for the query
 type Join struct {
-	Type   string      // inner, left, right, cross
-	Name   interface{} // The table the join clause is joining to.
-	Query  *Query
-	Alias  string
-	SQL    interface{}
-	Offset int
+	Type    string      // inner, left, right, cross
+	Name    interface{} // The table the join clause is joining to.
+	Query   *Query
+	Alias   string
+	SQL     interface{}
+	Offset  int
+	Lateral bool // The subquery could reference the columns of the preceding tables. (PostgreSQL, MySQL 8.0.14+)
 }
 
 // Union the query union statement
@@ -191,6 +221,23 @@ type Union struct {
 	Query *Query
 }
 
+// CTE the common table expression of the query
+type CTE struct {
+	Name         string   // The name of the expression
+	Columns      []string // The column names of the expression
+	Query        *Query   // The subquery of the expression
+	SQL          string   // The raw SQL of the expression
+	Recursive    bool     // Whether the expression is recursive
+	Materialized bool     // Whether the expression should be materialized, only available for PostgreSQL
+}
+
+// Lock the row locking clause of the query
+type Lock struct {
+	Type   string   // share, update, no key update
+	Of     []string // The tables (or aliases) to lock, lock all the tables of the query if it's empty.
+	Option string   // skip locked, nowait
+}
+
 // Aggregate An aggregate function and column to be run.
 type Aggregate struct {
 	Func    string        // AVG, COUNT, MIN, MAX, SUM
@@ -217,6 +264,8 @@ type Order struct {
 	Direction string
 	Offset    int
 	SQL       string
+	FullText  *FullText   // The full text search of the "fulltext" order
+	Value     interface{} // The search value of the "fulltext" order, or the seed of the "random" order
 }
 
 // From the from query
@@ -237,6 +286,21 @@ type Select struct {
 	SQL    string
 }
 
+// Window the window definition of the window functions
+type Window struct {
+	Name        string        // The name of the named window
+	PartitionBy []interface{} // The partition by columns
+	OrderBy     []Order       // The order by columns
+	Frame       string        // The frame clause. eg: rows between unbounded preceding and current row
+}
+
+// WindowFunction the window function column of the select
+type WindowFunction struct {
+	Func   interface{} // The window function. eg: row_number(), sum(amount), dbal.Raw("count(*)")
+	Window Window      // The window of the function, refers to the named window if only the name is given
+	Alias  string      // The alias of the column
+}
+
 // Query the query builder
 type Query struct {
 	UseWriteConnection bool                     // Whether to use write connection for the select. default is false
@@ -247,6 +311,7 @@ type Query struct {
 	Wheres             []Where                  // The where constraints for the query.
 	Joins              []Join                   // The table joins for the query.
 	Unions             []Union                  // The query union statements.
+	CTEs               []CTE                    // The common table expressions of the query.
 	UnionLimit         int                      // The maximum number of union records to return.
 	UnionOffset        int                      // The number of union records to skip.
 	UnionOrders        []Order                  // The orderings for the union query.
@@ -255,6 +320,8 @@ type Query struct {
 	Offset             int                      // The number of records to skip.
 	Groups             []interface{}            // The groupings for the query.
 	Havings            []Having                 // The having constraints for the query.
+	Windows            []Window                 // The named window definitions for the query.
+	Returning          []interface{}            // The columns that should be returned by the insert, update and delete statements.
 	Bindings           map[string][]interface{} // The current query value bindings.
 	Distinct           bool                     // Indicates if the query returns distinct results. Occasionally contains the columns that should be distinct. default is false
 	DistinctColumns    []interface{}            // Indicates if the query returns distinct results. Occasionally contains the columns that should be distinct.
@@ -262,3 +329,22 @@ type Query struct {
 	BindingOffset      int                      // The Binding offset before select
 	SQL                string                   // The SQL STMT
 }
+
+// Plan the normalized query plan of the explain statement
+type Plan struct {
+	Nodes []*PlanNode // The root nodes of the query plan.
+	Cost  float64     // The total cost estimated by the database, 0 if not available.
+	Raw   interface{} // The raw output of the explain statement.
+}
+
+// PlanNode the node of the query plan
+type PlanNode struct {
+	Operation string      // The operation of the node. eg: Seq Scan (Postgres), ALL (MySQL), SCAN (SQLite)
+	Table     string      // The table of the node, empty if the node has no table
+	Index     string      // The index used by the node, empty if the node uses no index
+	Rows      float64     // The rows estimated by the database, 0 if not available.
+	Cost      float64     // The cost estimated by the database, 0 if not available.
+	FullScan  bool        // Indicates if the node scans the whole table without using index.
+	Detail    string      // The detail description of the node
+	Children  []*PlanNode // The child nodes
+}
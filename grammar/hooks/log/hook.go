@@ -69,8 +69,10 @@ func (h *Hook) log(ctx context.Context, query string, err error, args ...interfa
 	}
 	fields[QueryFieldName] = query
 	fields[RequestTimeFieldName] = rt
-	for k, v := range h.ContextFields(ctx) {
-		fields[k] = v
+	if h.ContextFields != nil {
+		for k, v := range h.ContextFields(ctx) {
+			fields[k] = v
+		}
 	}
 	for i, arg := range args {
 		argName := ArgFieldPrefix + strconv.Itoa(i)
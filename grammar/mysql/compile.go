@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/blang/semver/v4"
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/grammar/sql"
 )
 
 // CompileSelect Compile a select query into SQL.
@@ -30,6 +32,10 @@ func (grammarSQL MySQL) CompileSelectOffset(query *dbal.Query, offset *int) stri
 		return grammarSQL.CompileUnionAggregate(query)
 	}
 
+	// The common table expressions should be compiled first, because
+	// their bindings are placed before the bindings of the select.
+	with := grammarSQL.CompileWith(query, query.CTEs, offset)
+
 	sqls := map[string]string{}
 
 	// If the query does not have any columns set, we'll set the columns to the
@@ -50,13 +56,14 @@ func (grammarSQL MySQL) CompileSelectOffset(query *dbal.Query, offset *int) stri
 	sqls["wheres"] = grammarSQL.CompileWheres(query, query.Wheres, offset)
 	sqls["groups"] = grammarSQL.CompileGroups(query, query.Groups, offset)
 	sqls["havings"] = grammarSQL.CompileHavings(query, query.Havings, offset)
+	sqls["windows"] = grammarSQL.CompileWindows(query, query.Windows, offset)
 	sqls["orders"] = grammarSQL.CompileOrders(query, query.Orders, offset)
 	sqls["limit"] = grammarSQL.CompileLimit(query, query.Limit, offset)
 	sqls["offset"] = grammarSQL.CompileOffset(query, query.Offset)
 	sqls["lock"] = grammarSQL.CompileLock(query, query.Lock)
 
 	sql := ""
-	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "orders", "limit", "offset", "lock"} {
+	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "windows", "orders", "limit", "offset", "lock"} {
 		segment, has := sqls[name]
 		if has && segment != "" {
 			sql = sql + segment + " "
@@ -68,13 +75,35 @@ func (grammarSQL MySQL) CompileSelectOffset(query *dbal.Query, offset *int) stri
 		sql = fmt.Sprintf("%s %s", grammarSQL.WrapUnion(sql), grammarSQL.CompileUnions(query, query.Unions, offset))
 	}
 
+	if with != "" {
+		sql = fmt.Sprintf("%s %s", with, sql)
+	}
+
 	// reset columns
 	query.Columns = columns
 	return strings.Trim(sql, " ")
 }
 
+// CompileWith Compile the common table expressions of the query.
+func (grammarSQL MySQL) CompileWith(query *dbal.Query, ctes []dbal.CTE, offset *int) string {
+	return grammarSQL.CompileCTEs(grammarSQL, query, ctes, offset, false)
+}
+
 // CompileLock the lock into SQL.
 func (grammarSQL MySQL) CompileLock(query *dbal.Query, lock interface{}) string {
+	if value, ok := lock.(dbal.Lock); ok {
+		// The unsupported row locking clauses are reported by CheckQuery, skip them here.
+		if grammarSQL.checkLock(value) != nil {
+			return ""
+		}
+
+		// MySQL 8.0+ supports "for share", keep "lock in share mode" for the older versions when it is possible.
+		if value.Type == "share" && len(value.Of) == 0 && value.Option == "" {
+			return "lock in share mode"
+		}
+		return grammarSQL.CompileRowLock(value)
+	}
+
 	lockType, ok := lock.(string)
 	if ok == false {
 		return ""
@@ -85,3 +114,110 @@ func (grammarSQL MySQL) CompileLock(query *dbal.Query, lock interface{}) string
 	}
 	return ""
 }
+
+// CheckQuery Check if the features used by the select statement are supported by the database engine.
+func (grammarSQL MySQL) CheckQuery(query *dbal.Query) error {
+	err := grammarSQL.checkCTEs(query.CTEs)
+	if err != nil {
+		return err
+	}
+
+	err = grammarSQL.checkJoins(query.Joins)
+	if err != nil {
+		return err
+	}
+
+	err = grammarSQL.checkWindows(query)
+	if err != nil {
+		return err
+	}
+	if lock, ok := query.Lock.(dbal.Lock); ok {
+		return grammarSQL.checkLock(lock)
+	}
+	return nil
+}
+
+// checkCTEs the common table expressions require MySQL 8.0+
+func (grammarSQL MySQL) checkCTEs(ctes []dbal.CTE) error {
+	if len(ctes) == 0 {
+		return nil
+	}
+
+	version, err := grammarSQL.version()
+	if err != nil {
+		return err
+	}
+
+	mysql8, _ := semver.Make("8.0.0")
+	if version.LT(mysql8) {
+		return fmt.Errorf("This database engine does not support the common table expressions, MySQL 8.0+ is required (current: %s)", version.String())
+	}
+	return nil
+}
+
+// checkWindows the window functions require MySQL 8.0+
+func (grammarSQL MySQL) checkWindows(query *dbal.Query) error {
+	if !query.HasWindowFunctions() {
+		return nil
+	}
+
+	version, err := grammarSQL.version()
+	if err != nil {
+		return err
+	}
+
+	mysql8, _ := semver.Make("8.0.0")
+	if version.LT(mysql8) {
+		return fmt.Errorf("This database engine does not support the window functions, MySQL 8.0+ is required (current: %s)", version.String())
+	}
+	return nil
+}
+
+// checkJoins the lateral joins require MySQL 8.0.14+
+func (grammarSQL MySQL) checkJoins(joins []dbal.Join) error {
+	lateral := false
+	for _, join := range joins {
+		lateral = lateral || join.Lateral
+	}
+	if !lateral {
+		return nil
+	}
+
+	version, err := grammarSQL.version()
+	if err != nil {
+		return err
+	}
+
+	mysql8014, _ := semver.Make("8.0.14")
+	if version.LT(mysql8014) {
+		return fmt.Errorf("This database engine does not support lateral joins, MySQL 8.0.14+ is required (current: %s)", version.String())
+	}
+	return nil
+}
+
+// checkLock the "for share" lock, the locking tables and the lock options require MySQL 8.0+
+func (grammarSQL MySQL) checkLock(lock dbal.Lock) error {
+	if lock.Type == "no key update" {
+		return fmt.Errorf("This database engine does not support the for no key update lock")
+	}
+
+	if len(lock.Of) == 0 && lock.Option == "" {
+		return nil
+	}
+
+	version, err := grammarSQL.version()
+	if err != nil {
+		return err
+	}
+
+	mysql8, _ := semver.Make("8.0.0")
+	if version.LT(mysql8) {
+		return fmt.Errorf("This database engine does not support the row locking clause: %s, MySQL 8.0+ is required (current: %s)", grammarSQL.CompileRowLock(lock), version.String())
+	}
+	return nil
+}
+
+// Interpolate Interpolate the bindings into the given SQL, the backslash is the escape character of MySQL string literals.
+func (grammarSQL MySQL) Interpolate(query string, bindings []interface{}) string {
+	return sql.Interpolate(query, bindings, grammarSQL.VAL, false, true)
+}
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/blang/semver/v4"
@@ -34,6 +35,7 @@ func (grammarSQL *MySQL) setup(db *sqlx.DB, config *dbal.Config, option *dbal.Op
 	grammarSQL.DB = db
 	grammarSQL.Config = config
 	grammarSQL.Option = option
+	grammarSQL.Version = &dbal.Version{}
 	cfg, err := mysql.ParseDSN(grammarSQL.Config.DSN)
 	if err != nil {
 		return err
@@ -66,12 +68,29 @@ func (grammarSQL MySQL) NewWithRead(write *sqlx.DB, writeConfig *dbal.Config, re
 	return grammarSQL, nil
 }
 
+// WithContext Create a new grammar interface with the given context, the context will be used for executing the statements.
+func (grammarSQL MySQL) WithContext(ctx context.Context) dbal.Grammar {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	grammarSQL.Context = ctx
+	return grammarSQL
+}
+
+// WithTransaction Create a new grammar interface with the given transaction, the statements will be executed within the transaction.
+func (grammarSQL MySQL) WithTransaction(tx *sqlx.Tx) dbal.Grammar {
+	grammarSQL.Tx = tx
+	return grammarSQL
+}
+
 // OnConnected the event will be triggered when db server was connected
 func (grammarSQL MySQL) OnConnected() error {
 	version, err := grammarSQL.GetVersion()
 	if err != nil {
 		return err
 	}
+	grammarSQL.CacheVersion(version)
+
 	ver577, err := semver.Make("5.7.7")
 	if err != nil {
 		return err
@@ -105,3 +124,11 @@ func New(opts ...sql.Option) dbal.Grammar {
 	}
 	return my
 }
+
+// version get the version cached when the db server was connected, the version is queried if it has not been cached.
+func (grammarSQL MySQL) version() (*dbal.Version, error) {
+	if version := grammarSQL.CachedVersion(); version != nil {
+		return version, nil
+	}
+	return grammarSQL.GetVersion()
+}
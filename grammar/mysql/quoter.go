@@ -3,6 +3,7 @@ package mysql
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/yaoapp/xun/grammar/sql"
 	"github.com/yaoapp/xun/utils"
@@ -23,22 +24,34 @@ func (quoter *Quoter) ID(name string) string {
 
 // VAL quoting query value ( 'value' )
 func (quoter *Quoter) VAL(v interface{}) string {
+	v = sql.LiteralValue(v)
+	if number, ok := sql.LiteralNumber(v); ok {
+		return number
+	}
+
 	input := ""
 	switch v.(type) {
-	case *string:
-		input = fmt.Sprintf("%s", utils.StringVal(v.(*string)))
-		break
-	case string:
-		input = fmt.Sprintf("%s", v)
-		break
-	case int, int16, int32, int64, float64, float32:
-		input = fmt.Sprintf("%d", v)
-		break
+	case nil:
+		return "NULL"
+	case bool:
+		return utils.GetIF(v.(bool), "1", "0").(string)
+	case []byte:
+		return fmt.Sprintf("X'%x'", v)
+	case time.Time:
+		input = v.(time.Time).Format(sql.TimeFormat)
 	default:
-		input = fmt.Sprintf("%v", v)
+		input = fmt.Sprintf("%s", v)
 	}
-	input = strings.ReplaceAll(input, "'", "\\'")
-	input = strings.ReplaceAll(input, "\n", "")
-	input = strings.ReplaceAll(input, "\r", "")
-	return "'" + input + "'"
+
+	return "'" + escaper.Replace(input) + "'"
 }
+
+// escaper the special characters of the MySQL string literals
+var escaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"'", "\\'",
+	"\x00", "\\0",
+	"\n", "\\n",
+	"\r", "\\r",
+	"\x1a", "\\Z",
+)
@@ -28,15 +28,13 @@ func (grammarSQL MySQL) CompileUpsert(query *dbal.Query, columns []interface{},
 			segments = append(segments, fmt.Sprintf("%s=values(%s)", grammarSQL.Wrap(column), grammarSQL.Wrap(column)))
 		}
 	} else if kind == reflect.Map {
+		values := map[string]interface{}{}
 		for _, key := range update.MapKeys() {
-			column := fmt.Sprintf("%v", key)
-			value := update.MapIndex(key).Interface()
-			segments = append(segments, fmt.Sprintf("%s=%s", grammarSQL.Wrap(column), grammarSQL.Parameter(value, offset)))
-			if !dbal.IsExpression(value) {
-				bindings = append(bindings, value)
-				offset++
-			}
+			values[fmt.Sprintf("%v", key)] = update.MapIndex(key).Interface()
 		}
+		columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
+		segments = append(segments, columns)
+		bindings = append(bindings, columnsBindings...)
 	}
 
 	return fmt.Sprintf("%s %s", sql, strings.Join(segments, ", ")), bindings
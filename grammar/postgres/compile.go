@@ -7,6 +7,7 @@ import (
 
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/grammar/sql"
 )
 
 // CompileSelect Compile a select query into SQL.
@@ -32,6 +33,10 @@ func (grammarSQL Postgres) CompileSelectOffset(query *dbal.Query, offset *int) s
 		return grammarSQL.CompileUnionAggregate(query)
 	}
 
+	// The common table expressions should be compiled first, because
+	// their bindings are placed before the bindings of the select.
+	with := grammarSQL.CompileWith(query, query.CTEs, offset)
+
 	sqls := map[string]string{}
 
 	// If the query does not have any columns set, we'll set the columns to the
@@ -52,13 +57,14 @@ func (grammarSQL Postgres) CompileSelectOffset(query *dbal.Query, offset *int) s
 	sqls["wheres"] = grammarSQL.CompileWheres(query, query.Wheres, offset)
 	sqls["groups"] = grammarSQL.CompileGroups(query, query.Groups, offset)
 	sqls["havings"] = grammarSQL.CompileHavings(query, query.Havings, offset)
+	sqls["windows"] = grammarSQL.CompileWindows(query, query.Windows, offset)
 	sqls["orders"] = grammarSQL.CompileOrders(query, query.Orders, offset)
 	sqls["limit"] = grammarSQL.CompileLimit(query, query.Limit, offset)
 	sqls["offset"] = grammarSQL.CompileOffset(query, query.Offset)
 	sqls["lock"] = grammarSQL.CompileLock(query, query.Lock)
 
 	sql := ""
-	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "orders", "limit", "offset", "lock"} {
+	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "windows", "orders", "limit", "offset", "lock"} {
 		segment, has := sqls[name]
 		if has && segment != "" {
 			sql = sql + segment + " "
@@ -70,11 +76,33 @@ func (grammarSQL Postgres) CompileSelectOffset(query *dbal.Query, offset *int) s
 		sql = fmt.Sprintf("%s %s", grammarSQL.WrapUnion(sql), grammarSQL.CompileUnions(query, query.Unions, offset))
 	}
 
+	if with != "" {
+		sql = fmt.Sprintf("%s %s", with, sql)
+	}
+
 	// reset columns
 	query.Columns = columns
 	return strings.Trim(sql, " ")
 }
 
+// CompileWith Compile the common table expressions of the query.
+func (grammarSQL Postgres) CompileWith(query *dbal.Query, ctes []dbal.CTE, offset *int) string {
+	return grammarSQL.CompileCTEs(grammarSQL, query, ctes, offset, true)
+}
+
+// CompileOrders Compile the "order by" portions of the query.
+func (grammarSQL Postgres) CompileOrders(query *dbal.Query, orders []dbal.Order, bindingOffset *int) string {
+	return grammarSQL.CompileOrderBy(grammarSQL, query, orders, bindingOffset)
+}
+
+// CompileReturning Compile the "returning" portion of the insert, update and delete statements.
+func (grammarSQL Postgres) CompileReturning(query *dbal.Query, columns []interface{}) (string, error) {
+	if len(columns) == 0 {
+		return "returning *", nil
+	}
+	return fmt.Sprintf("returning %s", grammarSQL.Columnize(columns)), nil
+}
+
 // CompileColumns Compile the "select *" portion of the query.
 func (grammarSQL Postgres) CompileColumns(query *dbal.Query, columns []interface{}, bindingOffset *int) string {
 
@@ -92,7 +120,7 @@ func (grammarSQL Postgres) CompileColumns(query *dbal.Query, columns []interface
 		sql = "select distinct"
 	}
 
-	sql = fmt.Sprintf("%s %s", sql, grammarSQL.Columnize(columns))
+	sql = fmt.Sprintf("%s %s", sql, grammarSQL.Columnize(grammarSQL.CompileWindowColumns(query, columns, bindingOffset)))
 
 	for _, col := range columns {
 		switch col.(type) {
@@ -193,8 +221,108 @@ func (grammarSQL Postgres) WhereDateBased(typ string, query *dbal.Query, where d
 	return fmt.Sprintf("extract(%s from %s)%s%s", typ, grammarSQL.Wrap(where.Column), where.Operator, value)
 }
 
+// WhereJSONContains Compile a "where JSON contains" clause.
+func (grammarSQL Postgres) WhereJSONContains(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	not := ""
+	if where.Not {
+		not = "not "
+	}
+	value := grammarSQL.WhereJSONValue(where, bindingOffset)
+	// ("options"->'languages')::jsonb @> $1
+	return fmt.Sprintf("%s%s @> %s", not, grammarSQL.WrapJSONB(where.Column), value)
+}
+
+// WhereJSONLength Compile a "where JSON length" clause.
+func (grammarSQL Postgres) WhereJSONLength(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	value := grammarSQL.WhereJSONValue(where, bindingOffset)
+	// jsonb_array_length(("options"->'languages')::jsonb) > $1
+	return fmt.Sprintf("jsonb_array_length(%s) %s %s", grammarSQL.WrapJSONB(where.Column), where.Operator, value)
+}
+
+// WhereJSONBoolean Compile a "where JSON boolean" clause.
+func (grammarSQL Postgres) WhereJSONBoolean(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	value := grammarSQL.WhereJSONValue(where, bindingOffset)
+	if dbal.IsExpression(where.Value) {
+		value = fmt.Sprintf("'%s'::jsonb", value)
+	}
+	// ("options"->'enabled')::jsonb = 'true'::jsonb
+	return fmt.Sprintf("%s %s %s", grammarSQL.WrapJSONB(where.Column), where.Operator, value)
+}
+
+// WrapJSONB Wrap the given JSON selector as a jsonb value. eg: ("options"->'languages')::jsonb
+func (grammarSQL Postgres) WrapJSONB(column interface{}) string {
+	field, path := grammarSQL.WrapJSONFieldAndPath(fmt.Sprintf("%v", column))
+	if path != "" {
+		field = fmt.Sprintf("%s->%s", field, path)
+	}
+	return fmt.Sprintf("(%s)::jsonb", field)
+}
+
+// WhereFullText Compile a "where fulltext" clause.
+func (grammarSQL Postgres) WhereFullText(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	*bindingOffset = *bindingOffset + where.Offset
+	fulltext := where.Column.(dbal.FullText)
+	vector, tsquery := grammarSQL.CompileFullTextVector(fulltext, grammarSQL.Parameter(where.Value, *bindingOffset))
+	// (to_tsvector('english', "title") || to_tsvector('english', "body")) @@ plainto_tsquery('english', $1)
+	return fmt.Sprintf("%s @@ %s", vector, tsquery)
+}
+
+// CompileFullTextOrder Compile the full text search relevance order into SQL.
+func (grammarSQL Postgres) CompileFullTextOrder(query *dbal.Query, fulltext dbal.FullText, value interface{}, offset int) (string, []interface{}) {
+	vector, tsquery := grammarSQL.CompileFullTextVector(fulltext, grammarSQL.Parameter(value, offset+1))
+	// ts_rank(to_tsvector('english', "title"), plainto_tsquery('english', $1)) desc
+	return fmt.Sprintf("ts_rank(%s, %s) desc", vector, tsquery), []interface{}{value}
+}
+
+// CompileFullTextVector Compile the tsvector and the tsquery of the full text search.
+// the mode option could be "plain"(default), "websearch", "phrase" or "boolean", the language option default is "english"
+func (grammarSQL Postgres) CompileFullTextVector(fulltext dbal.FullText, value string) (string, string) {
+	language := "english"
+	if lang, ok := fulltext.Options["language"].(string); ok && lang != "" {
+		language = lang
+	}
+	language = grammarSQL.VAL(language)
+
+	vectors := []string{}
+	for _, column := range fulltext.Columns {
+		vectors = append(vectors, fmt.Sprintf("to_tsvector(%s, %s)", language, grammarSQL.Wrap(column)))
+	}
+	vector := strings.Join(vectors, " || ")
+	if len(vectors) > 1 {
+		vector = fmt.Sprintf("(%s)", vector)
+	}
+
+	function := "plainto_tsquery"
+	switch fulltext.Options["mode"] {
+	case "websearch":
+		function = "websearch_to_tsquery"
+	case "phrase":
+		function = "phraseto_tsquery"
+	case "boolean":
+		function = "to_tsquery"
+	}
+	return vector, fmt.Sprintf("%s(%s, %s)", function, language, value)
+}
+
+// CompileRandom Compile the random statement into SQL. (the seed is not supported, it is reported by CheckQuery)
+func (grammarSQL Postgres) CompileRandom(seed string) string {
+	return "RANDOM()"
+}
+
+// CheckQuery Check if the features used by the select statement are supported by the database engine.
+func (grammarSQL Postgres) CheckQuery(query *dbal.Query) error {
+	if query.HasRandomSeed() {
+		return fmt.Errorf("This database engine does not support the seed of the random order")
+	}
+	return nil
+}
+
 // CompileLock the lock into SQL.
 func (grammarSQL Postgres) CompileLock(query *dbal.Query, lock interface{}) string {
+	if value, ok := lock.(dbal.Lock); ok {
+		return grammarSQL.CompileRowLock(value)
+	}
+
 	lockType, ok := lock.(string)
 	if ok == false {
 		return ""
@@ -205,3 +333,8 @@ func (grammarSQL Postgres) CompileLock(query *dbal.Query, lock interface{}) stri
 	}
 	return ""
 }
+
+// Interpolate Interpolate the bindings into the given SQL using the "$n" placeholders.
+func (grammarSQL Postgres) Interpolate(query string, bindings []interface{}) string {
+	return sql.Interpolate(query, bindings, grammarSQL.VAL, true, false)
+}
@@ -17,13 +17,17 @@ func (grammarSQL Postgres) CompileDelete(query *dbal.Query) (string, []interface
 	bindings := []interface{}{}
 	table := grammarSQL.WrapTable(query.From)
 
-	alias := query.From.Alias
-	if alias != "" {
-		query.Columns = []interface{}{fmt.Sprintf("%s.ctid", alias)}
-	} else {
-		query.Columns = []interface{}{"ctid"}
+	// delete from "users" using "posts" where ("posts"."user_id" = "users"."id") and ("posts"."vote" > $1)
+	if grammarSQL.IsJoinsAsFrom(query) {
+		tables, conditions, _, _ := grammarSQL.CompileJoinsAsFrom(query, &offset)
+		bindings = append(bindings, query.GetBindings("join")...)
+		conditions = grammarSQL.MergeJoinsAsFromWheres(conditions, grammarSQL.CompileWheres(query, query.Wheres, &offset))
+		bindings = append(bindings, query.GetBindings("where")...)
+		return fmt.Sprintf("delete from %s using %s %s", table, tables, conditions), bindings
 	}
 
+	query.Columns = []interface{}{fmt.Sprintf("%s.ctid", grammarSQL.FromReference(query))}
+
 	selectSQL := grammarSQL.CompileSelectOffset(query, &offset)
 
 	bindings = append(bindings, query.GetBindings()...)
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/grammar/sql"
+)
+
+// CompileExplain Compile an explain statement of the given select SQL. eg: explain (format json) select * from "users"
+// The analyze is opt-in, the select statement will be executed only when the analyze option is true.
+func (grammarSQL Postgres) CompileExplain(query string, options map[string]interface{}) string {
+	analyze, _ := options["analyze"].(bool)
+	buffers, ok := options["buffers"].(bool)
+	if !ok {
+		buffers = analyze
+	}
+
+	if !analyze {
+		return fmt.Sprintf("explain (format json) %s", query)
+	} else if !buffers {
+		return fmt.Sprintf("explain (analyze, format json) %s", query)
+	}
+	return fmt.Sprintf("explain (analyze, buffers, format json) %s", query)
+}
+
+// ProcessExplain Normalize the results of the explain statement.
+func (grammarSQL Postgres) ProcessExplain(rows []map[string]interface{}, options map[string]interface{}) (*dbal.Plan, error) {
+	plan := &dbal.Plan{Nodes: []*dbal.PlanNode{}, Raw: rows}
+	if len(rows) != 1 {
+		return nil, fmt.Errorf("the explain statement should return one row, %d rows returned", len(rows))
+	}
+
+	// eg: [{"Plan": {"Node Type": "Seq Scan", "Relation Name": "users", "Total Cost": 10.5, "Plans": [...]}, "Execution Time": 0.02}]
+	raw, err := sql.ExplainJSON(rows[0]["QUERY PLAN"])
+	if err != nil {
+		return nil, err
+	}
+	plan.Raw = raw
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the explain statement returns an unexpected output %v", raw)
+	}
+
+	for _, item := range items {
+		values, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if node, ok := values["Plan"].(map[string]interface{}); ok {
+			root := grammarSQL.explainNode(node)
+			plan.Cost = plan.Cost + root.Cost
+			plan.Nodes = append(plan.Nodes, root)
+		}
+	}
+	return plan, nil
+}
+
+// explainNode convert the JSON plan node to the PlanNode
+func (grammarSQL Postgres) explainNode(values map[string]interface{}) *dbal.PlanNode {
+	node := &dbal.PlanNode{
+		Operation: sql.ExplainString(values["Node Type"]),
+		Table:     sql.ExplainString(values["Relation Name"]),
+		Index:     sql.ExplainString(values["Index Name"]),
+		Rows:      sql.ExplainFloat(values["Plan Rows"]),
+		Cost:      sql.ExplainFloat(values["Total Cost"]),
+		FullScan:  sql.ExplainString(values["Node Type"]) == "Seq Scan",
+		Detail:    sql.ExplainString(values["Filter"]),
+		Children:  []*dbal.PlanNode{},
+	}
+
+	if children, ok := values["Plans"].([]interface{}); ok {
+		for _, child := range children {
+			if child, ok := child.(map[string]interface{}); ok {
+				node.Children = append(node.Children, grammarSQL.explainNode(child))
+			}
+		}
+	}
+	return node
+}
@@ -21,9 +21,9 @@ func (grammarSQL Postgres) CompileInsertGetID(query *dbal.Query, columns []inter
 }
 
 // ProcessInsertGetID Execute an insert and get ID statement and return the id
-func (grammarSQL Postgres) ProcessInsertGetID(sql string, bindings []interface{}, sequence string) (int64, error) {
+func (grammarSQL Postgres) ProcessInsertGetID(db dbal.Executor, sql string, bindings []interface{}, sequence string) (int64, error) {
 	var seq int64
-	err := grammarSQL.DB.Get(&seq, sql, bindings...)
+	err := db.GetContext(grammarSQL.Context, &seq, sql, bindings...)
 	if err != nil {
 		return 0, err
 	}
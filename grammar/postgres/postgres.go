@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"path/filepath"
@@ -86,6 +87,21 @@ func (grammarSQL Postgres) NewWithRead(write *sqlx.DB, writeConfig *dbal.Config,
 	return grammarSQL, nil
 }
 
+// WithContext Create a new grammar interface with the given context, the context will be used for executing the statements.
+func (grammarSQL Postgres) WithContext(ctx context.Context) dbal.Grammar {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	grammarSQL.Context = ctx
+	return grammarSQL
+}
+
+// WithTransaction Create a new grammar interface with the given transaction, the statements will be executed within the transaction.
+func (grammarSQL Postgres) WithTransaction(tx *sqlx.Tx) dbal.Grammar {
+	grammarSQL.Tx = tx
+	return grammarSQL
+}
+
 // New Create a new mysql grammar inteface
 func New(opts ...sql.Option) dbal.Grammar {
 	pg := Postgres{
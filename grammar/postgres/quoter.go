@@ -2,13 +2,19 @@ package postgres
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/grammar/sql"
 	"github.com/yaoapp/xun/utils"
 )
 
+// jsonPathIndex the array index suffix of the JSON path segment. eg: languages[0]
+var jsonPathIndex = regexp.MustCompile(`^(.*?)((\[\d+\])+)$`)
+
 // Quoter the database quoting query text SQL type
 type Quoter struct {
 	sql.Quoter
@@ -24,24 +30,26 @@ func (quoter Quoter) ID(name string) string {
 
 // VAL quoting query value ( 'value' )
 func (quoter Quoter) VAL(v interface{}) string {
+	v = sql.LiteralValue(v)
+	if number, ok := sql.LiteralNumber(v); ok {
+		return number
+	}
+
 	input := ""
 	switch v.(type) {
-	case *string:
-		input = fmt.Sprintf("%s", utils.StringVal(v.(*string)))
-		break
-	case string:
-		input = fmt.Sprintf("%s", v)
-		break
-	case int, int16, int32, int64, float64, float32:
-		input = fmt.Sprintf("%d", v)
-		break
+	case nil:
+		return "NULL"
+	case bool:
+		return utils.GetIF(v.(bool), "true", "false").(string)
+	case []byte:
+		return fmt.Sprintf("'\\x%x'::bytea", v)
+	case time.Time:
+		input = v.(time.Time).Format(sql.TimeFormat)
 	default:
-		input = fmt.Sprintf("%v", v)
+		input = fmt.Sprintf("%s", v)
 	}
-	input = strings.ReplaceAll(input, "'", "\\'")
-	input = strings.ReplaceAll(input, "\n", "")
-	input = strings.ReplaceAll(input, "\r", "")
-	return "'" + input + "'"
+
+	return "'" + strings.ReplaceAll(input, "'", "''") + "'"
 }
 
 // Wrap a value in keyword identifiers.
@@ -73,6 +81,17 @@ func (quoter *Quoter) WrapAliasedValue(value string) string {
 	if value == "*" {
 		return "*"
 	}
+
+	// If the value is a JSON selector, we will wrap it with the JSON operators.
+	// eg: options->language as lang
+	if dbal.IsJSONSelector(value) {
+		name := dbal.NewName(value)
+		if name.As() != "" {
+			return fmt.Sprintf("%s as %s", quoter.WrapJSONSelector(name.Name), quoter.ID(name.As()))
+		}
+		return quoter.WrapJSONSelector(name.Name)
+	}
+
 	if strings.Contains(value, ".") {
 		arrs := strings.Split(value, ".")
 		table := arrs[0]
@@ -87,6 +106,54 @@ func (quoter *Quoter) WrapAliasedValue(value string) string {
 	return fmt.Sprintf("%s", quoter.ID(name.Fullname()))
 }
 
+// WrapJSONSelector Wrap the given JSON selector.
+// eg: options->dining->meal: "options"->'dining'->'meal', options->dining->>meal: "options"->'dining'->>'meal'
+func (quoter *Quoter) WrapJSONSelector(value string) string {
+	field, path, unquote := dbal.ParseJSONSelector(value)
+	field = quoter.WrapAliasedValue(field)
+	segments := quoter.WrapJSONPathSegments(path)
+	if len(segments) == 0 {
+		return field
+	}
+
+	if !unquote {
+		return strings.Join(append([]string{field}, segments...), "->")
+	}
+	last := segments[len(segments)-1]
+	segments = append([]string{field}, segments[:len(segments)-1]...)
+	return fmt.Sprintf("%s->>%s", strings.Join(segments, "->"), last)
+}
+
+// WrapJSONFieldAndPath Split the given JSON selector into the wrapped field and the wrapped path. eg: "options", 'dining'->'meal'
+func (quoter *Quoter) WrapJSONFieldAndPath(value string) (string, string) {
+	field, path, _ := dbal.ParseJSONSelector(value)
+	return quoter.WrapAliasedValue(field), strings.Join(quoter.WrapJSONPathSegments(path), "->")
+}
+
+// WrapJSONPathSegments Wrap the given JSON path segments, the array indexes will not be quoted. eg: 'languages', 0
+func (quoter *Quoter) WrapJSONPathSegments(path []string) []string {
+	segments := []string{}
+	for _, segment := range path {
+		matches := jsonPathIndex.FindStringSubmatch(segment)
+		if len(matches) > 0 {
+			if matches[1] != "" {
+				segments = append(segments, quoter.VAL(matches[1]))
+			}
+			for _, index := range strings.Split(strings.Trim(matches[2], "[]"), "][") {
+				segments = append(segments, index)
+			}
+			continue
+		}
+
+		if _, err := strconv.Atoi(segment); err == nil {
+			segments = append(segments, segment)
+			continue
+		}
+		segments = append(segments, quoter.VAL(segment))
+	}
+	return segments
+}
+
 // WrapTable Wrap a table in keyword identifiers.
 func (quoter *Quoter) WrapTable(value interface{}) string {
 	switch value.(type) {
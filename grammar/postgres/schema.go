@@ -16,7 +16,7 @@ func (grammarSQL Postgres) GetVersion() (*dbal.Version, error) {
 	sql := fmt.Sprintf("SELECT VERSION()")
 	// defer logger.Debug(logger.RETRIEVE, sql).TimeCost(time.Now())
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &rows, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +72,7 @@ func (grammarSQL Postgres) GetTables() ([]string, error) {
 	)
 	defer log.Debug(sql)
 	tables := []string{}
-	err := grammarSQL.DB.Select(&tables, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &tables, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +89,7 @@ func (grammarSQL Postgres) TableExists(name string) (bool, error) {
 	)
 	defer log.Debug(sql)
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &rows, sql)
 	if err != nil {
 		return false, err
 	}
@@ -112,7 +112,7 @@ func (grammarSQL Postgres) CreateType(table *dbal.Table, types map[string][]stri
 	END $$;
 	`, table.SchemaName, name, typ)
 		defer log.Debug(typeSQL)
-		_, err := grammarSQL.DB.Exec(typeSQL)
+		_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context, typeSQL)
 		if err != nil {
 			return err
 		}
@@ -130,6 +130,8 @@ func (grammarSQL Postgres) CreateTable(table *dbal.Table) error {
 	var primary *dbal.Primary = nil
 	columns := []*dbal.Column{}
 	indexes := []*dbal.Index{}
+	foreigns := []*dbal.Foreign{}
+	constraints := []*dbal.Constraint{}
 	cbCommands := []*dbal.Command{}
 	// Commands
 	// The commands must be:
@@ -140,6 +142,8 @@ func (grammarSQL Postgres) CreateTable(table *dbal.Table) error {
 	//    CreateIndex(index *Index) for creating a index
 	//    DropIndex( name string) for  dropping a index
 	//    RenameIndex(old string,new string)  for renaming a index
+	//    CreateForeign(foreign *Foreign) for creating a foreign key
+	//    CreateConstraint(constraint *Constraint) for creating a unique or check constraint
 	for _, command := range table.Commands {
 		switch command.Name {
 		case "AddColumn":
@@ -154,6 +158,14 @@ func (grammarSQL Postgres) CreateTable(table *dbal.Table) error {
 			primary = command.Params[0].(*dbal.Primary)
 			cbCommands = append(cbCommands, command)
 			break
+		case "CreateForeign":
+			foreigns = append(foreigns, command.Params[0].(*dbal.Foreign))
+			cbCommands = append(cbCommands, command)
+			break
+		case "CreateConstraint":
+			constraints = append(constraints, command.Params[0].(*dbal.Constraint))
+			cbCommands = append(cbCommands, command)
+			break
 		}
 	}
 
@@ -166,12 +178,22 @@ func (grammarSQL Postgres) CreateTable(table *dbal.Table) error {
 	if primary != nil {
 		stmts = append(stmts, grammarSQL.SQLAddPrimary(primary))
 	}
+
+	// Foreign keys
+	for _, foreign := range foreigns {
+		stmts = append(stmts, grammarSQL.SQLAddForeign(foreign))
+	}
+
+	// Constraints
+	for _, constraint := range constraints {
+		stmts = append(stmts, grammarSQL.SQLAddConstraint(constraint))
+	}
 	sql = sql + strings.Join(stmts, ",\n")
 	sql = sql + fmt.Sprintf("\n)")
 
 	// Create table
 	defer log.Debug(sql)
-	_, err = grammarSQL.DB.Exec(sql)
+	_, err = grammarSQL.Executor().ExecContext(grammarSQL.Context, sql)
 	if err != nil {
 		return err
 	}
@@ -235,7 +257,7 @@ func (grammarSQL Postgres) createTableCreateIndex(table *dbal.Table, indexes []*
 	if len(indexStmts) > 0 {
 		sql := strings.Join(indexStmts, ";\n")
 		defer log.Debug(sql)
-		_, err := grammarSQL.DB.Exec(sql)
+		_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context, sql)
 		return err
 	}
 	return nil
@@ -245,7 +267,7 @@ func (grammarSQL Postgres) createTableAddComment(table *dbal.Table, commentStmts
 	if len(commentStmts) > 0 {
 		sql := strings.Join(commentStmts, ";\n")
 		defer log.Debug(sql)
-		_, err := grammarSQL.DB.Exec(sql)
+		_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context, sql)
 		return err
 	}
 	return nil
@@ -255,7 +277,7 @@ func (grammarSQL Postgres) createTableAddComment(table *dbal.Table, commentStmts
 func (grammarSQL Postgres) RenameTable(old string, new string) error {
 	sql := fmt.Sprintf("ALTER TABLE %s RENAME TO %s", grammarSQL.ID(old), grammarSQL.ID(new))
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context, sql)
 	return err
 }
 
@@ -279,6 +301,14 @@ func (grammarSQL Postgres) GetTable(name string) (*dbal.Table, error) {
 	if err != nil {
 		return nil, err
 	}
+	foreigns, err := grammarSQL.GetForeignListing(table.SchemaName, table.TableName)
+	if err != nil {
+		return nil, err
+	}
+	constraints, err := grammarSQL.GetTableConstraintListing(table.SchemaName, table.TableName)
+	if err != nil {
+		return nil, err
+	}
 
 	primaryKeyName := ""
 
@@ -288,12 +318,32 @@ func (grammarSQL Postgres) GetTable(name string) (*dbal.Table, error) {
 		table.PushColumn(column)
 	}
 
+	// attaching constraints
+	for _, c := range constraints {
+		if !table.HasConstraint(c.Name) {
+			constraint := *c
+			constraint.Table = table
+			constraint.Args = []string{}
+			constraint.Columns = []string{}
+			table.PushConstraint(&constraint)
+		}
+		if c.ColumnName != "" {
+			constraint := table.ConstraintMap[c.Name]
+			constraint.Columns = append(constraint.Columns, c.ColumnName)
+		}
+	}
+
 	// attaching indexes
 	for i := range indexes {
 		idx := indexes[i]
 		if !table.HasColumn(idx.ColumnName) {
 			return nil, fmt.Errorf("the column %s does not exists", idx.ColumnName)
 		}
+
+		// the unique index of the unique constraint
+		if constraint := table.GetConstraint(idx.Name); constraint != nil && constraint.Type == "UNIQUE" {
+			continue
+		}
 		column := table.ColumnMap[idx.ColumnName]
 		if !table.HasIndex(idx.Name) {
 			index := *idx
@@ -325,6 +375,18 @@ func (grammarSQL Postgres) GetTable(name string) (*dbal.Table, error) {
 		}
 	}
 
+	// attaching foreign keys
+	for _, fk := range foreigns {
+		if !table.HasForeign(fk.Name) {
+			foreign := *fk
+			foreign.Table = table
+			foreign.Columns = []string{}
+			foreign.ReferencedColumns = []string{}
+			table.PushForeign(&foreign)
+		}
+		table.ForeignMap[fk.Name].AddColumn(fk.ColumnName, fk.ReferencedColumnName)
+	}
+
 	return table, nil
 }
 
@@ -344,6 +406,10 @@ func (grammarSQL Postgres) AlterTable(table *dbal.Table) error {
 	//    CreateIndex(index *Index) for creating a index
 	//    DropIndex(name string) for  dropping a index
 	//    RenameIndex(old string,new string)  for renaming a index
+	//    CreateForeign(foreign *Foreign) for creating a foreign key
+	//    DropForeign(name string) for dropping a foreign key
+	//    CreateConstraint(constraint *Constraint) for creating a unique or check constraint
+	//    DropConstraint(name string, typ string) for dropping a unique or check constraint
 	for _, command := range table.Commands {
 		switch command.Name {
 		case "AddColumn":
@@ -373,6 +439,18 @@ func (grammarSQL Postgres) AlterTable(table *dbal.Table) error {
 		case "DropPrimary":
 			grammarSQL.alterTableDropPrimary(table, command, sql, &stmts, &errs)
 			break
+		case "CreateForeign":
+			grammarSQL.alterTableCreateForeign(table, command, sql, &stmts, &errs)
+			break
+		case "DropForeign":
+			grammarSQL.alterTableDropForeign(table, command, sql, &stmts, &errs)
+			break
+		case "CreateConstraint":
+			grammarSQL.alterTableCreateConstraint(table, command, sql, &stmts, &errs)
+			break
+		case "DropConstraint":
+			grammarSQL.alterTableDropConstraint(table, command, sql, &stmts, &errs)
+			break
 		}
 	}
 
@@ -547,9 +625,53 @@ func (grammarSQL Postgres) alterTableRenameIndex(table *dbal.Table, command *dba
 	command.Callback(err)
 }
 
+func (grammarSQL Postgres) alterTableCreateForeign(table *dbal.Table, command *dbal.Command, sql string, stmts *[]string, errs *[]error) {
+	foreign := command.Params[0].(*dbal.Foreign)
+	stmt := "ADD " + grammarSQL.SQLAddForeign(foreign)
+	*stmts = append(*stmts, sql+stmt)
+	err := grammarSQL.ExecSQL(table, sql+stmt)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("CreateForeign: %s", err))
+	}
+	command.Callback(err)
+}
+
+func (grammarSQL Postgres) alterTableDropForeign(table *dbal.Table, command *dbal.Command, sql string, stmts *[]string, errs *[]error) {
+	name := command.Params[0].(string)
+	stmt := fmt.Sprintf("DROP CONSTRAINT %s", grammarSQL.ID(name))
+	*stmts = append(*stmts, sql+stmt)
+	err := grammarSQL.ExecSQL(table, sql+stmt)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("DropForeign: %s", err))
+	}
+	command.Callback(err)
+}
+
+func (grammarSQL Postgres) alterTableCreateConstraint(table *dbal.Table, command *dbal.Command, sql string, stmts *[]string, errs *[]error) {
+	constraint := command.Params[0].(*dbal.Constraint)
+	stmt := "ADD " + grammarSQL.SQLAddConstraint(constraint)
+	*stmts = append(*stmts, sql+stmt)
+	err := grammarSQL.ExecSQL(table, sql+stmt)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("CreateConstraint: %s", err))
+	}
+	command.Callback(err)
+}
+
+func (grammarSQL Postgres) alterTableDropConstraint(table *dbal.Table, command *dbal.Command, sql string, stmts *[]string, errs *[]error) {
+	name := fmt.Sprintf("%s_%s", table.TableName, command.Params[0])
+	stmt := fmt.Sprintf("DROP CONSTRAINT %s", grammarSQL.ID(name))
+	*stmts = append(*stmts, sql+stmt)
+	err := grammarSQL.ExecSQL(table, sql+stmt)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("DropConstraint: %s", err))
+	}
+	command.Callback(err)
+}
+
 // ExecSQL execute sql then update table structure
 func (grammarSQL Postgres) ExecSQL(table *dbal.Table, sql string) error {
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context, sql)
 	if err != nil {
 		return err
 	}
@@ -680,7 +802,7 @@ func (grammarSQL Postgres) GetIndexListing(dbName string, tableName string) ([]*
 	)
 	defer log.Debug(sql)
 	indexes := []*dbal.Index{}
-	err := grammarSQL.DB.Select(&indexes, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &indexes, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -700,6 +822,106 @@ func (grammarSQL Postgres) GetIndexListing(dbName string, tableName string) ([]*
 	return indexes, nil
 }
 
+// GetForeignListing get a table foreign keys structure (one row per column)
+func (grammarSQL Postgres) GetForeignListing(dbName string, tableName string) ([]*dbal.Foreign, error) {
+	actions := `CASE %s
+			WHEN 'a' THEN 'NO ACTION'
+			WHEN 'r' THEN 'RESTRICT'
+			WHEN 'c' THEN 'CASCADE'
+			WHEN 'n' THEN 'SET NULL'
+			WHEN 'd' THEN 'SET DEFAULT'
+			ELSE ''
+		END`
+	selectColumns := []string{
+		"n.nspname AS db_name",
+		"t.relname AS table_name",
+		"c.conname AS foreign_name",
+		"a.attname AS column_name",
+		"ft.relname AS referenced_table_name",
+		"fa.attname AS referenced_column_name",
+		fmt.Sprintf(actions, "c.confdeltype") + " AS on_delete",
+		fmt.Sprintf(actions, "c.confupdtype") + " AS on_update",
+	}
+	sql := fmt.Sprintf(`
+			SELECT %s
+			FROM pg_constraint c
+			INNER JOIN pg_class t ON t.oid = c.conrelid
+			INNER JOIN pg_namespace n ON n.oid = t.relnamespace
+			INNER JOIN pg_class ft ON ft.oid = c.confrelid
+			CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, fattnum, seq)
+			INNER JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+			INNER JOIN pg_attribute fa ON fa.attrelid = c.confrelid AND fa.attnum = k.fattnum
+			WHERE
+				c.contype = 'f'
+				AND n.nspname = %s
+				AND t.relname = %s
+			ORDER BY
+				c.conname, k.seq
+			`,
+		strings.Join(selectColumns, ","),
+		grammarSQL.VAL(dbName),
+		grammarSQL.VAL(tableName),
+	)
+	defer log.Debug(sql)
+	foreigns := []*dbal.Foreign{}
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &foreigns, sql)
+	if err != nil {
+		return nil, err
+	}
+	return foreigns, nil
+}
+
+// GetTableConstraintListing get a table unique and check constraints structure (one row per column of the unique constraints)
+// The names of the constraints are prefixed with the table name, the prefix will be removed.
+func (grammarSQL Postgres) GetTableConstraintListing(dbName string, tableName string) ([]*dbal.Constraint, error) {
+	selectColumns := []string{
+		"n.nspname AS schema_name",
+		"t.relname AS table_name",
+		"c.conname AS constraint_name",
+		"CASE c.contype WHEN 'u' THEN 'UNIQUE' ELSE 'CHECK' END AS constraint_type",
+		"COALESCE(a.attname, '') AS column_name",
+		"CASE c.contype WHEN 'c' THEN pg_get_constraintdef(c.oid) ELSE '' END AS expression",
+	}
+	sql := fmt.Sprintf(`
+			SELECT %s
+			FROM pg_constraint c
+			INNER JOIN pg_class t ON t.oid = c.conrelid
+			INNER JOIN pg_namespace n ON n.oid = t.relnamespace
+			LEFT JOIN LATERAL unnest(c.conkey) WITH ORDINALITY AS k(attnum, seq) ON c.contype = 'u'
+			LEFT JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+			WHERE
+				c.contype IN ('u', 'c')
+				AND n.nspname = %s
+				AND t.relname = %s
+			ORDER BY
+				c.conname, k.seq
+			`,
+		strings.Join(selectColumns, ","),
+		grammarSQL.VAL(dbName),
+		grammarSQL.VAL(tableName),
+	)
+	defer log.Debug(sql)
+	constraints := []*dbal.Constraint{}
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &constraints, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	// CHECK ((vote >= 0)) -> (vote >= 0)
+	for _, constraint := range constraints {
+		constraint.Name = strings.TrimPrefix(constraint.Name, tableName+"_")
+		if constraint.Type == "CHECK" {
+			expression := strings.TrimSpace(strings.TrimSuffix(constraint.Expression, " NOT VALID"))
+			expression = strings.TrimPrefix(expression, "CHECK ")
+			if strings.HasPrefix(expression, "(") && strings.HasSuffix(expression, ")") {
+				expression = expression[1 : len(expression)-1]
+			}
+			constraint.Expression = expression
+		}
+	}
+	return constraints, nil
+}
+
 // GetColumnListing get a table columns structure
 func (grammarSQL Postgres) GetColumnListing(dbName string, tableName string) ([]*dbal.Column, error) {
 	selectColumns := []string{
@@ -732,7 +954,7 @@ func (grammarSQL Postgres) GetColumnListing(dbName string, tableName string) ([]
 		 	WHEN (COLUMN_DEFAULT ~ 'nextval\(.*_seq') THEN 'auto_increment'
 		 	ELSE ''
 		END as "extra"`,
-		"pg_catalog.col_description(format('%s.%s',table_schema,table_name)::regclass::oid,ordinal_position)  as \"comment\"",
+		"COALESCE(pg_catalog.col_description(format('%s.%s',table_schema,table_name)::regclass::oid,ordinal_position), '')  as \"comment\"",
 	}
 	sql := fmt.Sprintf(`
 			SELECT %s
@@ -745,7 +967,7 @@ func (grammarSQL Postgres) GetColumnListing(dbName string, tableName string) ([]
 	)
 	defer log.Debug(sql)
 	columns := []*dbal.Column{}
-	err := grammarSQL.DB.Select(&columns, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &columns, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -773,7 +995,7 @@ func (grammarSQL Postgres) GetColumnListing(dbName string, tableName string) ([]
 				column.Type = "enum"
 				if _, has := enumOptions[column.TypeName]; !has {
 					optionRange := []string{}
-					err := grammarSQL.DB.Select(&optionRange, fmt.Sprintf("select enum_range(null::%s.%s)", dbName, column.TypeName))
+					err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &optionRange, fmt.Sprintf("select enum_range(null::%s.%s)", dbName, column.TypeName))
 					if err != nil {
 						return nil, err
 					}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
 )
 
 // Upsert Upsert new records or update the existing ones.
@@ -26,7 +28,7 @@ func (grammarSQL Postgres) Upsert(query *dbal.Query, values []xun.R, uniqueBy []
 
 	sql, bindings := grammarSQL.CompileUpsert(query, columns, insertValues, uniqueBy, updateValues)
 	defer log.Debug(sql)
-	return grammarSQL.DB.Exec(sql, bindings...)
+	return grammarSQL.Executor().ExecContext(grammarSQL.Context, sql, bindings...)
 }
 
 // CompileUpsert Upsert new records or update the existing ones.
@@ -38,7 +40,7 @@ func (grammarSQL Postgres) CompileUpsert(query *dbal.Query, columns []interface{
 
 	sql, bindings := grammarSQL.CompileInsert(query, columns, values)
 	sql = fmt.Sprintf("%s on conflict (%s) do update set", sql, grammarSQL.Columnize(uniqueBy))
-	offset := len(bindings) + 1
+	offset := len(bindings)
 
 	update := reflect.ValueOf(updateValues)
 	kind := update.Kind()
@@ -49,15 +51,13 @@ func (grammarSQL Postgres) CompileUpsert(query *dbal.Query, columns []interface{
 			segments = append(segments, fmt.Sprintf("%s=excluded.%s", grammarSQL.Wrap(column), grammarSQL.Wrap(column)))
 		}
 	} else if kind == reflect.Map {
+		values := map[string]interface{}{}
 		for _, key := range update.MapKeys() {
-			column := fmt.Sprintf("%v", key)
-			value := update.MapIndex(key).Interface()
-			segments = append(segments, fmt.Sprintf("%s=%s", grammarSQL.Wrap(column), grammarSQL.Parameter(value, offset)))
-			if !dbal.IsExpression(value) {
-				bindings = append(bindings, value)
-				offset++
-			}
+			values[fmt.Sprintf("%v", key)] = update.MapIndex(key).Interface()
 		}
+		columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
+		segments = append(segments, columns)
+		bindings = append(bindings, columnsBindings...)
 	}
 
 	return fmt.Sprintf("%s %s", sql, strings.Join(segments, ", ")), bindings
@@ -66,24 +66,33 @@ func (grammarSQL Postgres) CompileUpsert(query *dbal.Query, columns []interface{
 // CompileUpdate Compile an update statement into SQL.
 func (grammarSQL Postgres) CompileUpdate(query *dbal.Query, values map[string]interface{}) (string, []interface{}) {
 
-	if len(query.Joins) == 0 && query.Limit < 0 {
-		return grammarSQL.SQL.CompileUpdate(query, values)
-	}
-
 	offset := 0
 	bindings := []interface{}{}
 	table := grammarSQL.WrapTable(query.From)
 
-	columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
-	bindings = append(bindings, columnsBindings...)
+	if len(query.Joins) == 0 && query.Limit < 0 {
+		columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
+		bindings = append(bindings, columnsBindings...)
+		wheres := grammarSQL.CompileWheres(query, query.Wheres, &offset)
+		bindings = append(bindings, query.GetBindings("where")...)
+		return fmt.Sprintf("update %s set %s %s", table, columns, wheres), bindings
+	}
 
-	alias := query.From.Alias
-	if alias != "" {
-		query.Columns = []interface{}{fmt.Sprintf("%s.ctid", alias)}
-	} else {
-		query.Columns = []interface{}{"ctid"}
+	// update "users" set "vote"=$1 from "posts" where ("posts"."user_id" = "users"."id") and ("posts"."vote" > $2)
+	if grammarSQL.IsJoinsAsFrom(query) {
+		columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
+		bindings = append(bindings, columnsBindings...)
+		tables, conditions, _, _ := grammarSQL.CompileJoinsAsFrom(query, &offset)
+		bindings = append(bindings, query.GetBindings("join")...)
+		conditions = grammarSQL.MergeJoinsAsFromWheres(conditions, grammarSQL.CompileWheres(query, query.Wheres, &offset))
+		bindings = append(bindings, query.GetBindings("where")...)
+		return fmt.Sprintf("update %s set %s from %s %s", table, columns, tables, conditions), bindings
 	}
 
+	columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
+	bindings = append(bindings, columnsBindings...)
+	query.Columns = []interface{}{fmt.Sprintf("%s.ctid", grammarSQL.FromReference(query))}
+
 	selectSQL := grammarSQL.CompileSelectOffset(query, &offset)
 
 	bindings = append(bindings, query.GetBindings()...)
@@ -91,3 +100,104 @@ func (grammarSQL Postgres) CompileUpdate(query *dbal.Query, values map[string]in
 
 	return sql, bindings
 }
+
+// CompileUpdateBatch Compile a batch update statement into SQL, the rows are matched by the key column.
+// The values are unioned with an empty selection of the table, so the types of the parameters could be resolved.
+// update "users" set "vote"="batch"."vote" from (select "id", "vote" from "users" where false union all values ($1, $2), ($3, $4)) as "batch" where "users"."id"="batch"."id"
+func (grammarSQL Postgres) CompileUpdateBatch(query *dbal.Query, key string, columns []interface{}, values [][]interface{}) (string, []interface{}) {
+	offset := 0
+	index := grammarSQL.UpdateBatchKeyIndex(key, columns)
+	table := grammarSQL.WrapTable(query.From)
+
+	ref := grammarSQL.FromReference(query)
+
+	sets := []string{}
+	for i, column := range columns {
+		if i == index {
+			continue
+		}
+		name := fmt.Sprintf("%v", column)
+		sets = append(sets, fmt.Sprintf("%s=%s", grammarSQL.Wrap(column), grammarSQL.Wrap(fmt.Sprintf("batch.%s", name))))
+	}
+
+	rows := []string{}
+	bindings := []interface{}{}
+	for _, row := range values {
+		params := []string{}
+		for _, value := range row {
+			if dbal.IsExpression(value) {
+				params = append(params, value.(dbal.Expression).GetValue())
+				continue
+			}
+			offset++
+			params = append(params, grammarSQL.Parameter(value, offset))
+			bindings = append(bindings, value)
+		}
+		rows = append(rows, fmt.Sprintf("(%s)", strings.Join(params, ", ")))
+	}
+
+	sql := fmt.Sprintf(
+		"update %s set %s from (select %s from %s where false union all values %s) as %s where %s=%s",
+		table, strings.Join(sets, ", "), grammarSQL.Columnize(columns), table, strings.Join(rows, ", "),
+		grammarSQL.ID("batch"), grammarSQL.Wrap(fmt.Sprintf("%s.%s", ref, key)), grammarSQL.Wrap(fmt.Sprintf("batch.%s", key)),
+	)
+
+	// the where clauses are compiled into a subquery to avoid the ambiguous column references
+	if len(query.Wheres) > 0 {
+		selection := query.Clone()
+		selection.Columns = []interface{}{key}
+		selectSQL := grammarSQL.CompileSelectOffset(selection, &offset)
+		bindings = append(bindings, selection.GetBindings()...)
+		sql = fmt.Sprintf("%s and %s in (%s)", sql, grammarSQL.Wrap(fmt.Sprintf("%s.%s", ref, key)), selectSQL)
+	}
+	return sql, bindings
+}
+
+// CompileUpdateColumns Compile the columns for an update statement.
+func (grammarSQL Postgres) CompileUpdateColumns(query *dbal.Query, values map[string]interface{}, offset *int) (string, []interface{}) {
+	return grammarSQL.CompileUpdateValues(grammarSQL, query, values, offset)
+}
+
+// CompileJSONUpdateColumn Compile the JSON selectors of a column for an update statement.
+// "options"=jsonb_set(jsonb_set(coalesce("options", '{}')::jsonb, '{"enabled"}', $1), '{"language"}', $2)
+func (grammarSQL Postgres) CompileJSONUpdateColumn(column string, selectors []string, values map[string]interface{}, offset *int) (string, []interface{}) {
+	field := grammarSQL.Wrap(column)
+	sql := fmt.Sprintf("coalesce(%s, '{}')::jsonb", field)
+	bindings := []interface{}{}
+	for _, selector := range selectors {
+		_, path, _ := dbal.ParseJSONSelector(selector)
+		value := values[selector]
+		if dbal.IsExpression(value) {
+			sql = fmt.Sprintf("jsonb_set(%s, %s, %s)", sql, grammarSQL.WrapJSONPathArray(path), value.(dbal.Expression).GetValue())
+			continue
+		}
+
+		// The values of the jsonb_set function must be JSON documents.
+		bytes, err := json.Marshal(value)
+		utils.PanicIF(err)
+		*offset++
+		sql = fmt.Sprintf("jsonb_set(%s, %s, %s)", sql, grammarSQL.WrapJSONPathArray(path), grammarSQL.Parameter(string(bytes), *offset))
+		bindings = append(bindings, string(bytes))
+	}
+	return fmt.Sprintf("%s=%s", field, sql), bindings
+}
+
+// WrapJSONPathArray Wrap the given JSON path segments as a text array. eg: '{"languages","0"}'
+func (grammarSQL Postgres) WrapJSONPathArray(path []string) string {
+	segments := []string{}
+	for _, segment := range path {
+		matches := jsonPathIndex.FindStringSubmatch(segment)
+		if len(matches) == 0 {
+			segments = append(segments, fmt.Sprintf(`"%s"`, strings.ReplaceAll(segment, `"`, `\"`)))
+			continue
+		}
+
+		if matches[1] != "" {
+			segments = append(segments, fmt.Sprintf(`"%s"`, strings.ReplaceAll(matches[1], `"`, `\"`)))
+		}
+		for _, index := range strings.Split(strings.Trim(matches[2], "[]"), "][") {
+			segments = append(segments, fmt.Sprintf(`"%s"`, index))
+		}
+	}
+	return "'{" + strings.ReplaceAll(strings.Join(segments, ","), "'", "''") + "}'"
+}
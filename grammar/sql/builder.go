@@ -166,3 +166,57 @@ func (grammarSQL SQL) SQLAddPrimary(primary *dbal.Primary) string {
 
 	return sql
 }
+
+// SQLAddForeign return the add foreign key sql for table create
+func (grammarSQL SQL) SQLAddForeign(foreign *dbal.Foreign) string {
+
+	quoter := grammarSQL.Quoter
+
+	// CONSTRAINT `posts_user_id_foreign` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+	columns := []string{}
+	for _, name := range foreign.Columns {
+		columns = append(columns, quoter.ID(name))
+	}
+
+	references := []string{}
+	for _, name := range foreign.ReferencedColumns {
+		references = append(references, quoter.ID(name))
+	}
+
+	sql := fmt.Sprintf(
+		"CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		quoter.ID(foreign.Name),
+		strings.Join(columns, ","),
+		quoter.ID(foreign.ReferencedTableName),
+		strings.Join(references, ","),
+	)
+
+	if foreign.OnDelete != "" {
+		sql = sql + " ON DELETE " + foreign.OnDelete
+	}
+
+	if foreign.OnUpdate != "" {
+		sql = sql + " ON UPDATE " + foreign.OnUpdate
+	}
+
+	return sql
+}
+
+// SQLAddConstraint return the add unique or check constraint sql for table create, the constraint name will be prefixed with the table name.
+func (grammarSQL SQL) SQLAddConstraint(constraint *dbal.Constraint) string {
+
+	quoter := grammarSQL.Quoter
+	name := quoter.ID(fmt.Sprintf("%s_%s", constraint.TableName, constraint.Name))
+
+	// CONSTRAINT `users_vote_range` CHECK (vote >= 0)
+	if constraint.Type == "CHECK" {
+		return fmt.Sprintf("CONSTRAINT %s CHECK (%s)", name, constraint.Expression)
+	}
+
+	// CONSTRAINT `users_name_email` UNIQUE (`name`,`email`)
+	columns := []string{}
+	for _, column := range constraint.Columns {
+		columns = append(columns, quoter.ID(column))
+	}
+	return fmt.Sprintf("CONSTRAINT %s UNIQUE (%s)", name, strings.Join(columns, ","))
+}
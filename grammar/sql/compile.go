@@ -3,12 +3,19 @@ package sql
 import (
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
 )
 
+// windowFunc the name and the arguments of the window function. eg: lag(price, 1)
+var windowFunc = regexp.MustCompile(`^\s*(\w+)\s*(?:\((.*)\))?\s*$`)
+
+// windowFuncColumn the column argument of the window function. eg: price, t.price
+var windowFuncColumn = regexp.MustCompile(`^[A-Za-z_]\w*(\.[A-Za-z_]\w*)?$`)
+
 // CompileSelect Compile a select query into SQL.
 func (grammarSQL SQL) CompileSelect(query *dbal.Query) string {
 	bindingOffset := 0
@@ -33,6 +40,10 @@ func (grammarSQL SQL) CompileSelectOffset(query *dbal.Query, offset *int) string
 		return grammarSQL.CompileUnionAggregate(query)
 	}
 
+	// The common table expressions should be compiled first, because
+	// their bindings are placed before the bindings of the select.
+	with := grammarSQL.CompileWith(query, query.CTEs, offset)
+
 	sqls := map[string]string{}
 
 	// If the query does not have any columns set, we'll set the columns to the
@@ -53,13 +64,14 @@ func (grammarSQL SQL) CompileSelectOffset(query *dbal.Query, offset *int) string
 	sqls["wheres"] = grammarSQL.CompileWheres(query, query.Wheres, offset)
 	sqls["groups"] = grammarSQL.CompileGroups(query, query.Groups, offset)
 	sqls["havings"] = grammarSQL.CompileHavings(query, query.Havings, offset)
+	sqls["windows"] = grammarSQL.CompileWindows(query, query.Windows, offset)
 	sqls["orders"] = grammarSQL.CompileOrders(query, query.Orders, offset)
 	sqls["limit"] = grammarSQL.CompileLimit(query, query.Limit, offset)
 	sqls["offset"] = grammarSQL.CompileOffset(query, query.Offset)
 	sqls["lock"] = grammarSQL.CompileLock(query, query.Lock)
 
 	sql := ""
-	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "orders", "limit", "offset", "lock"} {
+	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "windows", "orders", "limit", "offset", "lock"} {
 		segment, has := sqls[name]
 		if has && segment != "" {
 			sql = sql + segment + " "
@@ -71,6 +83,10 @@ func (grammarSQL SQL) CompileSelectOffset(query *dbal.Query, offset *int) string
 		sql = fmt.Sprintf("%s %s", grammarSQL.WrapUnion(sql), grammarSQL.CompileUnions(query, query.Unions, offset))
 	}
 
+	if with != "" {
+		sql = fmt.Sprintf("%s %s", with, sql)
+	}
+
 	// reset columns
 	query.Columns = columns
 	return strings.Trim(sql, " ")
@@ -82,6 +98,11 @@ func (grammarSQL SQL) CompileExists(query *dbal.Query) string {
 	return fmt.Sprintf("select exists(%s) as %s", sql, grammarSQL.Wrap("exists"))
 }
 
+// CompileReturning Compile the "returning" portion of the insert, update and delete statements.
+func (grammarSQL SQL) CompileReturning(query *dbal.Query, columns []interface{}) (string, error) {
+	return "", fmt.Errorf("This database engine does not support returning")
+}
+
 // CompileUnionAggregate Compile a union aggregate query into SQL.
 func (grammarSQL SQL) CompileUnionAggregate(query *dbal.Query) string {
 	qb := &(*query)
@@ -144,6 +165,73 @@ func (grammarSQL SQL) CompileUnion(query *dbal.Query, union dbal.Union, offset *
 	return fmt.Sprintf("%s%s", conjunction, grammarSQL.WrapUnion(grammarSQL.CompileSelectOffset(union.Query, offset)))
 }
 
+// CompileWith Compile the common table expressions of the query.
+func (grammarSQL SQL) CompileWith(query *dbal.Query, ctes []dbal.CTE, offset *int) string {
+	return grammarSQL.CompileCTEs(grammarSQL, query, ctes, offset, false)
+}
+
+// CompileCTEs Compile the common table expressions, the materialized hint is compiled only if the database supports it.
+// The subqueries are compiled by the given grammar, so the where clauses of the expressions use the syntax of the database.
+func (grammarSQL SQL) CompileCTEs(grammar dbal.Grammar, query *dbal.Query, ctes []dbal.CTE, offset *int, materialized bool) string {
+	if len(ctes) == 0 {
+		return ""
+	}
+
+	recursive := false
+	expressions := []string{}
+	for _, cte := range ctes {
+		if cte.Recursive {
+			recursive = true
+		}
+
+		name := grammarSQL.ID(cte.Name)
+		if len(cte.Columns) > 0 {
+			columns := []string{}
+			for _, column := range cte.Columns {
+				columns = append(columns, grammarSQL.ID(column))
+			}
+			name = fmt.Sprintf("%s (%s)", name, strings.Join(columns, ", "))
+		}
+
+		hint := ""
+		if materialized && cte.Materialized {
+			hint = "materialized "
+		}
+		expressions = append(expressions, fmt.Sprintf("%s as %s(%s)", name, hint, grammarSQL.CompileCTE(grammar, cte, offset)))
+	}
+
+	if recursive {
+		return fmt.Sprintf("with recursive %s", strings.Join(expressions, ", "))
+	}
+	return fmt.Sprintf("with %s", strings.Join(expressions, ", "))
+}
+
+// CompileCTE Compile the subquery of a single common table expression using the given grammar.
+func (grammarSQL SQL) CompileCTE(grammar dbal.Grammar, cte dbal.CTE, offset *int) string {
+	if cte.Query == nil {
+		return cte.SQL
+	}
+
+	// The recursive term of a recursive expression must be a plain select, so the
+	// union queries are compiled without being wrapped unless they are ordered or limited.
+	query := cte.Query
+	if !cte.Recursive || len(query.Unions) == 0 || len(query.UnionOrders) > 0 || query.UnionLimit >= 0 || query.UnionOffset >= 0 {
+		return grammar.CompileSelectOffset(query, offset)
+	}
+
+	anchor := *query
+	anchor.Unions = []dbal.Union{}
+	sql := grammar.CompileSelectOffset(&anchor, offset)
+	for _, union := range query.Unions {
+		conjunction := "union"
+		if union.All {
+			conjunction = "union all"
+		}
+		sql = fmt.Sprintf("%s %s %s", sql, conjunction, grammar.CompileSelectOffset(union.Query, offset))
+	}
+	return sql
+}
+
 // CompileJoins Compile the "join" portions of the query.
 func (grammarSQL SQL) CompileJoins(query *dbal.Query, joins []dbal.Join, offset *int) string {
 	sql := ""
@@ -171,6 +259,12 @@ func (grammarSQL SQL) CompileJoins(query *dbal.Query, joins []dbal.Join, offset
 			tableAndNestedJoins = fmt.Sprintf("(%s%s)", table, nestedJoins)
 		}
 
+		// inner join lateral (select * from `posts` where `posts`.`user_id` = `users`.`id` limit 3) as latest on true
+		if join.Lateral {
+			sql = strings.Trim(sql+" "+fmt.Sprintf("%s join lateral %s on true", join.Type, tableAndNestedJoins), " ")
+			continue
+		}
+
 		sql = strings.Trim(
 			sql+" "+fmt.Sprintf("%s join %s %s", join.Type, tableAndNestedJoins, grammarSQL.CompileWheres(join.Query, join.Query.Wheres, offset)),
 			" ",
@@ -208,7 +302,7 @@ func (grammarSQL SQL) CompileColumns(query *dbal.Query, columns []interface{}, b
 		sql = "select distinct"
 	}
 
-	sql = fmt.Sprintf("%s %s", sql, grammarSQL.Columnize(columns))
+	sql = fmt.Sprintf("%s %s", sql, grammarSQL.Columnize(grammarSQL.CompileWindowColumns(query, columns, bindingOffset)))
 	for _, col := range columns {
 		switch col.(type) {
 		case dbal.Select:
@@ -310,15 +404,140 @@ func (grammarSQL SQL) HavingBetween(query *dbal.Query, having dbal.Having, bindi
 	return fmt.Sprintf("%s %s %s %s and %s", having.Boolean, column, between, min, max)
 }
 
+// CompileWindows Compile the "window" portions of the query.
+func (grammarSQL SQL) CompileWindows(query *dbal.Query, windows []dbal.Window, bindingOffset *int) string {
+	if len(windows) == 0 {
+		return ""
+	}
+
+	clauses := []string{}
+	for _, window := range windows {
+		clauses = append(clauses, fmt.Sprintf("%s as (%s)", grammarSQL.ID(window.Name), grammarSQL.CompileWindowSpec(query, window, bindingOffset)))
+	}
+	return fmt.Sprintf("window %s", strings.Join(clauses, ", "))
+}
+
+// CompileWindowColumns Compile the window function columns into raw expressions, the other columns are returned as they are.
+func (grammarSQL SQL) CompileWindowColumns(query *dbal.Query, columns []interface{}, bindingOffset *int) []interface{} {
+	compiled := []interface{}{}
+	for _, column := range columns {
+		if fn, ok := column.(dbal.WindowFunction); ok {
+			column = dbal.Raw(grammarSQL.CompileWindowFunction(query, fn, bindingOffset))
+		}
+		compiled = append(compiled, column)
+	}
+	return compiled
+}
+
+// CompileWindowFunction Compile a window function column. eg: row_number() over (partition by `cate` order by `score` desc) as `rank`
+func (grammarSQL SQL) CompileWindowFunction(query *dbal.Query, fn dbal.WindowFunction, bindingOffset *int) string {
+
+	over := ""
+	window := fn.Window
+	if window.Name != "" && len(window.PartitionBy) == 0 && len(window.OrderBy) == 0 && window.Frame == "" {
+		over = grammarSQL.ID(window.Name)
+	} else {
+		over = fmt.Sprintf("(%s)", grammarSQL.CompileWindowSpec(query, window, bindingOffset))
+	}
+
+	sql := fmt.Sprintf("%s over %s", grammarSQL.CompileWindowFunc(fn.Func), over)
+	if fn.Alias != "" {
+		sql = fmt.Sprintf("%s as %s", sql, grammarSQL.ID(fn.Alias))
+	}
+	return sql
+}
+
+// CompileWindowSpec Compile the partition, order and frame clauses of a window.
+func (grammarSQL SQL) CompileWindowSpec(query *dbal.Query, window dbal.Window, bindingOffset *int) string {
+	clauses := []string{}
+	if len(window.PartitionBy) > 0 {
+		clauses = append(clauses, fmt.Sprintf("partition by %s", grammarSQL.Columnize(window.PartitionBy)))
+	}
+	if len(window.OrderBy) > 0 {
+		clauses = append(clauses, grammarSQL.CompileOrders(query, window.OrderBy, bindingOffset))
+	}
+	if window.Frame != "" {
+		clauses = append(clauses, window.Frame)
+	}
+	return strings.Join(clauses, " ")
+}
+
+// CompileWindowFunc Compile the function of the window function column. eg: sum(`amount`), lag(`price`, 1), count(distinct cate)
+// The arguments which are column names will be wrapped, the other arguments (expressions, literals and nested calls) are kept as they are.
+// Use dbal.Raw to keep the whole function as it is.
+func (grammarSQL SQL) CompileWindowFunc(fn interface{}) string {
+	value, ok := fn.(string)
+	if !ok {
+		return grammarSQL.Wrap(fn)
+	}
+
+	matches := windowFunc.FindStringSubmatch(value)
+	if len(matches) == 0 {
+		return value
+	}
+
+	args := []string{}
+	for _, arg := range splitWindowFuncArgs(matches[2]) {
+		keyword := strings.ToLower(arg)
+		if windowFuncColumn.MatchString(arg) && keyword != "null" && keyword != "true" && keyword != "false" {
+			arg = grammarSQL.Wrap(arg)
+		}
+		args = append(args, arg)
+	}
+	return fmt.Sprintf("%s(%s)", matches[1], strings.Join(args, ", "))
+}
+
+// splitWindowFuncArgs split the arguments of the window function by the commas outside the parentheses and the string literals.
+// eg: coalesce(price, 0), 'a,b' => [coalesce(price, 0), 'a,b']
+func splitWindowFuncArgs(value string) []string {
+	args := []string{}
+	depth := 0
+	quoted := false
+	start := 0
+	for i, char := range value {
+		switch {
+		case char == '\'':
+			quoted = !quoted
+		case quoted:
+		case char == '(':
+			depth++
+		case char == ')':
+			depth--
+		case char == ',' && depth == 0:
+			args = append(args, value[start:i])
+			start = i + 1
+		}
+	}
+	args = append(args, value[start:])
+
+	trimmed := []string{}
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if arg != "" {
+			trimmed = append(trimmed, arg)
+		}
+	}
+	return trimmed
+}
+
 // CompileOrders Compile the "order by" portions of the query.
 func (grammarSQL SQL) CompileOrders(query *dbal.Query, orders []dbal.Order, bindingOffset *int) string {
+	return grammarSQL.CompileOrderBy(grammarSQL, query, orders, bindingOffset)
+}
+
+// CompileOrderBy Compile the "order by" portions of the query, the full text search orders are compiled by the given grammar.
+func (grammarSQL SQL) CompileOrderBy(grammar dbal.Grammar, query *dbal.Query, orders []dbal.Order, bindingOffset *int) string {
 	if len(orders) == 0 {
 		return ""
 	}
 
 	clauses := []string{}
 	for _, order := range orders {
-		if order.SQL != "" {
+		if order.Type == "fulltext" {
+			sql, bindings := grammar.CompileFullTextOrder(query, *order.FullText, order.Value, *bindingOffset)
+			*bindingOffset = *bindingOffset + len(bindings)
+			clauses = append(clauses, sql)
+		} else if order.SQL != "" {
 			clauses = append(clauses, order.SQL)
 		} else {
 			clauses = append(clauses, fmt.Sprintf("%s %s", grammarSQL.Wrap(order.Column), order.Direction))
@@ -343,15 +562,49 @@ func (grammarSQL SQL) CompileOffset(query *dbal.Query, offset int) string {
 	return fmt.Sprintf("offset %d", offset)
 }
 
+// CompileRandom Compile the random statement into SQL.
+func (grammarSQL SQL) CompileRandom(seed string) string {
+	return fmt.Sprintf("RAND(%s)", seed)
+}
+
 // CompileLock the lock into SQL.
 func (grammarSQL SQL) CompileLock(query *dbal.Query, lock interface{}) string {
-	sql, ok := lock.(string)
-	if ok {
-		return sql
+	switch lock.(type) {
+	case string:
+		return lock.(string)
+	case dbal.Lock:
+		return grammarSQL.CompileRowLock(lock.(dbal.Lock))
 	}
 	return ""
 }
 
+// CheckQuery Check if the features used by the select statement are supported by the database engine.
+func (grammarSQL SQL) CheckQuery(query *dbal.Query) error {
+	return nil
+}
+
+// CompileRowLock Compile the row locking clause. eg: for update of `jobs` skip locked
+func (grammarSQL SQL) CompileRowLock(lock dbal.Lock) string {
+	sql := fmt.Sprintf("for %s", lock.Type)
+	if len(lock.Of) > 0 {
+		tables := []string{}
+		for _, table := range lock.Of {
+			// The aliased tables are locked by their aliases. eg: users as u => `u`
+			name := dbal.NewName(table)
+			if name.As() != "" {
+				tables = append(tables, grammarSQL.ID(name.As()))
+				continue
+			}
+			tables = append(tables, grammarSQL.ID(table))
+		}
+		sql = fmt.Sprintf("%s of %s", sql, strings.Join(tables, ", "))
+	}
+	if lock.Option != "" {
+		sql = fmt.Sprintf("%s %s", sql, lock.Option)
+	}
+	return sql
+}
+
 // CompileWheres Compile an update statement into SQL.
 func (grammarSQL SQL) CompileWheres(query *dbal.Query, wheres []dbal.Where, bindingOffset *int) string {
 
@@ -545,6 +798,70 @@ func (grammarSQL SQL) WhereIn(query *dbal.Query, where dbal.Where, bindingOffset
 	return sql
 }
 
+// WhereJSONContains Compile a "where JSON contains" clause.
+func (grammarSQL SQL) WhereJSONContains(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	not := ""
+	if where.Not {
+		not = "not "
+	}
+	field, path := grammarSQL.WrapJSONFieldAndPath(fmt.Sprintf("%v", where.Column))
+	value := grammarSQL.WhereJSONValue(where, bindingOffset)
+	// json_contains(`options`, ?, '$."languages"')
+	return fmt.Sprintf("%sjson_contains(%s, %s, %s)", not, field, value, path)
+}
+
+// WhereJSONLength Compile a "where JSON length" clause.
+func (grammarSQL SQL) WhereJSONLength(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	field, path := grammarSQL.WrapJSONFieldAndPath(fmt.Sprintf("%v", where.Column))
+	value := grammarSQL.WhereJSONValue(where, bindingOffset)
+	// json_length(`options`, '$."languages"') > ?
+	return fmt.Sprintf("json_length(%s, %s) %s %s", field, path, where.Operator, value)
+}
+
+// WhereJSONBoolean Compile a "where JSON boolean" clause.
+func (grammarSQL SQL) WhereJSONBoolean(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	field, path := grammarSQL.WrapJSONFieldAndPath(fmt.Sprintf("%v", where.Column))
+	value := grammarSQL.WhereJSONValue(where, bindingOffset)
+	// json_extract(`options`, '$."enabled"') = true
+	return fmt.Sprintf("json_extract(%s, %s) %s %s", field, path, where.Operator, value)
+}
+
+// WhereJSONValue Get the parameter place-holder of a JSON where clause value.
+func (grammarSQL SQL) WhereJSONValue(where dbal.Where, bindingOffset *int) string {
+	if dbal.IsExpression(where.Value) {
+		return where.Value.(dbal.Expression).GetValue()
+	}
+	*bindingOffset = *bindingOffset + where.Offset
+	return grammarSQL.Parameter(where.Value, *bindingOffset)
+}
+
+// WhereFullText Compile a "where fulltext" clause.
+func (grammarSQL SQL) WhereFullText(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	*bindingOffset = *bindingOffset + where.Offset
+	value := grammarSQL.Parameter(where.Value, *bindingOffset)
+	// match (`title`, `body`) against (? in natural language mode)
+	return grammarSQL.CompileFullTextMatch(where.Column.(dbal.FullText), value)
+}
+
+// CompileFullTextOrder Compile the full text search relevance order into SQL.
+func (grammarSQL SQL) CompileFullTextOrder(query *dbal.Query, fulltext dbal.FullText, value interface{}, offset int) (string, []interface{}) {
+	// match (`title`, `body`) against (? in natural language mode) desc
+	match := grammarSQL.CompileFullTextMatch(fulltext, grammarSQL.Parameter(value, offset+1))
+	return fmt.Sprintf("%s desc", match), []interface{}{value}
+}
+
+// CompileFullTextMatch Compile the "match ... against" expression. the mode option could be "natural"(default), "boolean" or "expanded"
+func (grammarSQL SQL) CompileFullTextMatch(fulltext dbal.FullText, value string) string {
+	mode := "in natural language mode"
+	switch fulltext.Options["mode"] {
+	case "boolean":
+		mode = "in boolean mode"
+	case "expanded":
+		mode = "with query expansion"
+	}
+	return fmt.Sprintf("match (%s) against (%s %s)", grammarSQL.Columnize(fulltext.Columns), value, mode)
+}
+
 // Utils for compiling
 
 // RemoveLeadingBoolean Remove the leading boolean from a statement.
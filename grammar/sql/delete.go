@@ -20,8 +20,9 @@ func (grammarSQL SQL) CompileDelete(query *dbal.Query) (string, []interface{}) {
 		joins = grammarSQL.CompileJoins(query, query.Joins, &offset)
 		bindings = append(bindings, query.GetBindings("join")...)
 		offset = len(bindings)
+		alias = table
 		tableArr := strings.Split(table, " as ")
-		if len(tableArr) >= 1 {
+		if len(tableArr) > 1 {
 			alias = tableArr[1]
 		}
 	}
@@ -0,0 +1,152 @@
+package sql
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/blang/semver/v4"
+	"github.com/yaoapp/xun/dbal"
+)
+
+// CompileExplain Compile an explain statement of the given select SQL. eg: explain format=json select * from `users`
+func (grammarSQL SQL) CompileExplain(sql string, options map[string]interface{}) string {
+	if grammarSQL.IsExplainJSONSupported() {
+		return fmt.Sprintf("explain format=json %s", sql)
+	}
+	return fmt.Sprintf("explain %s", sql)
+}
+
+// IsExplainJSONSupported Determine if the database supports the JSON format of the explain statement (MySQL 5.6.5+)
+func (grammarSQL SQL) IsExplainJSONSupported() bool {
+	version, err := grammarSQL.version()
+	if err != nil {
+		return false
+	}
+	return version.GE(semver.MustParse("5.6.5"))
+}
+
+// ProcessExplain Normalize the results of the explain statement.
+func (grammarSQL SQL) ProcessExplain(rows []map[string]interface{}, options map[string]interface{}) (*dbal.Plan, error) {
+	plan := &dbal.Plan{Nodes: []*dbal.PlanNode{}, Raw: rows}
+
+	// the JSON format. eg: {"query_block": {"cost_info": {"query_cost": "1.40"}, "table": {...}}}
+	if len(rows) == 1 {
+		if value, has := rows[0]["EXPLAIN"]; has {
+			raw, err := ExplainJSON(value)
+			if err != nil {
+				return nil, err
+			}
+			plan.Raw = raw
+			plan.Nodes = grammarSQL.explainJSONNodes(raw)
+			if values, ok := raw.(map[string]interface{}); ok {
+				if block, ok := values["query_block"].(map[string]interface{}); ok {
+					if cost, ok := block["cost_info"].(map[string]interface{}); ok {
+						plan.Cost = ExplainFloat(cost["query_cost"])
+					}
+				}
+			}
+			return plan, nil
+		}
+	}
+
+	// the traditional format. eg: id, select_type, table, type, possible_keys, key, rows, Extra
+	for _, row := range rows {
+		plan.Nodes = append(plan.Nodes, &dbal.PlanNode{
+			Operation: ExplainString(row["type"]),
+			Table:     ExplainString(row["table"]),
+			Index:     ExplainString(row["key"]),
+			Rows:      ExplainFloat(row["rows"]),
+			FullScan:  ExplainString(row["type"]) == "ALL",
+			Detail:    ExplainString(row["Extra"]),
+			Children:  []*dbal.PlanNode{},
+		})
+	}
+	return plan, nil
+}
+
+// explainJSONNodes collect the table nodes of the JSON format explain output
+func (grammarSQL SQL) explainJSONNodes(value interface{}) []*dbal.PlanNode {
+	nodes := []*dbal.PlanNode{}
+	switch value.(type) {
+	case []interface{}:
+		for _, item := range value.([]interface{}) {
+			nodes = append(nodes, grammarSQL.explainJSONNodes(item)...)
+		}
+
+	case map[string]interface{}:
+		values := value.(map[string]interface{})
+		keys := []string{}
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			table, ok := values[key].(map[string]interface{})
+			if key != "table" || !ok {
+				nodes = append(nodes, grammarSQL.explainJSONNodes(values[key])...)
+				continue
+			}
+
+			node := &dbal.PlanNode{
+				Operation: ExplainString(table["access_type"]),
+				Table:     ExplainString(table["table_name"]),
+				Index:     ExplainString(table["key"]),
+				Rows:      ExplainFloat(table["rows_examined_per_scan"]),
+				FullScan:  ExplainString(table["access_type"]) == "ALL",
+				Detail:    ExplainString(table["attached_condition"]),
+				Children:  grammarSQL.explainJSONNodes(table),
+			}
+			if cost, ok := table["cost_info"].(map[string]interface{}); ok {
+				node.Cost = ExplainFloat(cost["prefix_cost"])
+			}
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// ExplainJSON Decode the JSON output of the explain statement
+func ExplainJSON(value interface{}) (interface{}, error) {
+	var raw interface{}
+	err := json.Unmarshal([]byte(ExplainString(value)), &raw)
+	if err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// ExplainString Convert the value of the explain output to string, returns "" if the value is nil
+func ExplainString(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(value.([]byte))
+	case string:
+		return value.(string)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// ExplainFloat Convert the value of the explain output to float64, returns 0 if the value is not a number
+func ExplainFloat(value interface{}) float64 {
+	switch value.(type) {
+	case float64:
+		return value.(float64)
+	case float32:
+		return float64(value.(float32))
+	case int64:
+		return float64(value.(int64))
+	case int:
+		return float64(value.(int))
+	}
+	number, err := strconv.ParseFloat(ExplainString(value), 64)
+	if err != nil {
+		return 0
+	}
+	return number
+}
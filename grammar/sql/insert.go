@@ -42,14 +42,14 @@ func (grammarSQL SQL) CompileInsertGetID(query *dbal.Query, columns []interface{
 }
 
 // ProcessInsertGetID Execute an insert and get ID statement and return the id
-func (grammarSQL SQL) ProcessInsertGetID(sql string, bindings []interface{}, sequence string) (int64, error) {
-	stmt, err := grammarSQL.DB.Prepare(sql)
+func (grammarSQL SQL) ProcessInsertGetID(db dbal.Executor, sql string, bindings []interface{}, sequence string) (int64, error) {
+	stmt, err := db.PrepareContext(grammarSQL.Context, sql)
 	if err != nil {
 		return 0, err
 	}
 
 	defer stmt.Close()
-	res, err := stmt.Exec(bindings...)
+	res, err := stmt.ExecContext(grammarSQL.Context, bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -2,13 +2,18 @@ package sql
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/utils"
 )
 
+// jsonPathIndex the array index suffix of the JSON path segment. eg: languages[0]
+var jsonPathIndex = regexp.MustCompile(`^(.*?)((\[\d+\])+)$`)
+
 // Quoter the database quoting query text SQL type
 type Quoter struct {
 	DB         *sqlx.DB
@@ -50,25 +55,26 @@ func (quoter *Quoter) ID(value string) string {
 
 // VAL quoting query value ( 'value' )
 func (quoter *Quoter) VAL(value interface{}) string {
+	value = LiteralValue(value)
+	if number, ok := LiteralNumber(value); ok {
+		return number
+	}
+
 	input := ""
 	switch value.(type) {
-	case *string:
-		input = fmt.Sprintf("%s", utils.StringVal(value.(*string)))
-		break
-	case string:
-		input = fmt.Sprintf("%s", value)
-		break
-	case int, int16, int32, int64, float64, float32:
-		input = fmt.Sprintf("%d", value)
-		break
+	case nil:
+		return "NULL"
+	case bool:
+		return utils.GetIF(value.(bool), "1", "0").(string)
+	case []byte:
+		return fmt.Sprintf("X'%x'", value)
+	case time.Time:
+		input = value.(time.Time).Format(TimeFormat)
 	default:
-		input = fmt.Sprintf("%v", value)
+		input = fmt.Sprintf("%s", value)
 	}
 
-	input = strings.ReplaceAll(input, "'", "\\'")
-	input = strings.ReplaceAll(input, "\n", "")
-	input = strings.ReplaceAll(input, "\r", "")
-	return "'" + input + "'"
+	return "'" + strings.ReplaceAll(input, "'", "''") + "'"
 }
 
 // Wrap a value in keyword identifiers.
@@ -100,6 +106,17 @@ func (quoter *Quoter) WrapAliasedValue(value string) string {
 	if value == "*" {
 		return "*"
 	}
+
+	// If the value is a JSON selector, we will wrap it with the JSON extract function.
+	// eg: options->language as lang
+	if dbal.IsJSONSelector(value) {
+		name := dbal.NewName(value)
+		if name.As() != "" {
+			return fmt.Sprintf("%s as %s", quoter.WrapJSONSelector(name.Name), quoter.ID(name.As()))
+		}
+		return quoter.WrapJSONSelector(name.Name)
+	}
+
 	if strings.Contains(value, ".") {
 		arrs := strings.Split(value, ".")
 		table := arrs[0]
@@ -114,6 +131,42 @@ func (quoter *Quoter) WrapAliasedValue(value string) string {
 	return fmt.Sprintf("%s", quoter.ID(name.Fullname()))
 }
 
+// WrapJSONSelector Wrap the given JSON selector.
+// eg: options->language: json_extract(`options`, '$."language"'), options->>language: json_unquote(json_extract(`options`, '$."language"'))
+func (quoter *Quoter) WrapJSONSelector(value string) string {
+	field, path := quoter.WrapJSONFieldAndPath(value)
+	if _, _, unquote := dbal.ParseJSONSelector(value); unquote {
+		return fmt.Sprintf("json_unquote(json_extract(%s, %s))", field, path)
+	}
+	return fmt.Sprintf("json_extract(%s, %s)", field, path)
+}
+
+// WrapJSONFieldAndPath Split the given JSON selector into the wrapped field and the wrapped path. eg: `options`, '$."language"'
+func (quoter *Quoter) WrapJSONFieldAndPath(value string) (string, string) {
+	field, path, _ := dbal.ParseJSONSelector(value)
+	return quoter.WrapAliasedValue(field), quoter.WrapJSONPath(path)
+}
+
+// WrapJSONPath Wrap the given JSON path segments. eg: '$."dining"."meal"', '$."languages"[0]'
+func (quoter *Quoter) WrapJSONPath(path []string) string {
+	segments := []string{"$"}
+	for _, segment := range path {
+		matches := jsonPathIndex.FindStringSubmatch(segment)
+		if len(matches) == 0 {
+			segments = append(segments, fmt.Sprintf(`"%s"`, strings.ReplaceAll(segment, `"`, `\"`)))
+			continue
+		}
+
+		// the array index suffix. eg: languages[0]
+		if matches[1] == "" {
+			segments[len(segments)-1] = segments[len(segments)-1] + matches[2]
+			continue
+		}
+		segments = append(segments, fmt.Sprintf(`"%s"%s`, strings.ReplaceAll(matches[1], `"`, `\"`), matches[2]))
+	}
+	return "'" + strings.ReplaceAll(strings.Join(segments, "."), "'", "''") + "'"
+}
+
 // WrapTable Wrap a table in keyword identifiers.
 func (quoter *Quoter) WrapTable(value interface{}) string {
 	switch value.(type) {
@@ -0,0 +1,168 @@
+package sql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeFormat the format of time values in the raw SQL
+const TimeFormat = "2006-01-02 15:04:05.999999"
+
+// Interpolate Interpolate the bindings into the given SQL using the "?" placeholders.
+func (grammarSQL SQL) Interpolate(sql string, bindings []interface{}) string {
+	return Interpolate(sql, bindings, grammarSQL.VAL, false, false)
+}
+
+// Interpolate Replace the placeholders ( "?" or "$n" when numbered is true ) of the given SQL with the quoted bindings.
+// The placeholders within the quoted strings, the quoted identifiers and the comments will be kept.
+// If backslash is true, the backslash is taken as the escape character of the quoted strings. (MySQL)
+func Interpolate(sql string, bindings []interface{}, quote func(value interface{}) string, numbered bool, backslash bool) string {
+	var res strings.Builder
+	next := 0
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+
+		// quoted strings and identifiers. eg: 'it''s', "name", `name`
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for ; end < len(sql); end++ {
+				if backslash && sql[end] == '\\' {
+					end++
+					continue
+				}
+				if sql[end] == c {
+					if end+1 < len(sql) && sql[end+1] == c {
+						end++
+						continue
+					}
+					break
+				}
+			}
+			if end >= len(sql) {
+				end = len(sql) - 1
+			}
+			res.WriteString(sql[i : end+1])
+			i = end
+
+		// comments. eg: -- comment, /* comment */
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i - 1
+			}
+			res.WriteString(sql[i : i+end+1])
+			i += end
+
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				end = len(sql) - i - 4
+			}
+			res.WriteString(sql[i : i+end+4])
+			i += end + 3
+
+		case !numbered && c == '?':
+			if next >= len(bindings) {
+				res.WriteByte(c)
+				continue
+			}
+			res.WriteString(quote(bindings[next]))
+			next++
+
+		case numbered && c == '$' && i+1 < len(sql) && sql[i+1] >= '0' && sql[i+1] <= '9':
+			end := i + 1
+			for end < len(sql) && sql[end] >= '0' && sql[end] <= '9' {
+				end++
+			}
+			num, _ := strconv.Atoi(sql[i+1 : end])
+			if num < 1 || num > len(bindings) {
+				res.WriteString(sql[i:end])
+			} else {
+				res.WriteString(quote(bindings[num-1]))
+			}
+			i = end - 1
+
+		default:
+			res.WriteByte(c)
+		}
+	}
+	return res.String()
+}
+
+// LiteralValue Resolve the given binding value to one of nil, bool, int64, uint64, float64, string, []byte and time.Time.
+// The pointers and the driver.Valuer values (eg: xun.T, xun.N) will be resolved.
+func LiteralValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	reflectValue := reflect.ValueOf(value)
+	if valuer, ok := value.(driver.Valuer); ok {
+		if reflectValue.Kind() == reflect.Ptr && reflectValue.IsNil() {
+			return nil
+		}
+		v, err := valuer.Value()
+		if err != nil {
+			return nil
+		}
+		if _, ok := v.(driver.Valuer); ok {
+			return v
+		}
+		return LiteralValue(v)
+	}
+
+	if reflectValue.Kind() == reflect.Ptr {
+		if reflectValue.IsNil() {
+			return nil
+		}
+		return LiteralValue(reflectValue.Elem().Interface())
+	}
+
+	// the type implements driver.Valuer with a pointer receiver. eg: xun.T
+	ptr := reflect.New(reflectValue.Type())
+	ptr.Elem().Set(reflectValue)
+	if _, ok := ptr.Interface().(driver.Valuer); ok {
+		return LiteralValue(ptr.Interface())
+	}
+
+	switch reflectValue.Kind() {
+	case reflect.Bool:
+		return reflectValue.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflectValue.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflectValue.Uint()
+	case reflect.Float32, reflect.Float64:
+		return reflectValue.Float()
+	case reflect.String:
+		return reflectValue.String()
+	case reflect.Slice:
+		if reflectValue.Type().Elem().Kind() == reflect.Uint8 {
+			return reflectValue.Bytes()
+		}
+	}
+
+	if t, ok := value.(time.Time); ok {
+		return t
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// LiteralNumber Format the numeric value of LiteralValue, returns false if the value is not a number.
+func LiteralNumber(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
@@ -27,7 +27,7 @@ func (grammarSQL SQL) GetVersion() (*dbal.Version, error) {
 	sql := fmt.Sprintf("SELECT VERSION()")
 	// defer logger.Debug(logger.RETRIEVE, sql).TimeCost(time.Now())
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &rows, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +75,7 @@ func (grammarSQL SQL) GetTables() ([]string, error) {
 	sql := "SHOW TABLES"
 	defer log.Debug(sql)
 	tables := []string{}
-	err := grammarSQL.DB.Select(&tables, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &tables, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +87,7 @@ func (grammarSQL SQL) TableExists(name string) (bool, error) {
 	sql := fmt.Sprintf("SHOW TABLES like %s", grammarSQL.VAL(name))
 	defer log.Debug(sql)
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &rows, sql)
 	if err != nil {
 		return false, err
 	}
@@ -120,6 +120,16 @@ func (grammarSQL SQL) GetTable(name string) (*dbal.Table, error) {
 		return nil, err
 	}
 
+	foreigns, err := grammarSQL.GetForeignListing(table.SchemaName, table.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	constraints, err := grammarSQL.GetTableConstraintListing(table.SchemaName, table.TableName)
+	if err != nil {
+		return nil, err
+	}
+
 	primaryKeyName := ""
 
 	// attaching columns
@@ -128,12 +138,36 @@ func (grammarSQL SQL) GetTable(name string) (*dbal.Table, error) {
 		table.PushColumn(column)
 	}
 
+	// attaching constraints
+	for _, c := range constraints {
+		if !table.HasConstraint(c.Name) {
+			constraint := *c
+			constraint.Table = table
+			constraint.Args = []string{}
+			constraint.Columns = []string{}
+			table.PushConstraint(&constraint)
+		}
+		if c.ColumnName != "" {
+			constraint := table.ConstraintMap[c.Name]
+			constraint.Columns = append(constraint.Columns, c.ColumnName)
+		}
+	}
+
 	// attaching indexes
 	for i := range indexes {
 		idx := indexes[i]
 		if !table.HasColumn(idx.ColumnName) {
 			return nil, fmt.Errorf("the column does not exists %s", idx.ColumnName)
 		}
+
+		// the unique index of the unique constraint
+		if strings.HasPrefix(idx.Name, table.TableName+"_") {
+			constraint := table.GetConstraint(strings.TrimPrefix(idx.Name, table.TableName+"_"))
+			if constraint != nil && constraint.Type == "UNIQUE" {
+				continue
+			}
+		}
+
 		column := table.ColumnMap[idx.ColumnName]
 		if !table.HasIndex(idx.Name) {
 			index := *idx
@@ -165,9 +199,143 @@ func (grammarSQL SQL) GetTable(name string) (*dbal.Table, error) {
 		}
 	}
 
+	// attaching foreign keys
+	for _, fk := range foreigns {
+		if !table.HasForeign(fk.Name) {
+			foreign := *fk
+			foreign.Table = table
+			foreign.Columns = []string{}
+			foreign.ReferencedColumns = []string{}
+			table.PushForeign(&foreign)
+		}
+		table.ForeignMap[fk.Name].AddColumn(fk.ColumnName, fk.ReferencedColumnName)
+	}
+
 	return table, nil
 }
 
+// GetForeignListing get a table foreign keys structure (one row per column)
+func (grammarSQL SQL) GetForeignListing(dbName string, tableName string) ([]*dbal.Foreign, error) {
+	selectColumns := []string{
+		"k.`TABLE_SCHEMA` AS `db_name`",
+		"k.`TABLE_NAME` AS `table_name`",
+		"k.`CONSTRAINT_NAME` AS `foreign_name`",
+		"k.`COLUMN_NAME` AS `column_name`",
+		"k.`REFERENCED_TABLE_NAME` AS `referenced_table_name`",
+		"k.`REFERENCED_COLUMN_NAME` AS `referenced_column_name`",
+		"r.`DELETE_RULE` AS `on_delete`",
+		"r.`UPDATE_RULE` AS `on_update`",
+	}
+	sql := fmt.Sprintf(`
+			SELECT %s
+			FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE AS k
+			INNER JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS AS r
+				ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA
+				AND r.TABLE_NAME = k.TABLE_NAME
+				AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME
+			WHERE k.TABLE_SCHEMA = %s AND k.TABLE_NAME = %s AND k.REFERENCED_TABLE_NAME IS NOT NULL
+			ORDER BY k.CONSTRAINT_NAME, k.ORDINAL_POSITION;
+		`,
+		strings.Join(selectColumns, ","),
+		grammarSQL.VAL(dbName),
+		grammarSQL.VAL(tableName),
+	)
+	defer log.Debug(sql)
+	foreigns := []*dbal.Foreign{}
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &foreigns, sql)
+	if err != nil {
+		return nil, err
+	}
+	return foreigns, nil
+}
+
+// uniqueConstraintComment MySQL doesn't tell the unique constraints from the unique indexes, the unique constraints are marked with the index comment.
+const uniqueConstraintComment = "xun:unique constraint"
+
+// GetTableConstraintListing get a table unique and check constraints structure (one row per column of the unique constraints)
+// The names of the constraints are prefixed with the table name, the prefix will be removed.
+// The unique constraints are the unique indexes marked with the comment, the check constraints are supported since MySQL 8.0.16
+func (grammarSQL SQL) GetTableConstraintListing(dbName string, tableName string) ([]*dbal.Constraint, error) {
+	selectColumns := []string{
+		"t.`TABLE_SCHEMA` AS `schema_name`",
+		"t.`TABLE_NAME` AS `table_name`",
+		"t.`CONSTRAINT_NAME` AS `constraint_name`",
+		"t.`CONSTRAINT_TYPE` AS `constraint_type`",
+		"k.`COLUMN_NAME` AS `column_name`",
+		"'' AS `expression`",
+	}
+	sql := fmt.Sprintf(`
+			SELECT %s
+			FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS AS t
+			INNER JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE AS k
+				ON k.CONSTRAINT_SCHEMA = t.CONSTRAINT_SCHEMA
+				AND k.TABLE_NAME = t.TABLE_NAME
+				AND k.CONSTRAINT_NAME = t.CONSTRAINT_NAME
+			INNER JOIN INFORMATION_SCHEMA.STATISTICS AS s
+				ON s.TABLE_SCHEMA = t.TABLE_SCHEMA
+				AND s.TABLE_NAME = t.TABLE_NAME
+				AND s.INDEX_NAME = t.CONSTRAINT_NAME
+				AND s.COLUMN_NAME = k.COLUMN_NAME
+			WHERE t.TABLE_SCHEMA = %s AND t.TABLE_NAME = %s
+				AND t.CONSTRAINT_TYPE = 'UNIQUE'
+				AND s.INDEX_COMMENT = %s
+			ORDER BY t.CONSTRAINT_NAME, k.ORDINAL_POSITION;
+		`,
+		strings.Join(selectColumns, ","),
+		grammarSQL.VAL(dbName),
+		grammarSQL.VAL(tableName),
+		grammarSQL.VAL(uniqueConstraintComment),
+	)
+	defer log.Debug(sql)
+	constraints := []*dbal.Constraint{}
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &constraints, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := grammarSQL.version()
+	if err != nil {
+		return nil, err
+	}
+
+	if version.GE(semver.MustParse("8.0.16")) {
+		selectColumns = []string{
+			"t.`TABLE_SCHEMA` AS `schema_name`",
+			"t.`TABLE_NAME` AS `table_name`",
+			"t.`CONSTRAINT_NAME` AS `constraint_name`",
+			"t.`CONSTRAINT_TYPE` AS `constraint_type`",
+			"'' AS `column_name`",
+			"c.`CHECK_CLAUSE` AS `expression`",
+		}
+		sql = fmt.Sprintf(`
+			SELECT %s
+			FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS AS t
+			INNER JOIN INFORMATION_SCHEMA.CHECK_CONSTRAINTS AS c
+				ON c.CONSTRAINT_SCHEMA = t.CONSTRAINT_SCHEMA
+				AND c.CONSTRAINT_NAME = t.CONSTRAINT_NAME
+			WHERE t.TABLE_SCHEMA = %s AND t.TABLE_NAME = %s
+				AND t.CONSTRAINT_TYPE = 'CHECK'
+			ORDER BY t.CONSTRAINT_NAME;
+		`,
+			strings.Join(selectColumns, ","),
+			grammarSQL.VAL(dbName),
+			grammarSQL.VAL(tableName),
+		)
+		defer log.Debug(sql)
+		checks := []*dbal.Constraint{}
+		err = grammarSQL.Executor().SelectContext(grammarSQL.Context, &checks, sql)
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, checks...)
+	}
+
+	for _, constraint := range constraints {
+		constraint.Name = strings.TrimPrefix(constraint.Name, tableName+"_")
+	}
+	return constraints, nil
+}
+
 // GetIndexListing get a table indexes structure
 func (grammarSQL SQL) GetIndexListing(dbName string, tableName string) ([]*dbal.Index, error) {
 	selectColumns := []string{
@@ -203,7 +371,7 @@ func (grammarSQL SQL) GetIndexListing(dbName string, tableName string) ([]*dbal.
 	)
 	defer log.Debug(sql)
 	indexes := []*dbal.Index{}
-	err := grammarSQL.DB.Select(&indexes, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &indexes, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -266,7 +434,7 @@ func (grammarSQL SQL) GetColumnListing(dbName string, tableName string) ([]*dbal
 	)
 	defer log.Debug(sql)
 	columns := []*dbal.Column{}
-	err := grammarSQL.DB.Select(&columns, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &columns, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -310,6 +478,8 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table) error {
 	var primary *dbal.Primary = nil
 	columns := []*dbal.Column{}
 	indexes := []*dbal.Index{}
+	foreigns := []*dbal.Foreign{}
+	constraints := []*dbal.Constraint{}
 	cbCommands := []*dbal.Command{}
 
 	// Commands
@@ -322,6 +492,8 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table) error {
 	//    DropIndex( name string) for  dropping a index
 	//    RenameIndex(old string,new string)  for renaming a index
 	//    CreatePrimary for creating the primary key
+	//    CreateForeign(foreign *Foreign) for creating a foreign key
+	//    CreateConstraint(constraint *Constraint) for creating a unique or check constraint
 	for _, command := range table.Commands {
 		switch command.Name {
 		case "AddColumn":
@@ -336,6 +508,14 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table) error {
 			primary = command.Params[0].(*dbal.Primary)
 			cbCommands = append(cbCommands, command)
 			break
+		case "CreateForeign":
+			foreigns = append(foreigns, command.Params[0].(*dbal.Foreign))
+			cbCommands = append(cbCommands, command)
+			break
+		case "CreateConstraint":
+			constraints = append(constraints, command.Params[0].(*dbal.Constraint))
+			cbCommands = append(cbCommands, command)
+			break
 		}
 
 	}
@@ -362,6 +542,23 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table) error {
 		}
 	}
 
+	// foreign keys
+	for _, foreign := range foreigns {
+		stmts = append(stmts, grammarSQL.SQLAddForeign(foreign))
+	}
+
+	// constraints
+	for _, constraint := range constraints {
+		stmt, err := grammarSQL.sqlAddConstraint(constraint)
+		if err != nil {
+			for _, cmd := range cbCommands {
+				cmd.Callback(err)
+			}
+			return err
+		}
+		stmts = append(stmts, stmt)
+	}
+
 	engine := utils.GetIF(table.Engine != "", "ENGINE "+table.Engine, "")
 	charset := utils.GetIF(table.Charset != "", "DEFAULT CHARSET "+table.Charset, "")
 	collation := utils.GetIF(table.Collation != "", "COLLATE="+table.Collation, "")
@@ -372,7 +569,7 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table) error {
 		engine, charset, collation,
 	)
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context, sql)
 
 	// Callback
 	for _, cmd := range cbCommands {
@@ -386,7 +583,7 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table) error {
 func (grammarSQL SQL) DropTable(name string) error {
 	sql := fmt.Sprintf("DROP TABLE %s", grammarSQL.ID(name))
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context, sql)
 	return err
 }
 
@@ -394,7 +591,7 @@ func (grammarSQL SQL) DropTable(name string) error {
 func (grammarSQL SQL) DropTableIfExists(name string) error {
 	sql := fmt.Sprintf("DROP TABLE IF EXISTS %s", grammarSQL.ID(name))
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context, sql)
 	return err
 }
 
@@ -402,7 +599,7 @@ func (grammarSQL SQL) DropTableIfExists(name string) error {
 func (grammarSQL SQL) RenameTable(old string, new string) error {
 	sql := fmt.Sprintf("ALTER TABLE %s RENAME %s", grammarSQL.ID(old), grammarSQL.ID(new))
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context, sql)
 	return err
 }
 
@@ -422,6 +619,10 @@ func (grammarSQL SQL) AlterTable(table *dbal.Table) error {
 	//    CreateIndex(index *Index) for creating a index
 	//    DropIndex(name string) for  dropping a index
 	//    RenameIndex(old string,new string)  for renaming a index
+	//    CreateForeign(foreign *Foreign) for creating a foreign key
+	//    DropForeign(name string) for dropping a foreign key
+	//    CreateConstraint(constraint *Constraint) for creating a unique or check constraint
+	//    DropConstraint(name string, typ string) for dropping a unique or check constraint
 	for _, command := range table.Commands {
 		switch command.Name {
 		case "AddColumn":
@@ -451,6 +652,18 @@ func (grammarSQL SQL) AlterTable(table *dbal.Table) error {
 		case "DropPrimary":
 			grammarSQL.alterTableDropPrimary(table, command, sql, &stmts, &errs)
 			break
+		case "CreateForeign":
+			grammarSQL.alterTableCreateForeign(table, command, sql, &stmts, &errs)
+			break
+		case "DropForeign":
+			grammarSQL.alterTableDropForeign(table, command, sql, &stmts, &errs)
+			break
+		case "CreateConstraint":
+			grammarSQL.alterTableCreateConstraint(table, command, sql, &stmts, &errs)
+			break
+		case "DropConstraint":
+			grammarSQL.alterTableDropConstraint(table, command, sql, &stmts, &errs)
+			break
 		}
 	}
 
@@ -619,9 +832,81 @@ func (grammarSQL SQL) alterTableDropPrimary(table *dbal.Table, command *dbal.Com
 	command.Callback(err)
 }
 
+func (grammarSQL SQL) alterTableCreateForeign(table *dbal.Table, command *dbal.Command, sql string, stmts *[]string, errs *[]error) {
+	foreign := command.Params[0].(*dbal.Foreign)
+	stmt := "ADD " + grammarSQL.SQLAddForeign(foreign)
+	*stmts = append(*stmts, sql+stmt)
+	err := grammarSQL.ExecSQL(table, sql+stmt)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("CreateForeign: %s", err))
+	}
+	command.Callback(err)
+}
+
+func (grammarSQL SQL) alterTableDropForeign(table *dbal.Table, command *dbal.Command, sql string, stmts *[]string, errs *[]error) {
+	name := command.Params[0].(string)
+	stmt := fmt.Sprintf("DROP FOREIGN KEY %s", grammarSQL.ID(name))
+	*stmts = append(*stmts, sql+stmt)
+	err := grammarSQL.ExecSQL(table, sql+stmt)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("DropForeign: %s", err))
+	}
+	command.Callback(err)
+}
+
+func (grammarSQL SQL) alterTableCreateConstraint(table *dbal.Table, command *dbal.Command, sql string, stmts *[]string, errs *[]error) {
+	constraint := command.Params[0].(*dbal.Constraint)
+	stmt, err := grammarSQL.sqlAddConstraint(constraint)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("CreateConstraint: %s", err))
+		command.Callback(err)
+		return
+	}
+
+	stmt = "ADD " + stmt
+	*stmts = append(*stmts, sql+stmt)
+	err = grammarSQL.ExecSQL(table, sql+stmt)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("CreateConstraint: %s", err))
+	}
+	command.Callback(err)
+}
+
+// sqlAddConstraint return the add constraint sql of MySQL, the unique constraints are marked with the index comment.
+// The check constraints are parsed and ignored before MySQL 8.0.16, so an error is returned.
+func (grammarSQL SQL) sqlAddConstraint(constraint *dbal.Constraint) (string, error) {
+	if constraint.Type != "CHECK" {
+		return fmt.Sprintf("%s COMMENT %s", grammarSQL.SQLAddConstraint(constraint), grammarSQL.VAL(uniqueConstraintComment)), nil
+	}
+
+	version, err := grammarSQL.version()
+	if err != nil {
+		return "", err
+	}
+
+	if version.LT(semver.MustParse("8.0.16")) {
+		return "", fmt.Errorf("This database engine does not support the check constraints, MySQL 8.0.16+ is required (current: %s)", version.String())
+	}
+	return grammarSQL.SQLAddConstraint(constraint), nil
+}
+
+func (grammarSQL SQL) alterTableDropConstraint(table *dbal.Table, command *dbal.Command, sql string, stmts *[]string, errs *[]error) {
+	name := grammarSQL.ID(fmt.Sprintf("%s_%s", table.TableName, command.Params[0]))
+	stmt := fmt.Sprintf("DROP INDEX %s", name)
+	if command.Params[1].(string) == "CHECK" {
+		stmt = fmt.Sprintf("DROP CHECK %s", name)
+	}
+	*stmts = append(*stmts, sql+stmt)
+	err := grammarSQL.ExecSQL(table, sql+stmt)
+	if err != nil {
+		*errs = append(*errs, fmt.Errorf("DropConstraint: %s", err))
+	}
+	command.Callback(err)
+}
+
 // ExecSQL execute sql then update table structure
 func (grammarSQL SQL) ExecSQL(table *dbal.Table, sql string) error {
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context, sql)
 	if err != nil {
 		return err
 	}
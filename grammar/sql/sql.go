@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"path/filepath"
@@ -24,6 +25,9 @@ type SQL struct {
 	Read         *sqlx.DB
 	ReadConfig   *dbal.Config
 	Option       *dbal.Option
+	Context      context.Context
+	Tx           *sqlx.Tx
+	Version      *dbal.Version // The version of the database, it is cached when the db server was connected.
 	dbal.Grammar
 	dbal.Quoter
 }
@@ -31,9 +35,10 @@ type SQL struct {
 // NewSQL create a new SQL instance
 func NewSQL(quoter dbal.Quoter, opts ...Option) SQL {
 	sql := &SQL{
-		Driver: "sql",
-		Mode:   "production",
-		Quoter: quoter,
+		Driver:  "sql",
+		Mode:    "production",
+		Quoter:  quoter,
+		Context: context.Background(),
 		IndexTypes: map[string]string{
 			"unique": "UNIQUE KEY",
 			"index":  "KEY",
@@ -111,6 +116,7 @@ func (grammarSQL *SQL) setup(db *sqlx.DB, config *dbal.Config, option *dbal.Opti
 	grammarSQL.DB = db
 	grammarSQL.Config = config
 	grammarSQL.Option = option
+	grammarSQL.Version = &dbal.Version{}
 	uinfo, err := url.Parse(grammarSQL.Config.DSN)
 	if err != nil {
 		return err
@@ -144,11 +150,58 @@ func (grammarSQL SQL) NewWithRead(write *sqlx.DB, writeConfig *dbal.Config, read
 	return grammarSQL, nil
 }
 
+// WithContext Create a new grammar interface with the given context, the context will be used for executing the statements.
+func (grammarSQL SQL) WithContext(ctx context.Context) dbal.Grammar {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	grammarSQL.Context = ctx
+	return grammarSQL
+}
+
+// WithTransaction Create a new grammar interface with the given transaction, the statements will be executed within the transaction.
+func (grammarSQL SQL) WithTransaction(tx *sqlx.Tx) dbal.Grammar {
+	grammarSQL.Tx = tx
+	return grammarSQL
+}
+
+// Executor Get the statement executor, returns the transaction if the grammar is bound to a transaction.
+func (grammarSQL SQL) Executor() dbal.Executor {
+	if grammarSQL.Tx != nil {
+		return grammarSQL.Tx
+	}
+	return grammarSQL.DB
+}
+
 // OnConnected the event will be triggered when db server was connected
 func (grammarSQL SQL) OnConnected() error {
 	return nil
 }
 
+// CacheVersion cache the version of the database, the copies of the grammar share the cached version.
+func (grammarSQL SQL) CacheVersion(version *dbal.Version) {
+	if grammarSQL.Version != nil && version != nil {
+		*grammarSQL.Version = *version
+	}
+}
+
+// CachedVersion get the cached version of the database, return nil if the version has not been cached.
+func (grammarSQL SQL) CachedVersion() *dbal.Version {
+	if grammarSQL.Version == nil || grammarSQL.Version.Driver == "" {
+		return nil
+	}
+	version := *grammarSQL.Version
+	return &version
+}
+
+// version get the version cached when the db server was connected, the version is queried if it has not been cached.
+func (grammarSQL SQL) version() (*dbal.Version, error) {
+	if version := grammarSQL.CachedVersion(); version != nil {
+		return version, nil
+	}
+	return grammarSQL.GetVersion()
+}
+
 // GetOperators get the operators
 func (grammarSQL SQL) GetOperators() []string {
 	return []string{
@@ -161,6 +214,11 @@ func (grammarSQL SQL) GetOperators() []string {
 	}
 }
 
+// GetMaxPlaceholders get the maximum number of the placeholders in a statement. (MySQL and PostgreSQL: 65535)
+func (grammarSQL SQL) GetMaxPlaceholders() int {
+	return 65535
+}
+
 // Wrap a value in keyword identifiers.
 func (grammarSQL SQL) Wrap(value interface{}) string {
 	return grammarSQL.Quoter.Wrap(value)
@@ -0,0 +1,18 @@
+package sql
+
+import "fmt"
+
+// CompileSavepoint Compile the SQL statement to define a savepoint.
+func (grammarSQL SQL) CompileSavepoint(name string) string {
+	return fmt.Sprintf("savepoint %s", name)
+}
+
+// CompileSavepointRollBack Compile the SQL statement to execute a savepoint rollback.
+func (grammarSQL SQL) CompileSavepointRollBack(name string) string {
+	return fmt.Sprintf("rollback to savepoint %s", name)
+}
+
+// CompileSavepointRelease Compile the SQL statement to release a savepoint.
+func (grammarSQL SQL) CompileSavepointRelease(name string) string {
+	return fmt.Sprintf("release savepoint %s", name)
+}
@@ -1,10 +1,14 @@
 package sql
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
 )
 
 // CompileUpsert Compile an "upsert" statement into SQL.
@@ -21,7 +25,7 @@ func (grammarSQL SQL) CompileUpdate(query *dbal.Query, values map[string]interfa
 
 	joins := ""
 	if len(query.Joins) > 0 {
-		joins = grammarSQL.CompileJoins(query, query.Joins, &offset)
+		joins = fmt.Sprintf("%s ", grammarSQL.CompileJoins(query, query.Joins, &offset))
 		bindings = append(bindings, query.GetBindings("join")...)
 		offset = len(bindings)
 	}
@@ -32,14 +36,182 @@ func (grammarSQL SQL) CompileUpdate(query *dbal.Query, values map[string]interfa
 	wheres := grammarSQL.CompileWheres(query, query.Wheres, &offset)
 	bindings = append(bindings, query.GetBindings("where")...)
 
+	// update `users` inner join `posts` on `posts`.`user_id` = `users`.`id` set `vote`=? where `posts`.`vote` > ?
 	return fmt.Sprintf("update %s %sset %s %s", table, joins, columns, wheres), bindings
 }
 
+// CompileUpdateBatch Compile a batch update statement into SQL, the rows are matched by the key column.
+// update `users` set `vote`=case when `id`=? then ? when `id`=? then ? else `vote` end where `id` in (?,?)
+func (grammarSQL SQL) CompileUpdateBatch(query *dbal.Query, key string, columns []interface{}, values [][]interface{}) (string, []interface{}) {
+	return grammarSQL.CompileUpdateBatchCases(grammarSQL, query, key, columns, values)
+}
+
+// CompileUpdateBatchCases Compile a batch update statement with the "case when" columns, the where clauses are compiled by the given grammar.
+func (grammarSQL SQL) CompileUpdateBatchCases(grammar dbal.Grammar, query *dbal.Query, key string, columns []interface{}, values [][]interface{}) (string, []interface{}) {
+	offset := 0
+	sets, bindings := grammarSQL.CompileUpdateBatchColumns(query, key, columns, values, &offset)
+	in, inBindings := grammarSQL.CompileUpdateBatchKeys(query, key, columns, values, &offset)
+	bindings = append(bindings, inBindings...)
+
+	sql := fmt.Sprintf("update %s set %s where %s", grammarSQL.WrapTable(query.From), sets, in)
+	wheres := grammar.CompileWheres(query, query.Wheres, &offset)
+	if wheres != "" {
+		sql = fmt.Sprintf("%s and (%s)", sql, strings.TrimPrefix(wheres, "where "))
+		bindings = append(bindings, query.GetBindings("where")...)
+	}
+	return sql, bindings
+}
+
+// CompileUpdateBatchColumns Compile the "case when" columns for a batch update statement.
+func (grammarSQL SQL) CompileUpdateBatchColumns(query *dbal.Query, key string, columns []interface{}, values [][]interface{}, offset *int) (string, []interface{}) {
+	index := grammarSQL.UpdateBatchKeyIndex(key, columns)
+	wrappedKey := grammarSQL.Wrap(key)
+	sets := []string{}
+	bindings := []interface{}{}
+	for i, column := range columns {
+		if i == index {
+			continue
+		}
+
+		wrapped := grammarSQL.Wrap(column)
+		cases := []string{}
+		for _, row := range values {
+			*offset++
+			keyParam := grammarSQL.Parameter(row[index], *offset)
+			bindings = append(bindings, row[index])
+
+			value := row[i]
+			if dbal.IsExpression(value) {
+				cases = append(cases, fmt.Sprintf("when %s=%s then %s", wrappedKey, keyParam, value.(dbal.Expression).GetValue()))
+				continue
+			}
+
+			*offset++
+			cases = append(cases, fmt.Sprintf("when %s=%s then %s", wrappedKey, keyParam, grammarSQL.Parameter(value, *offset)))
+			bindings = append(bindings, value)
+		}
+		sets = append(sets, fmt.Sprintf("%s=case %s else %s end", wrapped, strings.Join(cases, " "), wrapped))
+	}
+	return strings.Join(sets, ", "), bindings
+}
+
+// CompileUpdateBatchKeys Compile the "where in" clause of the keys for a batch update statement.
+func (grammarSQL SQL) CompileUpdateBatchKeys(query *dbal.Query, key string, columns []interface{}, values [][]interface{}, offset *int) (string, []interface{}) {
+	index := grammarSQL.UpdateBatchKeyIndex(key, columns)
+	keys := []interface{}{}
+	for _, row := range values {
+		keys = append(keys, row[index])
+	}
+	in := fmt.Sprintf("%s in (%s)", grammarSQL.Wrap(key), grammarSQL.Parameterize(keys, *offset))
+	*offset = *offset + len(keys)
+	return in, keys
+}
+
+// UpdateBatchKeyIndex Get the index of the key column in the batch update columns.
+func (grammarSQL SQL) UpdateBatchKeyIndex(key string, columns []interface{}) int {
+	for i, column := range columns {
+		if fmt.Sprintf("%v", column) == key {
+			return i
+		}
+	}
+	panic(fmt.Errorf("The key column %s is not in the update columns", key))
+}
+
+// FromReference Get the reference name of the table. (the alias or the table name)
+func (grammarSQL SQL) FromReference(query *dbal.Query) string {
+	if query.From.Alias != "" {
+		return query.From.Alias
+	}
+	switch name := query.From.Name.(type) {
+	case dbal.Name:
+		return name.Fullname()
+	case dbal.Expression:
+		return name.GetValue()
+	default:
+		return fmt.Sprintf("%v", name)
+	}
+}
+
+// IsJoinsAsFrom Determine if the joins could be compiled into the "update ... from" or "delete ... using" statement. (inner and cross joins without nested and lateral joins)
+func (grammarSQL SQL) IsJoinsAsFrom(query *dbal.Query) bool {
+	if len(query.Joins) == 0 || query.Limit >= 0 {
+		return false
+	}
+	for _, join := range query.Joins {
+		if (join.Type != "inner" && join.Type != "cross") || join.Lateral {
+			return false
+		}
+		if join.Query != nil && len(join.Query.Joins) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// CompileJoinsAsFrom Compile the joins into the tables of the "from" or "using" clause and the conditions of the "where" clause.
+// The bindings of the tables and the bindings of the conditions are returned separately.
+// update "users" set "vote"=$1 from "posts" where ("posts"."user_id" = "users"."id")
+func (grammarSQL SQL) CompileJoinsAsFrom(query *dbal.Query, offset *int) (string, string, []interface{}, []interface{}) {
+	tables := []string{}
+	conditions := []string{}
+	tableBindings := []interface{}{}
+	conditionBindings := []interface{}{}
+	bindings := query.GetBindings("join")
+	used := 0
+	for _, join := range query.Joins {
+		start := *offset
+		table := grammarSQL.WrapTable(join.Name)
+		if join.SQL != nil && join.Alias != "" {
+			sql := grammarSQL.CompileSub(join.SQL, offset)
+			table = fmt.Sprintf("(%s) as %s", sql, join.Alias)
+		}
+		tables = append(tables, table)
+		tableBindings = append(tableBindings, bindings[used:used+*offset-start]...)
+		used = used + *offset - start
+
+		start = *offset
+		wheres := grammarSQL.CompileWheres(join.Query, join.Query.Wheres, offset)
+		if wheres != "" {
+			conditions = append(conditions, fmt.Sprintf("(%s)", strings.TrimPrefix(wheres, "on ")))
+		}
+		conditionBindings = append(conditionBindings, bindings[used:used+*offset-start]...)
+		used = used + *offset - start
+	}
+	return strings.Join(tables, ", "), strings.Join(conditions, " and "), tableBindings, conditionBindings
+}
+
+// MergeJoinsAsFromWheres Merge the conditions of the joins with the compiled where clauses of the query.
+func (grammarSQL SQL) MergeJoinsAsFromWheres(conditions string, wheres string) string {
+	wheres = strings.TrimPrefix(wheres, "where ")
+	if conditions == "" && wheres == "" {
+		return ""
+	} else if conditions == "" {
+		return fmt.Sprintf("where %s", wheres)
+	} else if wheres == "" {
+		return fmt.Sprintf("where %s", conditions)
+	}
+	return fmt.Sprintf("where %s and (%s)", conditions, wheres)
+}
+
 // CompileUpdateColumns Compile the columns for an update statement.
 func (grammarSQL SQL) CompileUpdateColumns(query *dbal.Query, values map[string]interface{}, offset *int) (string, []interface{}) {
+	return grammarSQL.CompileUpdateValues(grammarSQL, query, values, offset)
+}
+
+// CompileUpdateValues Compile the columns for an update statement, the JSON selectors are compiled by the given grammar.
+func (grammarSQL SQL) CompileUpdateValues(grammar dbal.Grammar, query *dbal.Query, values map[string]interface{}, offset *int) (string, []interface{}) {
 	columns := []string{}
 	bindings := []interface{}{}
-	for key, value := range values {
+	keys, selectors := grammarSQL.GroupJSONUpdateColumns(values)
+	for _, key := range keys {
+		if _, has := selectors[key]; has {
+			column, columnBindings := grammar.CompileJSONUpdateColumn(key, selectors[key], values, offset)
+			columns = append(columns, column)
+			bindings = append(bindings, columnBindings...)
+			continue
+		}
+
+		value := values[key]
 		columns = append(columns, fmt.Sprintf("%s=%s", grammarSQL.Wrap(key), grammarSQL.Parameter(value, *offset+1)))
 		if !dbal.IsExpression(value) {
 			bindings = append(bindings, value)
@@ -48,3 +220,75 @@ func (grammarSQL SQL) CompileUpdateColumns(query *dbal.Query, values map[string]
 	}
 	return strings.Join(columns, ", "), bindings
 }
+
+// CompileJSONUpdateColumn Compile the JSON selectors of a column for an update statement.
+// `options`=json_set(ifnull(`options`, json_object()), '$."enabled"', true, '$."language"', ?)
+func (grammarSQL SQL) CompileJSONUpdateColumn(column string, selectors []string, values map[string]interface{}, offset *int) (string, []interface{}) {
+	field := grammarSQL.Wrap(column)
+	segments := []string{}
+	bindings := []interface{}{}
+	for _, selector := range selectors {
+		_, path := grammarSQL.WrapJSONFieldAndPath(selector)
+		value := values[selector]
+		if dbal.IsExpression(value) {
+			segments = append(segments, fmt.Sprintf("%s, %s", path, value.(dbal.Expression).GetValue()))
+			continue
+		}
+
+		if isBool, ok := value.(bool); ok {
+			segments = append(segments, fmt.Sprintf("%s, %v", path, isBool))
+			continue
+		}
+
+		*offset++
+		if grammarSQL.IsJSONDocument(value) {
+			bytes, err := json.Marshal(value)
+			utils.PanicIF(err)
+			value = string(bytes)
+			segments = append(segments, fmt.Sprintf("%s, cast(%s as json)", path, grammarSQL.Parameter(value, *offset)))
+		} else {
+			segments = append(segments, fmt.Sprintf("%s, %s", path, grammarSQL.Parameter(value, *offset)))
+		}
+		bindings = append(bindings, value)
+	}
+	return fmt.Sprintf("%s=json_set(ifnull(%s, json_object()), %s)", field, field, strings.Join(segments, ", ")), bindings
+}
+
+// GroupJSONUpdateColumns Sort the columns of the update values, and group the JSON selectors by the column they belong to.
+// eg: name, options->enabled, options->language => [name, options], {options: [options->enabled, options->language]}
+func (grammarSQL SQL) GroupJSONUpdateColumns(values map[string]interface{}) ([]string, map[string][]string) {
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	columns := []string{}
+	selectors := map[string][]string{}
+	for _, key := range keys {
+		if !dbal.IsJSONSelector(key) {
+			columns = append(columns, key)
+			continue
+		}
+
+		column, _, _ := dbal.ParseJSONSelector(key)
+		if _, has := selectors[column]; !has {
+			columns = append(columns, column)
+		}
+		selectors[column] = append(selectors[column], key)
+	}
+	return columns, selectors
+}
+
+// IsJSONDocument Determine if the given value should be bound as a JSON document. (map, slice, array and struct)
+func (grammarSQL SQL) IsJSONDocument(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		_, isBytes := value.([]byte)
+		return !isBytes
+	}
+	return false
+}
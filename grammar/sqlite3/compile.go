@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/blang/semver/v4"
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
 )
@@ -32,6 +33,10 @@ func (grammarSQL SQLite3) CompileSelectOffset(query *dbal.Query, offset *int) st
 		return grammarSQL.CompileUnionAggregate(query)
 	}
 
+	// The common table expressions should be compiled first, because
+	// their bindings are placed before the bindings of the select.
+	with := grammarSQL.CompileWith(query, query.CTEs, offset)
+
 	sqls := map[string]string{}
 
 	// If the query does not have any columns set, we'll set the columns to the
@@ -52,13 +57,14 @@ func (grammarSQL SQLite3) CompileSelectOffset(query *dbal.Query, offset *int) st
 	sqls["wheres"] = grammarSQL.CompileWheres(query, query.Wheres, offset)
 	sqls["groups"] = grammarSQL.CompileGroups(query, query.Groups, offset)
 	sqls["havings"] = grammarSQL.CompileHavings(query, query.Havings, offset)
+	sqls["windows"] = grammarSQL.CompileWindows(query, query.Windows, offset)
 	sqls["orders"] = grammarSQL.CompileOrders(query, query.Orders, offset)
 	sqls["limit"] = grammarSQL.CompileLimit(query, query.Limit, offset)
 	sqls["offset"] = grammarSQL.CompileOffset(query, query.Offset)
 	sqls["lock"] = grammarSQL.CompileLock(query, query.Lock)
 
 	sql := ""
-	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "orders", "limit", "offset", "lock"} {
+	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "windows", "orders", "limit", "offset", "lock"} {
 		segment, has := sqls[name]
 		if has && segment != "" {
 			sql = sql + segment + " "
@@ -70,11 +76,25 @@ func (grammarSQL SQLite3) CompileSelectOffset(query *dbal.Query, offset *int) st
 		sql = fmt.Sprintf("%s %s", grammarSQL.WrapUnion(sql), grammarSQL.CompileUnions(query, query.Unions, offset))
 	}
 
+	if with != "" {
+		sql = fmt.Sprintf("%s %s", with, sql)
+	}
+
 	// reset columns
 	query.Columns = columns
 	return strings.Trim(sql, " ")
 }
 
+// CompileWith Compile the common table expressions of the query.
+func (grammarSQL SQLite3) CompileWith(query *dbal.Query, ctes []dbal.CTE, offset *int) string {
+	return grammarSQL.CompileCTEs(grammarSQL, query, ctes, offset, false)
+}
+
+// CompileOrders Compile the "order by" portions of the query.
+func (grammarSQL SQLite3) CompileOrders(query *dbal.Query, orders []dbal.Order, bindingOffset *int) string {
+	return grammarSQL.CompileOrderBy(grammarSQL, query, orders, bindingOffset)
+}
+
 // CompileWheres Compile an update statement into SQL.
 func (grammarSQL SQLite3) CompileWheres(query *dbal.Query, wheres []dbal.Where, bindingOffset *int) string {
 
@@ -151,7 +171,189 @@ func (grammarSQL SQLite3) WhereDateBased(typ string, query *dbal.Query, where db
 	return fmt.Sprintf("strftime('%s',%s) %s cast(%s as text)", typ, grammarSQL.Wrap(where.Column), where.Operator, value)
 }
 
+// WhereJSONContains Compile a "where JSON contains" clause.
+func (grammarSQL SQLite3) WhereJSONContains(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	not := ""
+	if where.Not {
+		not = "not "
+	}
+	field, path := grammarSQL.WrapJSONFieldAndPath(fmt.Sprintf("%v", where.Column))
+	value := grammarSQL.WhereJSONValue(where, bindingOffset)
+
+	// SQLite does not support json_contains, so we check that every item of the given
+	// value is also an item of the column value.
+	return fmt.Sprintf(
+		"%snot exists (select 1 from json_each(%s) as `contains` where `contains`.`value` not in (select `value` from json_each(%s, %s)))",
+		not, value, field, path,
+	)
+}
+
+// WhereJSONLength Compile a "where JSON length" clause.
+func (grammarSQL SQLite3) WhereJSONLength(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	field, path := grammarSQL.WrapJSONFieldAndPath(fmt.Sprintf("%v", where.Column))
+	value := grammarSQL.WhereJSONValue(where, bindingOffset)
+	// json_array_length(`options`, '$."languages"') > ?
+	return fmt.Sprintf("json_array_length(%s, %s) %s %s", field, path, where.Operator, value)
+}
+
+// CompileReturning Compile the "returning" portion of the insert, update and delete statements. (SQLite 3.35.0+)
+func (grammarSQL SQLite3) CompileReturning(query *dbal.Query, columns []interface{}) (string, error) {
+	version, err := grammarSQL.version()
+	if err != nil {
+		return "", err
+	}
+
+	sqlite3_35, _ := semver.Make("3.35.0")
+	if version.LT(sqlite3_35) {
+		return "", fmt.Errorf("This database engine does not support returning, SQLite 3.35.0+ is required (current: %s)", version.String())
+	}
+
+	if len(columns) == 0 {
+		return "returning *", nil
+	}
+	return fmt.Sprintf("returning %s", grammarSQL.Columnize(columns)), nil
+}
+
+// WhereFullText Compile a "where fulltext" clause. (the table should be a FTS5 virtual table)
+func (grammarSQL SQLite3) WhereFullText(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	*bindingOffset = *bindingOffset + where.Offset
+	fulltext := where.Column.(dbal.FullText)
+	value := grammarSQL.Parameter(where.Value, *bindingOffset)
+
+	columns := []string{}
+	for _, column := range fulltext.Columns {
+		name := fmt.Sprintf("%v", column)
+		if dbal.IsExpression(column) {
+			name = column.(dbal.Expression).GetValue()
+		}
+		if pos := strings.LastIndex(name, "."); pos >= 0 {
+			name = name[pos+1:]
+		}
+		columns = append(columns, name)
+	}
+
+	if len(columns) == 0 {
+		// `docs` match ?
+		return fmt.Sprintf("%s match %s", grammarSQL.FullTextTable(query), value)
+	}
+
+	// `docs` match '{title body} : (' || ? || ')'
+	return fmt.Sprintf("%s match '{%s} : (' || %s || ')'", grammarSQL.FullTextTable(query), strings.Join(columns, " "), value)
+}
+
+// CompileFullTextOrder Compile the full text search relevance order into SQL. (bm25 returns the lower value for the more relevant rows)
+func (grammarSQL SQLite3) CompileFullTextOrder(query *dbal.Query, fulltext dbal.FullText, value interface{}, offset int) (string, []interface{}) {
+	// bm25(`docs`) asc
+	return fmt.Sprintf("bm25(%s) asc", grammarSQL.FullTextTable(query)), []interface{}{}
+}
+
+// FullTextTable Get the FTS5 table reference of the query. eg: `docs` or `d`.`docs` (the subqueries are reported by CheckQuery)
+func (grammarSQL SQLite3) FullTextTable(query *dbal.Query) string {
+	name, ok := query.From.Name.(dbal.Name)
+	if !ok {
+		return ""
+	}
+
+	if name.As() != "" {
+		return fmt.Sprintf("%s.%s", grammarSQL.ID(name.As()), grammarSQL.ID(name.Fullname()))
+	}
+	return grammarSQL.ID(name.Fullname())
+}
+
+// checkFullText the full text search requires the query selects from a FTS5 table
+func (grammarSQL SQLite3) checkFullText(query *dbal.Query) error {
+	if _, ok := query.From.Name.(dbal.Name); ok {
+		return nil
+	}
+
+	for _, order := range append(query.CopyOrders(), query.UnionOrders...) {
+		if order.Type == "fulltext" {
+			return fmt.Errorf("The full text search requires a FTS5 table")
+		}
+	}
+
+	for _, where := range query.Wheres {
+		if where.Type == "fullText" {
+			return fmt.Errorf("The full text search requires a FTS5 table")
+		} else if where.Type == "nested" && where.Query != nil {
+			err := grammarSQL.checkFullText(where.Query)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CompileRandom Compile the random statement into SQL. (the seed is not supported, it is reported by CheckQuery)
+func (grammarSQL SQLite3) CompileRandom(seed string) string {
+	return "RANDOM()"
+}
+
 // CompileLock the lock into SQL.
 func (grammarSQL SQLite3) CompileLock(query *dbal.Query, lock interface{}) string {
+	// SQLite locks the whole database, the unsupported row locking clauses are reported by CheckQuery.
 	return ""
 }
+
+// CheckQuery Check if the features used by the select statement are supported by the database engine.
+func (grammarSQL SQLite3) CheckQuery(query *dbal.Query) error {
+	err := grammarSQL.checkJoins(query.Joins)
+	if err != nil {
+		return err
+	}
+
+	err = grammarSQL.checkWindows(query)
+	if err != nil {
+		return err
+	}
+
+	if query.HasRandomSeed() {
+		return fmt.Errorf("This database engine does not support the seed of the random order")
+	}
+
+	err = grammarSQL.checkFullText(query)
+	if err != nil {
+		return err
+	}
+	return grammarSQL.checkLock(query.Lock)
+}
+
+// checkWindows the window functions require SQLite 3.25.0+
+func (grammarSQL SQLite3) checkWindows(query *dbal.Query) error {
+	if !query.HasWindowFunctions() {
+		return nil
+	}
+
+	version, err := grammarSQL.version()
+	if err != nil {
+		return err
+	}
+
+	sqlite325, _ := semver.Make("3.25.0")
+	if version.LT(sqlite325) {
+		return fmt.Errorf("This database engine does not support the window functions, SQLite 3.25.0+ is required (current: %s)", version.String())
+	}
+	return nil
+}
+
+// checkJoins the lateral joins are not supported by SQLite
+func (grammarSQL SQLite3) checkJoins(joins []dbal.Join) error {
+	for _, join := range joins {
+		if join.Lateral {
+			return fmt.Errorf("This database engine does not support lateral joins")
+		}
+	}
+	return nil
+}
+
+// checkLock SQLite locks the whole database file while writing, the shared and update locks could be ignored.
+// But the lock options and the locking tables can't be honored, so the error should be returned.
+func (grammarSQL SQLite3) checkLock(lock interface{}) error {
+	if value, ok := lock.(dbal.Lock); ok {
+		if value.Type == "no key update" || len(value.Of) > 0 || value.Option != "" {
+			return fmt.Errorf("This database engine does not support the row locking clause: %s", grammarSQL.CompileRowLock(value))
+		}
+	}
+	return nil
+}
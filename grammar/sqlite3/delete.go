@@ -17,17 +17,13 @@ func (grammarSQL SQLite3) CompileDelete(query *dbal.Query) (string, []interface{
 	bindings := []interface{}{}
 	table := grammarSQL.WrapTable(query.From)
 
-	alias := query.From.Alias
-	if alias != "" {
-		query.Columns = []interface{}{fmt.Sprintf("%s.rowid", alias)}
-	} else {
-		query.Columns = []interface{}{"rowid"}
-	}
+	// SQLite does not support the "delete ... using" statement, the joins are rewritten into the subquery of the row ids
+	query.Columns = []interface{}{fmt.Sprintf("%s.rowid", grammarSQL.FromReference(query))}
 
 	selectSQL := grammarSQL.CompileSelectOffset(query, &offset)
 
 	bindings = append(bindings, query.GetBindings()...)
-	sql := fmt.Sprintf("delete from  %s where %s in (%s)", table, grammarSQL.Wrap("rowid"), selectSQL)
+	sql := fmt.Sprintf("delete from %s where %s in (%s)", table, grammarSQL.Wrap("rowid"), selectSQL)
 
 	return sql, bindings
 }
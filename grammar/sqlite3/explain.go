@@ -0,0 +1,56 @@
+package sqlite3
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/grammar/sql"
+)
+
+// explainDetail the detail of the query plan. eg: SCAN TABLE users, SEARCH users USING INDEX users_email_unique (email=?)
+var explainDetail = regexp.MustCompile(`^(SCAN|SEARCH) (?:TABLE )?([^\s()]+)(?: AS [^\s()]+)?(.*)$`)
+
+// explainIndex the index of the query plan detail. eg: USING COVERING INDEX users_email_unique, USING INTEGER PRIMARY KEY
+var explainIndex = regexp.MustCompile(`USING (?:AUTOMATIC )?(?:PARTIAL )?(?:COVERING )?INDEX ([^\s()]+)|USING (INTEGER PRIMARY KEY|ROWID SEARCH)|(VIRTUAL TABLE INDEX [^\s()]+)`)
+
+// CompileExplain Compile an explain statement of the given select SQL. eg: explain query plan select * from `users`
+func (grammarSQL SQLite3) CompileExplain(query string, options map[string]interface{}) string {
+	return fmt.Sprintf("explain query plan %s", query)
+}
+
+// ProcessExplain Normalize the results of the explain statement. (id, parent, notused, detail)
+func (grammarSQL SQLite3) ProcessExplain(rows []map[string]interface{}, options map[string]interface{}) (*dbal.Plan, error) {
+	plan := &dbal.Plan{Nodes: []*dbal.PlanNode{}, Raw: rows}
+	nodes := map[string]*dbal.PlanNode{}
+	for _, row := range rows {
+		node := grammarSQL.explainNode(sql.ExplainString(row["detail"]))
+		nodes[sql.ExplainString(row["id"])] = node
+
+		parent, has := nodes[sql.ExplainString(row["parent"])]
+		if !has {
+			plan.Nodes = append(plan.Nodes, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	return plan, nil
+}
+
+// explainNode parse the detail of the query plan
+func (grammarSQL SQLite3) explainNode(detail string) *dbal.PlanNode {
+	node := &dbal.PlanNode{Operation: detail, Detail: detail, Children: []*dbal.PlanNode{}}
+	matches := explainDetail.FindStringSubmatch(detail)
+	if len(matches) == 0 || matches[2] == "SUBQUERY" || matches[2] == "CONSTANT" {
+		return node
+	}
+
+	node.Operation = matches[1]
+	node.Table = matches[2]
+	if index := explainIndex.FindStringSubmatch(matches[3]); len(index) > 0 {
+		node.Index = strings.Join(index[1:], "")
+	}
+	node.FullScan = node.Operation == "SCAN" && node.Index == ""
+	return node
+}
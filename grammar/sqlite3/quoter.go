@@ -2,7 +2,9 @@ package sqlite3
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/grammar/sql"
 )
 
@@ -11,7 +13,38 @@ type Quoter struct {
 	sql.Quoter
 }
 
+// Wrap a value in keyword identifiers.
+func (quoter *Quoter) Wrap(value interface{}) string {
+	if dbal.IsJSONSelector(value) {
+		name := dbal.NewName(value.(string))
+		if name.As() != "" {
+			return fmt.Sprintf("%s as %s", quoter.WrapJSONSelector(name.Name), quoter.ID(name.As()))
+		}
+		return quoter.WrapJSONSelector(name.Name)
+	}
+	return quoter.Quoter.Wrap(value)
+}
+
+// WrapJSONSelector Wrap the given JSON selector.
+// eg: options->language: json_quote(json_extract(`options`, '$."language"')), options->>language: json_extract(`options`, '$."language"')
+func (quoter *Quoter) WrapJSONSelector(value string) string {
+	field, path := quoter.WrapJSONFieldAndPath(value)
+	if _, _, unquote := dbal.ParseJSONSelector(value); unquote {
+		return fmt.Sprintf("json_extract(%s, %s)", field, path)
+	}
+	return fmt.Sprintf("json_quote(json_extract(%s, %s))", field, path)
+}
+
 // WrapUnion a union subquery in parentheses.
 func (quoter *Quoter) WrapUnion(sql string) string {
 	return fmt.Sprintf("select * from (%s)", sql)
 }
+
+// Columnize Convert an array of column names into a delimited string.
+func (quoter *Quoter) Columnize(columns []interface{}) string {
+	wrapColumns := []string{}
+	for _, col := range columns {
+		wrapColumns = append(wrapColumns, quoter.Wrap(col))
+	}
+	return strings.Join(wrapColumns, ", ")
+}
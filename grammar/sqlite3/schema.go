@@ -18,7 +18,7 @@ func (grammarSQL SQLite3) GetVersion() (*dbal.Version, error) {
 	sql := fmt.Sprintf("SELECT SQLITE_VERSION()")
 	// defer logger.Debug(logger.RETRIEVE, sql).TimeCost(time.Now())
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &rows, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +42,7 @@ func (grammarSQL SQLite3) GetTables() ([]string, error) {
 	sql := fmt.Sprintf("SELECT `name` FROM `sqlite_master` WHERE type='table'")
 	defer log.Debug(sql)
 	tables := []string{}
-	err := grammarSQL.DB.Select(&tables, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &tables, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +54,7 @@ func (grammarSQL SQLite3) TableExists(name string) (bool, error) {
 	sql := fmt.Sprintf("SELECT `name` FROM `sqlite_master` WHERE type='table' AND name=%s", grammarSQL.VAL(name))
 	defer log.Debug(sql)
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &rows, sql)
 	if err != nil {
 		return false, err
 	}
@@ -74,6 +74,8 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table) error {
 	var primary *dbal.Primary = nil
 	columns := []*dbal.Column{}
 	indexes := []*dbal.Index{}
+	foreigns := []*dbal.Foreign{}
+	constraints := []*dbal.Constraint{}
 	cbCommands := []*dbal.Command{}
 
 	// Commands
@@ -85,6 +87,8 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table) error {
 	//    CreateIndex(index *Index) for creating a index
 	//    DropIndex( name string) for  dropping a index
 	//    RenameIndex(old string,new string)  for renaming a index
+	//    CreateForeign(foreign *Foreign) for creating a foreign key
+	//    CreateConstraint(constraint *Constraint) for creating a unique or check constraint
 	for _, command := range table.Commands {
 		switch command.Name {
 		case "AddColumn":
@@ -98,6 +102,12 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table) error {
 		case "CreatePrimary":
 			primary = command.Params[0].(*dbal.Primary)
 			cbCommands = append(cbCommands, command)
+		case "CreateForeign":
+			foreigns = append(foreigns, command.Params[0].(*dbal.Foreign))
+			cbCommands = append(cbCommands, command)
+		case "CreateConstraint":
+			constraints = append(constraints, command.Params[0].(*dbal.Constraint))
+			cbCommands = append(cbCommands, command)
 		}
 	}
 
@@ -115,12 +125,26 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table) error {
 		)
 	}
 
+	// Foreign keys
+	for _, foreign := range foreigns {
+		stmts = append(stmts,
+			grammarSQL.SQLAddForeign(foreign),
+		)
+	}
+
+	// Constraints
+	for _, constraint := range constraints {
+		stmts = append(stmts,
+			grammarSQL.SQLAddConstraint(constraint),
+		)
+	}
+
 	sql = sql + strings.Join(stmts, ",\n")
 	sql = sql + fmt.Sprintf("\n)")
 
 	// Create table
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context, sql)
 	if err != nil {
 		return err
 	}
@@ -133,7 +157,7 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table) error {
 		)
 	}
 	defer log.Debug(strings.Join(indexStmts, ";\n"))
-	_, err = grammarSQL.DB.Exec(strings.Join(indexStmts, ";\n"))
+	_, err = grammarSQL.Executor().ExecContext(grammarSQL.Context, strings.Join(indexStmts, ";\n"))
 
 	for _, cmd := range cbCommands {
 		cmd.Callback(err)
@@ -150,7 +174,7 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table) error {
 func (grammarSQL SQLite3) RenameTable(old string, new string) error {
 	sql := fmt.Sprintf("ALTER TABLE %s RENAME TO %s", grammarSQL.ID(old), grammarSQL.ID(new))
 	defer log.Debug(sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context, sql)
 	return err
 }
 
@@ -177,6 +201,16 @@ func (grammarSQL SQLite3) GetTable(name string) (*dbal.Table, error) {
 		return nil, err
 	}
 
+	foreigns, err := grammarSQL.GetForeignListing(table.DBName, table.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	constraints, err := grammarSQL.GetTableConstraintListing(table.DBName, table.TableName)
+	if err != nil {
+		return nil, err
+	}
+
 	primaryKeyName := ""
 
 	// attaching columns
@@ -185,12 +219,32 @@ func (grammarSQL SQLite3) GetTable(name string) (*dbal.Table, error) {
 		table.PushColumn(column)
 	}
 
+	// attaching constraints
+	for _, c := range constraints {
+		if !table.HasConstraint(c.Name) {
+			constraint := *c
+			constraint.Table = table
+			constraint.Args = []string{}
+			constraint.Columns = []string{}
+			table.PushConstraint(&constraint)
+		}
+		if c.ColumnName != "" {
+			constraint := table.ConstraintMap[c.Name]
+			constraint.Columns = append(constraint.Columns, c.ColumnName)
+		}
+	}
+
 	// attaching indexes
 	for i := range indexes {
 		idx := indexes[i]
 		if !table.HasColumn(idx.ColumnName) {
 			return nil, fmt.Errorf("the column   %s does not exists", idx.ColumnName)
 		}
+
+		// the unique index of the unique constraint. eg: sqlite_autoindex_users_1
+		if idx.Type == "unique" && strings.HasPrefix(idx.Name, "sqlite_autoindex_") {
+			continue
+		}
 		column := table.ColumnMap[idx.ColumnName]
 		if !table.HasIndex(idx.Name) {
 			index := *idx
@@ -223,9 +277,183 @@ func (grammarSQL SQLite3) GetTable(name string) (*dbal.Table, error) {
 		}
 	}
 
+	// attaching foreign keys
+	for _, fk := range foreigns {
+		if !table.HasForeign(fk.Name) {
+			foreign := *fk
+			foreign.Table = table
+			foreign.Columns = []string{}
+			foreign.ReferencedColumns = []string{}
+			table.PushForeign(&foreign)
+		}
+		table.ForeignMap[fk.Name].AddColumn(fk.ColumnName, fk.ReferencedColumnName)
+	}
+
 	return table, nil
 }
 
+// GetForeignListing get a table foreign keys structure (one row per column)
+// The foreign key names are parsed from the CONSTRAINT clauses of the table definition,
+// the name of an unnamed foreign key is "{table}_{columns}_foreign".
+func (grammarSQL SQLite3) GetForeignListing(dbName string, tableName string) ([]*dbal.Foreign, error) {
+	sql := `
+		SELECT
+			id,
+			"table" AS referenced_table_name,
+			"from" AS column_name,
+			IFNULL("to", '') AS referenced_column_name,
+			on_update,
+			on_delete
+		FROM pragma_foreign_key_list(?)
+		ORDER BY id, seq
+	`
+	defer log.Debug(sql)
+	rows := []struct {
+		ID int `db:"id"`
+		dbal.Foreign
+	}{}
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &rows, sql, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return []*dbal.Foreign{}, nil
+	}
+
+	definitions := []string{}
+	err = grammarSQL.Executor().SelectContext(grammarSQL.Context, &definitions, "SELECT `sql` FROM sqlite_master WHERE type='table' AND name=?", tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	// the named foreign keys. eg: CONSTRAINT `posts_user_id_foreign` FOREIGN KEY (`user_id`) ...
+	names := map[string]string{}
+	if len(definitions) > 0 {
+		re := regexp.MustCompile("(?i)CONSTRAINT\\s+[`\"\\[]?([^`\"\\]\\s]+)[`\"\\]]?\\s+FOREIGN\\s+KEY\\s*\\(([^)]*)\\)")
+		for _, matched := range re.FindAllStringSubmatch(definitions[0], -1) {
+			names[foreignColumnsKey(strings.Split(matched[2], ","))] = matched[1]
+		}
+	}
+
+	columns := map[int][]string{}
+	for _, row := range rows {
+		columns[row.ID] = append(columns[row.ID], row.ColumnName)
+	}
+
+	foreigns := []*dbal.Foreign{}
+	for i := range rows {
+		foreign := rows[i].Foreign
+		foreign.DBName = dbName
+		foreign.TableName = tableName
+		foreign.Name = fmt.Sprintf("%s_%s_foreign", tableName, strings.Join(columns[rows[i].ID], "_"))
+		if name, has := names[foreignColumnsKey(columns[rows[i].ID])]; has {
+			foreign.Name = name
+		}
+		foreigns = append(foreigns, &foreign)
+	}
+	return foreigns, nil
+}
+
+// GetTableConstraintListing get a table unique and check constraints structure (one row per column of the unique constraints)
+// The constraints are parsed from the CONSTRAINT clauses of the table definition,
+// the names of the constraints are prefixed with the table name, the prefix will be removed.
+func (grammarSQL SQLite3) GetTableConstraintListing(dbName string, tableName string) ([]*dbal.Constraint, error) {
+	definitions := []string{}
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &definitions, "SELECT `sql` FROM sqlite_master WHERE type='table' AND name=?", tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	constraints := []*dbal.Constraint{}
+	if len(definitions) == 0 {
+		return constraints, nil
+	}
+
+	// CONSTRAINT `users_vote_range` CHECK (vote >= 0), CONSTRAINT `users_name_email` UNIQUE (`name`,`email`)
+	sql := definitions[0]
+	re := regexp.MustCompile("(?i)CONSTRAINT\\s+[`\"\\[]?([^`\"\\]\\s]+)[`\"\\]]?\\s+(CHECK|UNIQUE)\\s*\\(")
+	for _, loc := range re.FindAllStringSubmatchIndex(sql, -1) {
+		open := loc[1] - 1
+		end := clauseEnd(sql, open)
+		if end < 0 {
+			continue
+		}
+
+		constraint := dbal.NewConstraint(dbName, tableName, "")
+		constraint.Name = strings.TrimPrefix(sql[loc[2]:loc[3]], tableName+"_")
+		constraint.Type = strings.ToUpper(sql[loc[4]:loc[5]])
+		content := strings.TrimSpace(sql[open+1 : end])
+		if constraint.Type == "CHECK" {
+			constraint.Expression = content
+			constraints = append(constraints, constraint)
+			continue
+		}
+
+		for _, column := range strings.Split(content, ",") {
+			row := *constraint
+			row.ColumnName = strings.Trim(column, " `\"[]")
+			constraints = append(constraints, &row)
+		}
+	}
+	return constraints, nil
+}
+
+// clauseEnd get the position of the parenthesis which closes the given opening parenthesis, returns -1 if not found
+func clauseEnd(sql string, open int) int {
+	depth := 0
+	for i := open; i < len(sql); i++ {
+		switch sql[i] {
+		case '\'', '"', '`':
+			end := strings.IndexByte(sql[i+1:], sql[i])
+			if end < 0 {
+				return -1
+			}
+			i += end + 1
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// removeForeign remove the clause of the given foreign key from the table definition.
+// The named foreign keys are matched by the names, and the unnamed foreign keys ( {table}_{columns}_foreign ) are matched by the columns.
+func removeForeign(table *dbal.Table, name string, body string) (string, error) {
+	clause := "\\s*REFERENCES\\s+[^\\s(]+\\s*\\([^)]*\\)" +
+		"(\\s+ON\\s+(DELETE|UPDATE)\\s+(SET\\s+NULL|SET\\s+DEFAULT|CASCADE|RESTRICT|NO\\s+ACTION))*"
+
+	re := regexp.MustCompile("(?i),\\s*CONSTRAINT\\s+[`\"\\[]?" + regexp.QuoteMeta(name) + "[`\"\\]]?\\s+FOREIGN\\s+KEY\\s*\\([^)]*\\)" + clause)
+	if re.MatchString(body) {
+		return re.ReplaceAllString(body, ""), nil
+	}
+
+	foreign := table.GetForeign(name)
+	if foreign != nil {
+		re = regexp.MustCompile("(?i),\\s*FOREIGN\\s+KEY\\s*\\(([^)]*)\\)" + clause)
+		for _, loc := range re.FindAllStringSubmatchIndex(body, -1) {
+			if foreignColumnsKey(strings.Split(body[loc[2]:loc[3]], ",")) == foreignColumnsKey(foreign.Columns) {
+				return body[:loc[0]] + body[loc[1]:], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("the foreign key %s does not exists", name)
+}
+
+// foreignColumnsKey the key of the foreign key columns for matching the foreign key names
+func foreignColumnsKey(columns []string) string {
+	names := []string{}
+	for _, column := range columns {
+		names = append(names, strings.ToLower(strings.Trim(column, " `\"[]")))
+	}
+	return strings.Join(names, ",")
+}
+
 // GetIndexListing get a table indexes structure
 func (grammarSQL SQLite3) GetIndexListing(dbName string, tableName string) ([]*dbal.Index, error) {
 	selectColumns := []string{
@@ -280,7 +508,7 @@ func (grammarSQL SQLite3) GetIndexListing(dbName string, tableName string) ([]*d
 	)
 	defer log.Debug(sql)
 	indexes := []*dbal.Index{}
-	err := grammarSQL.DB.Select(&indexes, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &indexes, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -333,7 +561,7 @@ func (grammarSQL SQLite3) GetColumnListing(schemaName string, tableName string)
 	)
 	defer log.Debug(sql)
 	columns := []*dbal.Column{}
-	err := grammarSQL.DB.Select(&columns, sql)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &columns, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -383,6 +611,10 @@ func (grammarSQL SQLite3) AlterTable(table *dbal.Table) error {
 	//    CreateIndex(index *Index) for creating a index
 	//    DropIndex(name string) for  dropping a index
 	//    RenameIndex(old string,new string)  for renaming a index
+	//    CreateForeign(foreign *Foreign) for creating a foreign key (rebuild the table)
+	//    DropForeign(name string) for dropping a foreign key (rebuild the table)
+	//    CreateConstraint(constraint *Constraint) for creating a unique or check constraint (rebuild the table)
+	//    DropConstraint(name string, typ string) for dropping a unique or check constraint (rebuild the table)
 	for _, command := range table.Commands {
 		switch command.Name {
 		case "AddColumn":
@@ -431,6 +663,61 @@ func (grammarSQL SQLite3) AlterTable(table *dbal.Table) error {
 			}
 			command.Callback(err)
 			break
+		case "CreateForeign":
+			foreign := command.Params[0].(*dbal.Foreign)
+			stmt := grammarSQL.SQLAddForeign(foreign)
+			stmts = append(stmts, stmt)
+			err := grammarSQL.RebuildTable(table, func(body string) (string, error) {
+				return body + ",\n" + stmt, nil
+			})
+			if err != nil {
+				errs = append(errs, errors.New("CreateForeign: "+stmt+" ERROR: "+err.Error()))
+			}
+			command.Callback(err)
+			break
+		case "DropForeign":
+			name := command.Params[0].(string)
+			stmts = append(stmts, "DROP FOREIGN KEY "+grammarSQL.ID(name))
+			err := grammarSQL.RebuildTable(table, func(body string) (string, error) {
+				return removeForeign(table, name, body)
+			})
+			if err != nil {
+				errs = append(errs, errors.New("DropForeign: "+name+" ERROR: "+err.Error()))
+			}
+			command.Callback(err)
+			break
+		case "CreateConstraint":
+			constraint := command.Params[0].(*dbal.Constraint)
+			stmt := grammarSQL.SQLAddConstraint(constraint)
+			stmts = append(stmts, stmt)
+			err := grammarSQL.RebuildTable(table, func(body string) (string, error) {
+				return body + ",\n" + stmt, nil
+			})
+			if err != nil {
+				errs = append(errs, errors.New("CreateConstraint: "+stmt+" ERROR: "+err.Error()))
+			}
+			command.Callback(err)
+			break
+		case "DropConstraint":
+			name := fmt.Sprintf("%s_%s", table.TableName, command.Params[0])
+			stmts = append(stmts, "DROP CONSTRAINT "+grammarSQL.ID(name))
+			err := grammarSQL.RebuildTable(table, func(body string) (string, error) {
+				re := regexp.MustCompile("(?i),\\s*CONSTRAINT\\s+[`\"\\[]?" + regexp.QuoteMeta(name) + "[`\"\\]]?\\s+(CHECK|UNIQUE)\\s*\\(")
+				loc := re.FindStringIndex(body)
+				if loc == nil {
+					return "", fmt.Errorf("the constraint %s does not exists", name)
+				}
+				end := clauseEnd(body, loc[1]-1)
+				if end < 0 {
+					return "", fmt.Errorf("the constraint %s can't be parsed", name)
+				}
+				return body[:loc[0]] + body[end+1:], nil
+			})
+			if err != nil {
+				errs = append(errs, errors.New("DropConstraint: "+name+" ERROR: "+err.Error()))
+			}
+			command.Callback(err)
+			break
 		case "DropColumn", "ChangeColumn", "DropPrimary", "RenameIndex":
 			log.Warn("sqlite3 not support %s operation", command.Name)
 			break
@@ -453,7 +740,7 @@ func (grammarSQL SQLite3) AlterTable(table *dbal.Table) error {
 // GetConstraintListing get the constraints of the table
 func (grammarSQL SQLite3) GetConstraintListing(schemaName string, tableName string) (map[string]*dbal.Constraint, error) {
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, "SELECT `sql` FROM sqlite_master WHERE type='table' and name=?", tableName)
+	err := grammarSQL.Executor().SelectContext(grammarSQL.Context, &rows, "SELECT `sql` FROM sqlite_master WHERE type='table' and name=?", tableName)
 	if err != nil {
 		return nil, err
 	}
@@ -497,9 +784,154 @@ func (grammarSQL SQLite3) parseConstraint(schemaName string, tableName string, l
 	return nil
 }
 
+// RebuildTable rebuild the table with the modified definition body then update table structure.
+// SQLite does not support altering the constraints of an existing table, so the table is recreated
+// in a transaction. see https://www.sqlite.org/lang_altertable.html#otheralter
+func (grammarSQL SQLite3) RebuildTable(table *dbal.Table, modify func(body string) (string, error)) error {
+	ctx := grammarSQL.Context
+	rows := []string{}
+	err := grammarSQL.Executor().SelectContext(ctx, &rows, "SELECT `sql` FROM sqlite_master WHERE type='table' AND name=?", table.TableName)
+	if err != nil {
+		return err
+	}
+
+	if len(rows) < 1 {
+		return fmt.Errorf("the table %s does not exists", table.TableName)
+	}
+
+	// CREATE TABLE `name` ( body ) options
+	sql := rows[0]
+	start := strings.Index(sql, "(")
+	end := strings.LastIndex(sql, ")")
+	if start < 0 || end < start {
+		return fmt.Errorf("the definition of the table %s can't be parsed", table.TableName)
+	}
+
+	body, err := modify(sql[start+1 : end])
+	if err != nil {
+		return err
+	}
+
+	indexes := []string{}
+	err = grammarSQL.Executor().SelectContext(ctx, &indexes, "SELECT `sql` FROM sqlite_master WHERE type='index' AND tbl_name=? AND `sql` IS NOT NULL", table.TableName)
+	if err != nil {
+		return err
+	}
+
+	columns := []string{}
+	err = grammarSQL.Executor().SelectContext(ctx, &columns, "SELECT `name` FROM pragma_table_info(?) ORDER BY cid", table.TableName)
+	if err != nil {
+		return err
+	}
+	for i := range columns {
+		columns[i] = grammarSQL.ID(columns[i])
+	}
+
+	name := grammarSQL.ID(table.TableName)
+	temp := grammarSQL.ID(fmt.Sprintf("__xun_rebuild_%s", table.TableName))
+	stmts := []string{
+		fmt.Sprintf("CREATE TABLE %s (%s%s", temp, body, sql[end:]),
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", temp, strings.Join(columns, ","), strings.Join(columns, ","), name),
+		fmt.Sprintf("DROP TABLE %s", name),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", temp, name),
+	}
+	stmts = append(stmts, indexes...)
+	defer log.Debug(strings.Join(stmts, ";\n"))
+
+	// within a transaction, the statements are executed in the transaction, and the foreign keys checking can't be disabled.
+	// Dropping the table with the foreign keys enabled deletes the rows of the child tables (ON DELETE CASCADE), so it should be refused.
+	if grammarSQL.Tx != nil {
+		enabled := 0
+		err = grammarSQL.Tx.QueryRowxContext(ctx, "PRAGMA foreign_keys").Scan(&enabled)
+		if err != nil {
+			return err
+		}
+		if enabled == 1 {
+			return fmt.Errorf("the table %s can't be rebuilt within a transaction while the foreign keys are enabled, please alter it outside of the transaction", table.TableName)
+		}
+
+		for _, stmt := range stmts {
+			_, err = grammarSQL.Tx.ExecContext(ctx, stmt)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		err = grammarSQL.execRebuildStatements(table, stmts)
+		if err != nil {
+			return err
+		}
+	}
+
+	// update table structure
+	new, err := grammarSQL.GetTable(table.TableName)
+	if err != nil {
+		return err
+	}
+
+	*table = *new
+	return nil
+}
+
+// execRebuildStatements execute the statements of the table rebuilding in a new transaction with the foreign keys checking disabled.
+func (grammarSQL SQLite3) execRebuildStatements(table *dbal.Table, stmts []string) error {
+	ctx := grammarSQL.Context
+	name := grammarSQL.ID(table.TableName)
+
+	// the foreign keys checking should be disabled during the rebuilding, and it is a no-op within a transaction.
+	conn, err := grammarSQL.DB.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	enabled := 0
+	err = conn.QueryRowxContext(ctx, "PRAGMA foreign_keys").Scan(&enabled)
+	if err != nil {
+		return err
+	}
+
+	if enabled == 1 {
+		_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
+		if err != nil {
+			return err
+		}
+		defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	}
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range stmts {
+		_, err = tx.ExecContext(ctx, stmt)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if enabled == 1 {
+		violations, err := tx.QueryxContext(ctx, fmt.Sprintf("PRAGMA foreign_key_check(%s)", name))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		violated := violations.Next()
+		violations.Close()
+		if violated {
+			tx.Rollback()
+			return fmt.Errorf("the foreign key constraints of the table %s are violated", table.TableName)
+		}
+	}
+
+	return tx.Commit()
+}
+
 // ExecSQL execute sql then update table structure
 func (grammarSQL SQLite3) ExecSQL(table *dbal.Table, sql string) error {
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.Executor().ExecContext(grammarSQL.Context, sql)
 	if err != nil {
 		return err
 	}
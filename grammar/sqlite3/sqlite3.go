@@ -1,11 +1,13 @@
 package sqlite3
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"path/filepath"
 	"strings"
 
+	"github.com/blang/semver/v4"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3" // Load sqlite3 driver
 	"github.com/yaoapp/xun/dbal"
@@ -49,6 +51,7 @@ func (grammarSQL *SQLite3) setup(db *sqlx.DB, config *dbal.Config, option *dbal.
 	grammarSQL.DB = db
 	grammarSQL.Config = config
 	grammarSQL.Option = option
+	grammarSQL.Version = &dbal.Version{}
 	uinfo, err := url.Parse(grammarSQL.Config.DSN)
 	if err != nil {
 		return err
@@ -82,6 +85,31 @@ func (grammarSQL SQLite3) NewWithRead(write *sqlx.DB, writeConfig *dbal.Config,
 	return grammarSQL, nil
 }
 
+// WithContext Create a new grammar interface with the given context, the context will be used for executing the statements.
+func (grammarSQL SQLite3) WithContext(ctx context.Context) dbal.Grammar {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	grammarSQL.Context = ctx
+	return grammarSQL
+}
+
+// WithTransaction Create a new grammar interface with the given transaction, the statements will be executed within the transaction.
+func (grammarSQL SQLite3) WithTransaction(tx *sqlx.Tx) dbal.Grammar {
+	grammarSQL.Tx = tx
+	return grammarSQL
+}
+
+// OnConnected the event will be triggered when db server was connected
+func (grammarSQL SQLite3) OnConnected() error {
+	version, err := grammarSQL.GetVersion()
+	if err != nil {
+		return err
+	}
+	grammarSQL.CacheVersion(version)
+	return nil
+}
+
 // New Create a new mysql grammar inteface
 func New(opts ...sql.Option) dbal.Grammar {
 	sqlite := SQLite3{
@@ -124,3 +152,25 @@ func (grammarSQL SQLite3) GetOperators() []string {
 		"&", "|", "<<", ">>",
 	}
 }
+
+// GetMaxPlaceholders get the maximum number of the placeholders in a statement. (SQLITE_MAX_VARIABLE_NUMBER, 999 before 3.32.0)
+func (grammarSQL SQLite3) GetMaxPlaceholders() int {
+	version, err := grammarSQL.version()
+	if err != nil {
+		return 999
+	}
+
+	sqlite3_32, _ := semver.Make("3.32.0")
+	if version.LT(sqlite3_32) {
+		return 999
+	}
+	return 32766
+}
+
+// version get the version cached when the db server was connected, the version is queried if it has not been cached.
+func (grammarSQL SQLite3) version() (*dbal.Version, error) {
+	if version := grammarSQL.CachedVersion(); version != nil {
+		return version, nil
+	}
+	return grammarSQL.GetVersion()
+}
@@ -1,11 +1,14 @@
 package sqlite3
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
 
+	"github.com/blang/semver/v4"
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
 )
 
 // CompileUpsert Upsert new records or update the existing ones.
@@ -17,7 +20,7 @@ func (grammarSQL SQLite3) CompileUpsert(query *dbal.Query, columns []interface{}
 
 	sql, bindings := grammarSQL.CompileInsert(query, columns, values)
 	sql = fmt.Sprintf("%s on conflict (%s) do update set", sql, grammarSQL.Columnize(uniqueBy))
-	offset := len(bindings) + 1
+	offset := len(bindings)
 
 	update := reflect.ValueOf(updateValues)
 	kind := update.Kind()
@@ -28,15 +31,13 @@ func (grammarSQL SQLite3) CompileUpsert(query *dbal.Query, columns []interface{}
 			segments = append(segments, fmt.Sprintf("%s=excluded.%s", grammarSQL.Wrap(column), grammarSQL.Wrap(column)))
 		}
 	} else if kind == reflect.Map {
+		values := map[string]interface{}{}
 		for _, key := range update.MapKeys() {
-			column := fmt.Sprintf("%v", key)
-			value := update.MapIndex(key).Interface()
-			segments = append(segments, fmt.Sprintf("%s=%s", grammarSQL.Wrap(column), grammarSQL.Parameter(value, offset)))
-			if !dbal.IsExpression(value) {
-				bindings = append(bindings, value)
-				offset++
-			}
+			values[fmt.Sprintf("%v", key)] = update.MapIndex(key).Interface()
 		}
+		columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
+		segments = append(segments, columns)
+		bindings = append(bindings, columnsBindings...)
 	}
 
 	return fmt.Sprintf("%s %s", sql, strings.Join(segments, ", ")), bindings
@@ -45,24 +46,35 @@ func (grammarSQL SQLite3) CompileUpsert(query *dbal.Query, columns []interface{}
 // CompileUpdate Compile an update statement into SQL.
 func (grammarSQL SQLite3) CompileUpdate(query *dbal.Query, values map[string]interface{}) (string, []interface{}) {
 
-	if len(query.Joins) == 0 && query.Limit < 0 {
-		return grammarSQL.SQL.CompileUpdate(query, values)
-	}
-
 	offset := 0
 	bindings := []interface{}{}
 	table := grammarSQL.WrapTable(query.From)
 
-	columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
-	bindings = append(bindings, columnsBindings...)
+	if len(query.Joins) == 0 && query.Limit < 0 {
+		columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
+		bindings = append(bindings, columnsBindings...)
+		wheres := grammarSQL.CompileWheres(query, query.Wheres, &offset)
+		bindings = append(bindings, query.GetBindings("where")...)
+		return fmt.Sprintf("update %s set %s %s", table, columns, wheres), bindings
+	}
 
-	alias := query.From.Alias
-	if alias != "" {
-		query.Columns = []interface{}{fmt.Sprintf("%s.rowid", alias)}
-	} else {
-		query.Columns = []interface{}{"rowid"}
+	// update `users` set `vote`=? from `posts` where (`posts`.`user_id` = `users`.`id`) and (`posts`.`vote` > ?) (SQLite 3.33.0+)
+	if grammarSQL.IsJoinsAsFrom(query) && grammarSQL.IsUpdateFromSupported() {
+		columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
+		bindings = append(bindings, columnsBindings...)
+		tables, conditions, tableBindings, conditionBindings := grammarSQL.CompileJoinsAsFrom(query, &offset)
+		bindings = append(bindings, tableBindings...)
+		bindings = append(bindings, conditionBindings...)
+		conditions = grammarSQL.MergeJoinsAsFromWheres(conditions, grammarSQL.CompileWheres(query, query.Wheres, &offset))
+		bindings = append(bindings, query.GetBindings("where")...)
+		return fmt.Sprintf("update %s set %s from %s %s", table, columns, tables, conditions), bindings
 	}
 
+	// the joins are rewritten into the subquery of the row ids
+	columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
+	bindings = append(bindings, columnsBindings...)
+	query.Columns = []interface{}{fmt.Sprintf("%s.rowid", grammarSQL.FromReference(query))}
+
 	selectSQL := grammarSQL.CompileSelectOffset(query, &offset)
 
 	bindings = append(bindings, query.GetBindings()...)
@@ -70,3 +82,56 @@ func (grammarSQL SQLite3) CompileUpdate(query *dbal.Query, values map[string]int
 
 	return sql, bindings
 }
+
+// IsUpdateFromSupported Determine if the "update ... from" statement is supported. (SQLite 3.33.0+)
+func (grammarSQL SQLite3) IsUpdateFromSupported() bool {
+	version, err := grammarSQL.version()
+	if err != nil {
+		return false
+	}
+	sqlite3_33, _ := semver.Make("3.33.0")
+	return version.GE(sqlite3_33)
+}
+
+// CompileUpdateBatch Compile a batch update statement into SQL, the rows are matched by the key column.
+func (grammarSQL SQLite3) CompileUpdateBatch(query *dbal.Query, key string, columns []interface{}, values [][]interface{}) (string, []interface{}) {
+	return grammarSQL.CompileUpdateBatchCases(grammarSQL, query, key, columns, values)
+}
+
+// CompileUpdateColumns Compile the columns for an update statement.
+func (grammarSQL SQLite3) CompileUpdateColumns(query *dbal.Query, values map[string]interface{}, offset *int) (string, []interface{}) {
+	return grammarSQL.CompileUpdateValues(grammarSQL, query, values, offset)
+}
+
+// CompileJSONUpdateColumn Compile the JSON selectors of a column for an update statement.
+// `options`=json_set(ifnull(`options`, json('{}')), '$."enabled"', json('true'), '$."language"', ?)
+func (grammarSQL SQLite3) CompileJSONUpdateColumn(column string, selectors []string, values map[string]interface{}, offset *int) (string, []interface{}) {
+	field := grammarSQL.Wrap(column)
+	segments := []string{}
+	bindings := []interface{}{}
+	for _, selector := range selectors {
+		_, path := grammarSQL.WrapJSONFieldAndPath(selector)
+		value := values[selector]
+		if dbal.IsExpression(value) {
+			segments = append(segments, fmt.Sprintf("%s, %s", path, value.(dbal.Expression).GetValue()))
+			continue
+		}
+
+		if isBool, ok := value.(bool); ok {
+			segments = append(segments, fmt.Sprintf("%s, json('%v')", path, isBool))
+			continue
+		}
+
+		*offset++
+		if grammarSQL.IsJSONDocument(value) {
+			bytes, err := json.Marshal(value)
+			utils.PanicIF(err)
+			value = string(bytes)
+			segments = append(segments, fmt.Sprintf("%s, json(%s)", path, grammarSQL.Parameter(value, *offset)))
+		} else {
+			segments = append(segments, fmt.Sprintf("%s, %s", path, grammarSQL.Parameter(value, *offset)))
+		}
+		bindings = append(bindings, value)
+	}
+	return fmt.Sprintf("%s=json_set(ifnull(%s, json('{}')), %s)", field, field, strings.Join(segments, ", ")), bindings
+}
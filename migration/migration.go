@@ -0,0 +1,379 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/utils"
+)
+
+// DefaultTable the default name of the migrations table
+const DefaultTable = "migrations"
+
+// New create a new migrator using the given schema and query builders, the migrations table is "migrations" by default.
+//
+//	migrator := migration.New(sch, qb)
+//	migrator := migration.New(sch, qb, "schema_migrations")
+func New(sch schema.Schema, qb query.Query, table ...string) *Migrator {
+	name := DefaultTable
+	if len(table) > 0 && table[0] != "" {
+		name = table[0]
+	}
+	return &Migrator{
+		Schema:     sch,
+		Query:      qb,
+		Table:      name,
+		Migrations: []*Migration{},
+	}
+}
+
+// Register register a migration, the migrations run in the order of registration. Panic if the name has been registered.
+//
+//	migrator.Register("2021_01_01_create_users_table", func(sch schema.Schema, qb query.Query) error {
+//		return sch.CreateTable("users", func(table schema.Blueprint) { table.ID("id") })
+//	}, func(sch schema.Schema, qb query.Query) error {
+//		return sch.DropTable("users")
+//	})
+func (migrator *Migrator) Register(name string, up Handler, down Handler) *Migrator {
+	if migrator.GetMigration(name) != nil {
+		panic(fmt.Errorf("the migration %s has been registered", name))
+	}
+	migrator.Migrations = append(migrator.Migrations, &Migration{Name: name, Up: up, Down: down})
+	return migrator
+}
+
+// GetMigration get the registered migration for the given name, if the migration does not exist return nil.
+func (migrator *Migrator) GetMigration(name string) *Migration {
+	for _, migration := range migrator.Migrations {
+		if migration.Name == name {
+			return migration
+		}
+	}
+	return nil
+}
+
+// WithContext create a new migrator instance, the migrations of it will be executed using the given context.
+func (migrator *Migrator) WithContext(ctx context.Context) *Migrator {
+	new := *migrator
+	new.Schema = migrator.Schema.WithContext(ctx)
+	new.Query = migrator.Query.New().WithContext(ctx)
+	return &new
+}
+
+// IsTransactional Determine if the driver supports transactional DDL (Postgres, SQLite)
+func (migrator *Migrator) IsTransactional() bool {
+	driver := migrator.Schema.Builder().Conn.WriteConfig.Driver
+	return driver == "postgres" || driver == "sqlite3"
+}
+
+// Migrate Run the pending migrations within a new batch, return the names of the migrations ran.
+func (migrator *Migrator) Migrate() ([]string, error) {
+	ran := []string{}
+	err := migrator.prepare()
+	if err != nil {
+		return ran, err
+	}
+
+	records, err := migrator.records()
+	if err != nil {
+		return ran, err
+	}
+
+	batch := 1
+	migrated := map[string]bool{}
+	for _, record := range records {
+		migrated[record.Name] = true
+		if record.Batch >= batch {
+			batch = record.Batch + 1
+		}
+	}
+
+	for _, migration := range migrator.Migrations {
+		if migrated[migration.Name] {
+			continue
+		}
+		name := migration.Name
+		err = migrator.run(migrator.Query.GetContext(), migration.Up, func(qb query.Query) error {
+			return qb.Table(migrator.Table).Insert(map[string]interface{}{
+				"migration": name,
+				"batch":     batch,
+			})
+		})
+		if err != nil {
+			return ran, fmt.Errorf("migrate %s: %s", name, err)
+		}
+		ran = append(ran, name)
+	}
+	return ran, nil
+}
+
+// MustMigrate Run the pending migrations within a new batch
+func (migrator *Migrator) MustMigrate() []string {
+	ran, err := migrator.Migrate()
+	utils.PanicIF(err)
+	return ran
+}
+
+// Rollback Rollback the last batches of migrations (1 by default), return the names of the migrations rolled back.
+func (migrator *Migrator) Rollback(steps ...int) ([]string, error) {
+	step := 1
+	if len(steps) > 0 {
+		step = steps[0]
+	}
+	if step < 1 {
+		return []string{}, fmt.Errorf("the steps of the rollback should be greater than 0, %d given", step)
+	}
+	return migrator.rollback(step)
+}
+
+// MustRollback Rollback the last batches of migrations (1 by default)
+func (migrator *Migrator) MustRollback(steps ...int) []string {
+	rolledBack, err := migrator.Rollback(steps...)
+	utils.PanicIF(err)
+	return rolledBack
+}
+
+// Reset Rollback all of the migrations, return the names of the migrations rolled back.
+func (migrator *Migrator) Reset() ([]string, error) {
+	return migrator.rollback(-1)
+}
+
+// MustReset Rollback all of the migrations
+func (migrator *Migrator) MustReset() []string {
+	rolledBack, err := migrator.Reset()
+	utils.PanicIF(err)
+	return rolledBack
+}
+
+// Refresh Rollback all of the migrations and run them all again, return the names of the migrations ran.
+func (migrator *Migrator) Refresh() ([]string, error) {
+	_, err := migrator.Reset()
+	if err != nil {
+		return []string{}, err
+	}
+	return migrator.Migrate()
+}
+
+// MustRefresh Rollback all of the migrations and run them all again
+func (migrator *Migrator) MustRefresh() []string {
+	ran, err := migrator.Refresh()
+	utils.PanicIF(err)
+	return ran
+}
+
+// Status Get the status of the registered migrations
+func (migrator *Migrator) Status() ([]Status, error) {
+	status := []Status{}
+	err := migrator.prepare()
+	if err != nil {
+		return status, err
+	}
+
+	records, err := migrator.records()
+	if err != nil {
+		return status, err
+	}
+
+	batches := map[string]int{}
+	for _, record := range records {
+		batches[record.Name] = record.Batch
+	}
+
+	for _, migration := range migrator.Migrations {
+		batch, ran := batches[migration.Name]
+		status = append(status, Status{Name: migration.Name, Ran: ran, Batch: batch})
+	}
+	return status, nil
+}
+
+// MustStatus Get the status of the registered migrations
+func (migrator *Migrator) MustStatus() []Status {
+	status, err := migrator.Status()
+	utils.PanicIF(err)
+	return status
+}
+
+// rollback Rollback the last batches of migrations, rollback all of the migrations if the step is negative.
+func (migrator *Migrator) rollback(step int) ([]string, error) {
+	rolledBack := []string{}
+	err := migrator.prepare()
+	if err != nil {
+		return rolledBack, err
+	}
+
+	records, err := migrator.records()
+	if err != nil {
+		return rolledBack, err
+	}
+
+	// the records are ordered by batch desc, id desc
+	batches := 0
+	last := 0
+	for _, record := range records {
+		if record.Batch != last {
+			batches++
+			last = record.Batch
+		}
+		if step >= 0 && batches > step {
+			break
+		}
+
+		migration := migrator.GetMigration(record.Name)
+		if migration == nil {
+			return rolledBack, fmt.Errorf("rollback %s: the migration is not registered", record.Name)
+		}
+
+		id := record.ID
+		err = migrator.run(migrator.Query.GetContext(), migration.Down, func(qb query.Query) error {
+			_, err := qb.Table(migrator.Table).Where("id", id).Delete()
+			return err
+		})
+		if err != nil {
+			return rolledBack, fmt.Errorf("rollback %s: %s", record.Name, err)
+		}
+		rolledBack = append(rolledBack, record.Name)
+	}
+	return rolledBack, nil
+}
+
+// prepare create the migrations table if it does not exist
+func (migrator *Migrator) prepare() error {
+	has, err := migrator.Schema.HasTable(migrator.Table)
+	if err != nil || has {
+		return err
+	}
+	return migrator.Schema.CreateTable(migrator.Table, func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("migration", 200).Unique()
+		table.Integer("batch").Index()
+	})
+}
+
+// records get the records of the migrations table, ordered by batch desc, id desc
+func (migrator *Migrator) records() ([]Record, error) {
+	records := []Record{}
+	_, err := migrator.Query.New().
+		Table(migrator.Table).
+		OrderByDesc("batch").
+		OrderByDesc("id").
+		Get(&records)
+	return records, err
+}
+
+// run Execute the handler and the bookkeeping function of a migration, within a transaction if the driver supports transactional DDL.
+// The foreign keys checking of SQLite is disabled within the transaction, and the foreign keys are checked before committing.
+func (migrator *Migrator) run(ctx context.Context, handler Handler, record func(qb query.Query) error) (err error) {
+	if !migrator.IsTransactional() {
+		if handler != nil {
+			err = handler(migrator.Schema, migrator.Query.New())
+			if err != nil {
+				return err
+			}
+		}
+		return record(migrator.Query.New())
+	}
+
+	db, err := migrator.Schema.GetDB()
+	if err != nil {
+		return err
+	}
+
+	// the foreign keys checking is a setting of the connection, and it can't be changed within a transaction.
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	foreignKeys, err := migrator.disableForeignKeys(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if foreignKeys {
+		defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	}
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// the transaction is finished once the commit is attempted, it should not be rolled back
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+		if err != nil && !committed {
+			errRollback := tx.Rollback()
+			if errRollback != nil {
+				err = fmt.Errorf("%s (rollback: %s)", err, errRollback)
+			}
+		}
+	}()
+
+	err = migrator.runWithTransaction(tx, handler, record)
+	if err != nil {
+		return err
+	}
+
+	if foreignKeys {
+		err = migrator.checkForeignKeys(ctx, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	committed = true
+	return tx.Commit()
+}
+
+// disableForeignKeys SQLite rebuilds the table to alter it, the rows of the child tables would be deleted by ON DELETE CASCADE
+// when the table is dropped, so the foreign keys checking of the connection is disabled during the migration.
+// Return true if the foreign keys checking was enabled.
+func (migrator *Migrator) disableForeignKeys(ctx context.Context, conn *sqlx.Conn) (bool, error) {
+	if migrator.Schema.Builder().Conn.WriteConfig.Driver != "sqlite3" {
+		return false, nil
+	}
+
+	enabled := 0
+	err := conn.QueryRowxContext(ctx, "PRAGMA foreign_keys").Scan(&enabled)
+	if err != nil || enabled == 0 {
+		return false, err
+	}
+
+	_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checkForeignKeys check the foreign key constraints of the database before committing the migration (SQLite)
+func (migrator *Migrator) checkForeignKeys(ctx context.Context, tx *sqlx.Tx) error {
+	violations, err := tx.QueryxContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return err
+	}
+	violated := violations.Next()
+	violations.Close()
+	if violated {
+		return fmt.Errorf("the foreign key constraints are violated")
+	}
+	return nil
+}
+
+// runWithTransaction Execute the handler and the bookkeeping function of a migration within the given transaction
+func (migrator *Migrator) runWithTransaction(tx *sqlx.Tx, handler Handler, record func(qb query.Query) error) error {
+	sch := migrator.Schema.WithTransaction(tx)
+	if handler != nil {
+		err := handler(sch, migrator.Query.New().WithTransaction(tx))
+		if err != nil {
+			return err
+		}
+	}
+	return record(migrator.Query.New().WithTransaction(tx))
+}
@@ -0,0 +1,266 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+var testSchema schema.Schema
+var testQuery query.Query
+
+func getTestSchema() schema.Schema {
+	defer unit.Catch()
+	unit.SetLogger()
+	if testSchema != nil {
+		return testSchema
+	}
+	testSchema = schema.New(unit.Driver(), unit.DSN())
+	return testSchema
+}
+
+func getTestQuery() query.Query {
+	defer unit.Catch()
+	unit.SetLogger()
+	if testQuery != nil {
+		return testQuery
+	}
+	testQuery = query.New(unit.Driver(), unit.DSN())
+	return testQuery
+}
+
+func TestMigrationMigrate(t *testing.T) {
+	defer unit.Catch()
+	migrator := NewMigratorForMigrationTest()
+	sch := getTestSchema()
+
+	ran, err := migrator.Migrate()
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.Equal(t, []string{"create_users", "create_posts"}, ran, "the create_users and create_posts migrations should be ran")
+	assert.True(t, sch.MustHasTable("table_test_migration_users"), "the table_test_migration_users table should be created")
+	assert.True(t, sch.MustHasTable("table_test_migration_posts"), "the table_test_migration_posts table should be created")
+
+	// Nothing to migrate
+	ran = migrator.MustMigrate()
+	assert.Equal(t, 0, len(ran), "there should be no pending migrations")
+
+	// A new batch
+	migrator.Register("alter_posts", func(sch schema.Schema, qb query.Query) error {
+		return sch.AlterTable("table_test_migration_posts", func(table schema.Blueprint) {
+			table.String("title", 80)
+		})
+	}, func(sch schema.Schema, qb query.Query) error {
+		return sch.AlterTable("table_test_migration_posts", func(table schema.Blueprint) {
+			table.DropColumn("title")
+		})
+	})
+	ran = migrator.MustMigrate()
+	assert.Equal(t, []string{"alter_posts"}, ran, "the alter_posts migration should be ran")
+
+	status := migrator.MustStatus()
+	assert.Equal(t, 3, len(status), "the status should have 3 migrations")
+	assert.Equal(t, Status{Name: "create_users", Ran: true, Batch: 1}, status[0], "the create_users migration should be ran in the batch 1")
+	assert.Equal(t, Status{Name: "create_posts", Ran: true, Batch: 1}, status[1], "the create_posts migration should be ran in the batch 1")
+	assert.Equal(t, Status{Name: "alter_posts", Ran: true, Batch: 2}, status[2], "the alter_posts migration should be ran in the batch 2")
+}
+
+func TestMigrationRollback(t *testing.T) {
+	defer unit.Catch()
+	migrator := NewMigratorForMigrationTest()
+	sch := getTestSchema()
+	migrator.MustMigrate()
+	migrator.Register("create_tags", func(sch schema.Schema, qb query.Query) error {
+		return sch.CreateTable("table_test_migration_tags", func(table schema.Blueprint) {
+			table.ID("id")
+		})
+	}, func(sch schema.Schema, qb query.Query) error {
+		return sch.DropTable("table_test_migration_tags")
+	})
+	migrator.MustMigrate()
+
+	rolledBack, err := migrator.Rollback()
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.Equal(t, []string{"create_tags"}, rolledBack, "the create_tags migration should be rolled back")
+	assert.False(t, sch.MustHasTable("table_test_migration_tags"), "the table_test_migration_tags table should be dropped")
+	assert.True(t, sch.MustHasTable("table_test_migration_posts"), "the table_test_migration_posts table should be kept")
+
+	status := migrator.MustStatus()
+	assert.False(t, status[2].Ran, "the create_tags migration should not be ran")
+
+	migrator.MustMigrate()
+	rolledBack = migrator.MustRollback(2)
+	assert.Equal(t, []string{"create_tags", "create_posts", "create_users"}, rolledBack, "the migrations should be rolled back in the reverse order")
+	assert.False(t, sch.MustHasTable("table_test_migration_users"), "the table_test_migration_users table should be dropped")
+}
+
+func TestMigrationResetAndRefresh(t *testing.T) {
+	defer unit.Catch()
+	migrator := NewMigratorForMigrationTest()
+	sch := getTestSchema()
+	migrator.MustMigrate()
+	getTestQuery().New().Table("table_test_migration_users").MustInsert(map[string]interface{}{"name": "Max"})
+
+	ran, err := migrator.Refresh()
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.Equal(t, []string{"create_users", "create_posts"}, ran, "the migrations should be ran again")
+	count := getTestQuery().New().Table("table_test_migration_users").MustCount()
+	assert.Equal(t, int64(0), count, "the table_test_migration_users table should be recreated")
+
+	rolledBack := migrator.MustReset()
+	assert.Equal(t, []string{"create_posts", "create_users"}, rolledBack, "all of the migrations should be rolled back")
+	assert.False(t, sch.MustHasTable("table_test_migration_users"), "the table_test_migration_users table should be dropped")
+	assert.False(t, sch.MustHasTable("table_test_migration_posts"), "the table_test_migration_posts table should be dropped")
+	for _, status := range migrator.MustStatus() {
+		assert.False(t, status.Ran, "the migration %s should not be ran", status.Name)
+	}
+}
+
+func TestMigrationFail(t *testing.T) {
+	defer unit.Catch()
+	migrator := NewMigratorForMigrationTest()
+	sch := getTestSchema()
+	migrator.Register("create_tags", func(sch schema.Schema, qb query.Query) error {
+		err := sch.CreateTable("table_test_migration_tags", func(table schema.Blueprint) {
+			table.ID("id")
+		})
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("something wrong")
+	}, nil)
+
+	ran, err := migrator.Migrate()
+	assert.False(t, err == nil, "the return error should not be nil")
+	assert.Equal(t, []string{"create_users", "create_posts"}, ran, "the create_users and create_posts migrations should be ran")
+
+	status := migrator.MustStatus()
+	assert.False(t, status[2].Ran, "the create_tags migration should not be ran")
+	if migrator.IsTransactional() {
+		assert.False(t, sch.MustHasTable("table_test_migration_tags"), "the table_test_migration_tags table should be rolled back")
+	}
+
+	assert.Panics(t, func() {
+		migrator.Register("create_users", nil, nil)
+	}, "the duplicate migration should panic")
+}
+
+func TestMigrationRollbackFail(t *testing.T) {
+	defer unit.Catch()
+	migrator := NewMigratorForMigrationTest()
+	migrator.MustMigrate()
+
+	_, err := migrator.Rollback(0)
+	assert.Contains(t, err.Error(), "should be greater than 0", "the steps should be checked")
+	assert.True(t, migrator.MustStatus()[0].Ran, "the migrations should not be rolled back")
+
+	migrator = New(getTestSchema(), getTestQuery(), "table_test_migration_migrations")
+	_, err = migrator.Rollback()
+	assert.False(t, err == nil, "the return error should not be nil")
+}
+
+func TestMigrationWithContext(t *testing.T) {
+	defer unit.Catch()
+	migrator := NewMigratorForMigrationTest()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ran, err := migrator.WithContext(ctx).Migrate()
+	assert.False(t, err == nil, "the canceled context should stop the migrations")
+	assert.Equal(t, 0, len(ran), "there should be no migrations ran")
+	assert.False(t, getTestSchema().MustHasTable("table_test_migration_users"), "the table_test_migration_users table should not be created")
+}
+
+func TestMigrationRebuildWithForeignKeys(t *testing.T) {
+	defer unit.Catch()
+	if !unit.DriverIs("sqlite3") {
+		return
+	}
+
+	// the foreign keys checking is enabled by the DSN
+	dsn := unit.DSN() + "?_foreign_keys=1"
+	if strings.Contains(unit.DSN(), "?") {
+		dsn = unit.DSN() + "&_foreign_keys=1"
+	}
+	sch := schema.New(unit.Driver(), dsn)
+	qb := query.New(unit.Driver(), dsn)
+	NewMigratorForMigrationTest()
+
+	migrator := New(sch, qb, "table_test_migration_migrations")
+	migrator.Register("create_users", func(sch schema.Schema, qb query.Query) error {
+		return sch.CreateTable("table_test_migration_users", func(table schema.Blueprint) {
+			table.ID("id")
+			table.String("name", 80)
+		})
+	}, nil)
+	migrator.Register("create_posts", func(sch schema.Schema, qb query.Query) error {
+		return sch.CreateTable("table_test_migration_posts", func(table schema.Blueprint) {
+			table.ID("id")
+			table.ForeignID("user_id")
+			table.Foreign("user_id").References("id").On("table_test_migration_users").OnDelete("cascade")
+		})
+	}, nil)
+	migrator.MustMigrate()
+
+	enabled := 0
+	err := sch.MustGetDB().Get(&enabled, "PRAGMA foreign_keys")
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.Equal(t, 1, enabled, "the foreign keys checking should be enabled")
+
+	qb.New().Table("table_test_migration_users").MustInsert(map[string]interface{}{"name": "Max"})
+	qb.New().Table("table_test_migration_posts").MustInsert(map[string]interface{}{"user_id": 1})
+
+	// adding a check constraint rebuilds the users table
+	migrator.Register("alter_users", func(sch schema.Schema, qb query.Query) error {
+		return sch.AlterTable("table_test_migration_users", func(table schema.Blueprint) {
+			table.AddCheck("name_length", "length(name) > 0")
+		})
+	}, nil)
+	ran, err := migrator.Migrate()
+	assert.Equal(t, nil, err, "the return error should be nil")
+	assert.Equal(t, []string{"alter_users"}, ran, "the alter_users migration should be ran")
+	assert.NotNil(t, sch.MustGetTable("table_test_migration_users").GetCheck("name_length"), "the check constraint should be created")
+	assert.Equal(t, int64(1), qb.New().Table("table_test_migration_posts").MustCount(), "the rows of the child table should be kept")
+}
+
+// clean the test data
+func TestMigrationClean(t *testing.T) {
+	sch := getTestSchema()
+	sch.DropTableIfExists("table_test_migration_migrations")
+	sch.DropTableIfExists("table_test_migration_users")
+	sch.DropTableIfExists("table_test_migration_posts")
+	sch.DropTableIfExists("table_test_migration_tags")
+}
+
+func NewMigratorForMigrationTest() *Migrator {
+	defer unit.Catch()
+	sch := getTestSchema()
+	sch.DropTableIfExists("table_test_migration_migrations")
+	sch.DropTableIfExists("table_test_migration_users")
+	sch.DropTableIfExists("table_test_migration_posts")
+	sch.DropTableIfExists("table_test_migration_tags")
+
+	migrator := New(sch, getTestQuery(), "table_test_migration_migrations")
+	migrator.Register("create_users", func(sch schema.Schema, qb query.Query) error {
+		return sch.CreateTable("table_test_migration_users", func(table schema.Blueprint) {
+			table.ID("id")
+			table.String("name", 80)
+		})
+	}, func(sch schema.Schema, qb query.Query) error {
+		return sch.DropTable("table_test_migration_users")
+	})
+	migrator.Register("create_posts", func(sch schema.Schema, qb query.Query) error {
+		return sch.CreateTable("table_test_migration_posts", func(table schema.Blueprint) {
+			table.ID("id")
+			table.String("body", 200)
+		})
+	}, func(sch schema.Schema, qb query.Query) error {
+		return sch.DropTable("table_test_migration_posts")
+	})
+	return migrator
+}
@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/xun/dbal/schema"
+)
+
+// Handler the up or down closure of a migration. The schema and query builders are bound to the transaction of the migration if the driver supports transactional DDL.
+type Handler func(schema schema.Schema, qb query.Query) error
+
+// Migration the registered migration
+type Migration struct {
+	Name string
+	Up   Handler
+	Down Handler
+}
+
+// Migrator the migration runner
+type Migrator struct {
+	Schema     schema.Schema
+	Query      query.Query
+	Table      string
+	Migrations []*Migration
+}
+
+// Status the status of a registered migration
+type Status struct {
+	Name  string
+	Ran   bool
+	Batch int
+}
+
+// Record the record of the migrations table
+type Record struct {
+	ID    int    `json:"id"`
+	Name  string `json:"migration"`
+	Batch int    `json:"batch"`
+}
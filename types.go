@@ -25,6 +25,8 @@ type P struct {
 	NextPage     int                    `json:"next_page"`
 	PreviousPage int                    `json:"previous_page"`
 	LastPage     int                    `json:"last_page"`
+	NextCursor   string                 `json:"next_cursor,omitempty"`
+	PrevCursor   string                 `json:"prev_cursor,omitempty"`
 	Options      map[string]interface{} `json:"options,omtempty"`
 }
 
@@ -207,6 +207,26 @@ func MakeP(total int, pageSize int, currentPage int, items ...interface{}) P {
 
 }
 
+// MakeCursorP create a new P struct of the cursor paginator, the total and the pages are not counted.
+func MakeCursorP(pageSize int, nextCursor string, prevCursor string, items ...interface{}) P {
+	if pageSize < 1 {
+		pageSize = 15
+	}
+
+	if items == nil {
+		items = []interface{}{}
+	}
+
+	return P{
+		Items:        items,
+		PageSize:     pageSize,
+		NextPage:     -1,
+		PreviousPage: -1,
+		NextCursor:   nextCursor,
+		PrevCursor:   prevCursor,
+	}
+}
+
 // Value get the value of the given key ( alias Get)
 func (row R) Value(key interface{}) interface{} {
 	return row.Get(key)